@@ -105,6 +105,18 @@ func runPluginCommand(command func(commandLine utils.CommandLine) error) func(co
 	}
 }
 
+func runAlertingCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		cmd := &utils.ContextCommandLine{Context: context}
+		if err := command(cmd); err != nil {
+			return err
+		}
+
+		logger.Info("\n")
+		return nil
+	}
+}
+
 // Command contains command state.
 type Command struct {
 	Client utils.ApiClient
@@ -258,6 +270,39 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var alertingCommands = []*cli.Command{
+	{
+		Name:   "import-prometheus-rules",
+		Usage:  "import-prometheus-rules <rule file> [<rule file> ...]",
+		Action: runAlertingCommand(importPrometheusRulesCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "url",
+				Usage:   "URL of the Grafana instance",
+				Value:   "http://localhost:3000",
+				EnvVars: []string{"GF_ALERTING_IMPORT_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "Service account token used to authenticate with the Grafana instance",
+				EnvVars: []string{"GF_ALERTING_IMPORT_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:  "folder",
+				Usage: "Title of the folder the rule groups are imported into",
+			},
+			&cli.StringFlag{
+				Name:  "datasource-uid",
+				Usage: "UID of the Prometheus data source that evaluates the expressions of the rules",
+			},
+			&cli.BoolFlag{
+				Name:  "commit",
+				Usage: "Save the changes. Without it, the changes are only shown",
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -269,4 +314,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana Alerting commands",
+		Subcommands: alertingCommands,
+	},
 }
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const importPrometheusRulesPath = "/api/ruler/grafana/api/v1/import/prometheus"
+
+// importPrometheusRulesCommand reads Prometheus rule files and imports their rule groups into a Grafana instance
+// as Grafana-managed alert rules. Unless the changes are committed, it only shows them.
+func importPrometheusRulesCommand(c utils.CommandLine) error {
+	files := c.Args().Slice()
+	if len(files) == 0 {
+		return errors.New("at least one rule file must be specified")
+	}
+	if c.String("url") == "" {
+		return errors.New("the URL of the Grafana instance must be specified")
+	}
+	if c.String("folder") == "" {
+		return errors.New("the folder must be specified")
+	}
+	if c.String("datasource-uid") == "" {
+		return errors.New("the UID of the Prometheus data source must be specified")
+	}
+
+	body := apimodels.PostablePrometheusRulesImport{
+		Namespace:     c.String("folder"),
+		DatasourceUID: c.String("datasource-uid"),
+		Commit:        c.Bool("commit"),
+	}
+	for _, file := range files {
+		groups, err := readPrometheusRuleFile(file)
+		if err != nil {
+			return err
+		}
+		body.Groups = append(body.Groups, groups...)
+	}
+
+	result, err := importPrometheusRules(&services.HttpClientNoTimeout, c.String("url"), c.String("token"), body)
+	if err != nil {
+		return err
+	}
+
+	logger.Info(formatPrometheusRulesImportResult(result))
+	if result.DryRun {
+		logger.Info(color.YellowString("Dry run: no changes were saved. Run the command with --commit to import the rules.\n"))
+	} else {
+		logger.Info(color.GreenString("Rules imported successfully.\n"))
+	}
+	return nil
+}
+
+func readPrometheusRuleFile(path string) ([]apimodels.PrometheusRuleGroup, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is provided by the user running the command
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule file %s: %w", path, err)
+	}
+	var file apimodels.PrometheusRuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rule file %s: %w", path, err)
+	}
+	return file.Groups, nil
+}
+
+func importPrometheusRules(client *http.Client, grafanaURL, token string, body apimodels.PostablePrometheusRulesImport) (apimodels.PrometheusRulesImportResult, error) {
+	var result apimodels.PrometheusRulesImportResult
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(grafanaURL, "/")+importPrometheusRulesPath, bytes.NewReader(payload))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return result, fmt.Errorf("failed to send the rules to Grafana: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Warnf("Failed to close response body: %s\n", err)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return result, fmt.Errorf("failed to read the response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Message != "" {
+			return result, fmt.Errorf("failed to import the rules: %s: %s", resp.Status, errResp.Message)
+		}
+		return result, fmt.Errorf("failed to import the rules: %s", resp.Status)
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return result, fmt.Errorf("failed to parse the response: %w", err)
+	}
+	return result, nil
+}
+
+// formatPrometheusRulesImportResult returns the changes made by an import as a diff of the rule groups.
+func formatPrometheusRulesImportResult(result apimodels.PrometheusRulesImportResult) string {
+	var b strings.Builder
+	for _, group := range result.Groups {
+		fmt.Fprintf(&b, "Rule group %s:\n", group.Name)
+		if len(group.Created)+len(group.Updated)+len(group.Deleted) == 0 {
+			b.WriteString("  no changes\n")
+			continue
+		}
+		for _, rule := range group.Created {
+			b.WriteString(color.GreenString("  + %s\n", rule.Title))
+		}
+		for _, rule := range group.Updated {
+			b.WriteString(color.YellowString("  ~ %s (%s): %s\n", rule.Title, rule.UID, strings.Join(rule.Diff, ", ")))
+		}
+		for _, rule := range group.Deleted {
+			b.WriteString(color.RedString("  - %s (%s)\n", rule.Title, rule.UID))
+		}
+	}
+	return b.String()
+}
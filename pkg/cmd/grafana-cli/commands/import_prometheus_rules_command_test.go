@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestReadPrometheusRuleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+groups:
+  - name: node
+    interval: 1m
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+        labels:
+          severity: critical
+        annotations:
+          summary: "{{ $labels.instance }} is down"
+      - record: job:up:sum
+        expr: sum by (job) (up)
+`), 0600))
+
+	groups, err := readPrometheusRuleFile(path)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Equal(t, "node", groups[0].Name)
+	require.Equal(t, "1m", groups[0].Interval.String())
+	require.Len(t, groups[0].Rules, 2)
+	require.Equal(t, "InstanceDown", groups[0].Rules[0].Alert)
+	require.Equal(t, "5m", groups[0].Rules[0].For.String())
+	require.Equal(t, map[string]string{"severity": "critical"}, groups[0].Rules[0].Labels)
+	require.Equal(t, "job:up:sum", groups[0].Rules[1].Record)
+}
+
+func TestImportPrometheusRules(t *testing.T) {
+	body := apimodels.PostablePrometheusRulesImport{
+		Namespace:     "Prometheus",
+		DatasourceUID: "prom",
+		Groups:        []apimodels.PrometheusRuleGroup{{Name: "node"}},
+	}
+
+	t.Run("sends the rules and returns the result", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, importPrometheusRulesPath, r.URL.Path)
+			require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			var received apimodels.PostablePrometheusRulesImport
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			require.Equal(t, body, received)
+
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"dryRun": true, "groups": [{"name": "node", "created": [{"title": "InstanceDown"}]}]}`))
+		}))
+		defer server.Close()
+
+		result, err := importPrometheusRules(server.Client(), server.URL+"/", "secret", body)
+		require.NoError(t, err)
+		require.True(t, result.DryRun)
+		require.Equal(t, []apimodels.PrometheusRuleImportChange{{Title: "InstanceDown"}}, result.Groups[0].Created)
+	})
+
+	t.Run("returns the error message of the server", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "invalid rule group node"}`))
+		}))
+		defer server.Close()
+
+		_, err := importPrometheusRules(server.Client(), server.URL, "", body)
+		require.EqualError(t, err, "failed to import the rules: 400 Bad Request: invalid rule group node")
+	})
+}
+
+func TestFormatPrometheusRulesImportResult(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	result := apimodels.PrometheusRulesImportResult{
+		Groups: []apimodels.PrometheusRuleGroupImportResult{
+			{
+				Name:    "node",
+				Created: []apimodels.PrometheusRuleImportChange{{Title: "HighLoad"}},
+				Updated: []apimodels.PrometheusRuleImportChange{{UID: "uid-1", Title: "InstanceDown", Diff: []string{"Data", "For"}}},
+				Deleted: []apimodels.PrometheusRuleImportChange{{UID: "uid-2", Title: "Obsolete"}},
+			},
+			{Name: "unchanged"},
+		},
+	}
+	require.Equal(t, `Rule group node:
+  + HighLoad
+  ~ InstanceDown (uid-1): Data, For
+  - Obsolete (uid-2)
+Rule group unchanged:
+  no changes
+`, formatPrometheusRulesImportResult(result))
+}
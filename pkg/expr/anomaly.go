@@ -0,0 +1,145 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+const (
+	// AnomalyOutputOutlier returns a series per input series that is 1 for anomalous points and 0 otherwise.
+	AnomalyOutputOutlier = "outlier"
+	// AnomalyOutputBand returns the lower and the upper bound of the expected values per input series.
+	AnomalyOutputBand = "band"
+
+	defaultAnomalySensitivity = 3
+)
+
+var (
+	supportedAnomalyAlgorithms = []string{mathexp.AnomalyZScore, mathexp.AnomalyMAD, mathexp.AnomalySeasonal}
+)
+
+// AnomalyCommand is an expression command that detects anomalies in time series by comparing
+// each point with a baseline that is computed from the series itself.
+type AnomalyCommand struct {
+	InputVar    string
+	Algorithm   string
+	Output      string
+	Sensitivity float64
+	Season      time.Duration
+	refID       string
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand.
+func NewAnomalyCommand(refID, inputVar, algorithm, output string, sensitivity float64, season time.Duration) (*AnomalyCommand, error) {
+	if !mathexp.IsSupportedAnomalyAlgorithm(algorithm) {
+		return nil, fmt.Errorf("expected anomaly algorithm to be one of %s, got %s", strings.Join(supportedAnomalyAlgorithms, ", "), algorithm)
+	}
+	if output != AnomalyOutputOutlier && output != AnomalyOutputBand {
+		return nil, fmt.Errorf("expected anomaly output to be one of %s, %s, got %s", AnomalyOutputOutlier, AnomalyOutputBand, output)
+	}
+	if sensitivity <= 0 {
+		return nil, fmt.Errorf("anomaly sensitivity must be greater than zero, got %v", sensitivity)
+	}
+	if algorithm == mathexp.AnomalySeasonal && season <= 0 {
+		return nil, errors.New("no season specified for seasonal anomaly detection")
+	}
+	return &AnomalyCommand{
+		InputVar:    inputVar,
+		Algorithm:   algorithm,
+		Output:      output,
+		Sensitivity: sensitivity,
+		Season:      season,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	rawVar, ok := rn.Query["expression"]
+	if !ok {
+		return nil, errors.New("no expression ID is specified to detect anomalies in. Must be a reference to an existing query or expression")
+	}
+	inputVar, ok := rawVar.(string)
+	if !ok {
+		return nil, fmt.Errorf("expression ID is expected to be a string, got %T", rawVar)
+	}
+	inputVar = strings.TrimPrefix(inputVar, "$")
+
+	algorithm := mathexp.AnomalyZScore
+	if rawAlgorithm, ok := rn.Query["algorithm"]; ok {
+		if algorithm, ok = rawAlgorithm.(string); !ok {
+			return nil, fmt.Errorf("expected anomaly algorithm to be a string, got %T", rawAlgorithm)
+		}
+	}
+
+	output := AnomalyOutputOutlier
+	if rawOutput, ok := rn.Query["output"]; ok {
+		if output, ok = rawOutput.(string); !ok {
+			return nil, fmt.Errorf("expected anomaly output to be a string, got %T", rawOutput)
+		}
+	}
+
+	sensitivity := float64(defaultAnomalySensitivity)
+	if rawSensitivity, ok := rn.Query["sensitivity"]; ok {
+		if sensitivity, ok = rawSensitivity.(float64); !ok {
+			return nil, fmt.Errorf("expected anomaly sensitivity to be a number, got %T", rawSensitivity)
+		}
+	}
+
+	var season time.Duration
+	if rawSeason, ok := rn.Query["season"]; ok && rawSeason != "" {
+		seasonStr, ok := rawSeason.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected anomaly season to be a string, got %T", rawSeason)
+		}
+		var err error
+		season, err = gtime.ParseDuration(seasonStr)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse anomaly "season" duration field %q: %w`, seasonStr, err)
+		}
+	}
+
+	return NewAnomalyCommand(rn.RefID, inputVar, algorithm, output, sensitivity, season)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AnomalyCommand) NeedsVars() []string {
+	return []string{ac.InputVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AnomalyCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars) (mathexp.Results, error) {
+	newRes := mathexp.Results{}
+	for _, val := range vars[ac.InputVar].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			if ac.Output == AnomalyOutputBand {
+				lower, upper, err := v.AnomalyBand(ac.refID, ac.Algorithm, ac.Sensitivity, ac.Season)
+				if err != nil {
+					return newRes, err
+				}
+				newRes.Values = append(newRes.Values, lower, upper)
+				continue
+			}
+			outliers, err := v.Outliers(ac.refID, ac.Algorithm, ac.Sensitivity, ac.Season)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, outliers)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
@@ -0,0 +1,138 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	cases := []struct {
+		description   string
+		query         string
+		expected      *AnomalyCommand
+		expectedError string
+	}{
+		{
+			description: "defaults",
+			query:       `{"expression": "$A", "type": "anomaly"}`,
+			expected: &AnomalyCommand{
+				InputVar:    "A",
+				Algorithm:   mathexp.AnomalyZScore,
+				Output:      AnomalyOutputOutlier,
+				Sensitivity: 3,
+				refID:       "B",
+			},
+		},
+		{
+			description: "seasonal band",
+			query:       `{"expression": "A", "type": "anomaly", "algorithm": "seasonal", "output": "band", "sensitivity": 2.5, "season": "1d"}`,
+			expected: &AnomalyCommand{
+				InputVar:    "A",
+				Algorithm:   mathexp.AnomalySeasonal,
+				Output:      AnomalyOutputBand,
+				Sensitivity: 2.5,
+				Season:      24 * time.Hour,
+				refID:       "B",
+			},
+		},
+		{
+			description:   "missing expression",
+			query:         `{"type": "anomaly"}`,
+			expectedError: "no expression ID",
+		},
+		{
+			description:   "unsupported algorithm",
+			query:         `{"expression": "A", "type": "anomaly", "algorithm": "magic"}`,
+			expectedError: "expected anomaly algorithm to be one of",
+		},
+		{
+			description:   "unsupported output",
+			query:         `{"expression": "A", "type": "anomaly", "output": "score"}`,
+			expectedError: "expected anomaly output to be one of",
+		},
+		{
+			description:   "negative sensitivity",
+			query:         `{"expression": "A", "type": "anomaly", "sensitivity": -1}`,
+			expectedError: "sensitivity must be greater than zero",
+		},
+		{
+			description:   "seasonal without season",
+			query:         `{"expression": "A", "type": "anomaly", "algorithm": "seasonal"}`,
+			expectedError: "no season specified",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(tc.query), &qmap))
+
+			cmd, err := UnmarshalAnomalyCommand(&rawNode{
+				RefID: "B",
+				Query: qmap,
+			})
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cmd)
+		})
+	}
+}
+
+func TestAnomalyCommand_Execute(t *testing.T) {
+	values := []float64{10, 11, 9, 10, 12, 10, 50, 11, 9, 10}
+	series := mathexp.NewSeries("A", data.Labels{"host": "a"}, len(values))
+	for i, v := range values {
+		v := v
+		series.SetPoint(i, time.Unix(int64(i*60), 0), &v)
+	}
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{series}}}
+
+	t.Run("should return outliers", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", mathexp.AnomalyMAD, AnomalyOutputOutlier, 3, 0)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		outliers := res.Values[0].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "a"}, outliers.GetLabels())
+		for i := 0; i < outliers.Len(); i++ {
+			expected := 0.0
+			if i == 6 {
+				expected = 1
+			}
+			require.Equal(t, expected, *outliers.GetValue(i), "point %d", i)
+		}
+	})
+
+	t.Run("should return band", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", mathexp.AnomalyZScore, AnomalyOutputBand, 1, 0)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, data.Labels{"host": "a", mathexp.AnomalyBandLabel: "lower"}, res.Values[0].GetLabels())
+		require.Equal(t, data.Labels{"host": "a", mathexp.AnomalyBandLabel: "upper"}, res.Values[1].GetLabels())
+	})
+
+	t.Run("should pass through no data", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", mathexp.AnomalyZScore, AnomalyOutputOutlier, 3, 0)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NoData{}.New()}}})
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, mathexp.NoData{}.New(), res.Values[0])
+	})
+}
@@ -15,8 +15,11 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "stddev", "variance", "range", "rate":
+		return true
 	}
-	return false
+	_, ok := mathexp.ParsePercentile(string(cr))
+	return ok
 }
 
 //nolint:gocyclo
@@ -144,6 +147,10 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 		if value > 0 {
 			allNull = false
 		}
+	default:
+		// first, stddev, variance, range, rate and percentiles are calculated by the
+		// server-side expressions reducers over all points that are not null or NaN.
+		allNull, value = reduceNonNull(series, string(cr))
 	}
 
 	if allNull {
@@ -182,6 +189,29 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+func reduceNonNull(series mathexp.Series, rFunc string) (bool, float64) {
+	reduceFunc, err := mathexp.GetReduceFunc(rFunc)
+	if err != nil {
+		return true, 0
+	}
+	nonNull := mathexp.NewSeries(series.GetName(), series.GetLabels(), 0)
+	for i := 0; i < series.Len(); i++ {
+		f := series.GetValue(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		nonNull.AppendPoint(series.GetTime(i), f)
+	}
+	if nonNull.Len() == 0 {
+		return true, 0
+	}
+	f := reduceFunc(nonNull)
+	if nilOrNaN(f) {
+		return true, 0
+	}
+	return false, *f
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
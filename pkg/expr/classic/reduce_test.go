@@ -115,6 +115,60 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first should ignore null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, ptr.Float64(2), ptr.Float64(3)),
+			expectedNumber: newNumber(ptr.Float64(2)),
+		},
+		{
+			name:           "first with only nulls",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(ptr.Float64(2), ptr.Float64(4), ptr.Float64(4), ptr.Float64(4), ptr.Float64(5), ptr.Float64(5), ptr.Float64(7), ptr.Float64(9)),
+			expectedNumber: newNumber(ptr.Float64(2)),
+		},
+		{
+			name:           "variance should ignore null values",
+			reducer:        reducer("variance"),
+			inputSeries:    newSeries(ptr.Float64(2), ptr.Float64(4), nil, ptr.Float64(4), ptr.Float64(4), ptr.Float64(5), ptr.Float64(5), ptr.Float64(7), ptr.Float64(9)),
+			expectedNumber: newNumber(ptr.Float64(4)),
+		},
+		{
+			name:           "range",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(ptr.Float64(1), nil, ptr.Float64(5), ptr.Float64(3)),
+			expectedNumber: newNumber(ptr.Float64(4)),
+		},
+		{
+			name:           "percentile",
+			reducer:        reducer("p75"),
+			inputSeries:    newSeries(ptr.Float64(5), ptr.Float64(1), ptr.Float64(4), ptr.Float64(2), ptr.Float64(3)),
+			expectedNumber: newNumber(ptr.Float64(4)),
+		},
+		{
+			name:           "percentile between values",
+			reducer:        reducer("p25"),
+			inputSeries:    newSeries(ptr.Float64(1), ptr.Float64(2), nil, ptr.Float64(3), ptr.Float64(4)),
+			expectedNumber: newNumber(ptr.Float64(1.75)),
+		},
+		{
+			name:           "rate should ignore null values",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(ptr.Float64(0), nil, ptr.Float64(4)),
+			expectedNumber: newNumber(ptr.Float64(2)),
+		},
+		{
+			name:           "rate with one value",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(nil, ptr.Float64(4)),
+			expectedNumber: newNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +180,15 @@ func TestReducer(t *testing.T) {
 	}
 }
 
+func TestValidReduceFunc(t *testing.T) {
+	for _, r := range []string{"first", "stddev", "variance", "range", "rate", "p50", "p99.9"} {
+		require.Truef(t, reducer(r).ValidReduceFunc(), "reducer %s should be valid", r)
+	}
+	for _, r := range []string{"", "mean", "p101", "foo"} {
+		require.Falsef(t, reducer(r).ValidReduceFunc(), "reducer %s should not be valid", r)
+	}
+}
+
 func TestDiffReducer(t *testing.T) {
 	var tests = []struct {
 		name           string
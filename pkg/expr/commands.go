@@ -302,6 +302,8 @@ const (
 	TypeClassicConditions
 	// TypeThreshold is the CMDType for checking if a threshold has been crossed
 	TypeThreshold
+	// TypeAnomaly is the CMDType for detecting anomalies in time series.
+	TypeAnomaly
 )
 
 func (gt CommandType) String() string {
@@ -314,6 +316,8 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeAnomaly:
+		return "anomaly"
 	default:
 		return "unknown"
 	}
@@ -332,6 +336,8 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeClassicConditions, nil
 	case "threshold":
 		return TypeThreshold, nil
+	case "anomaly":
+		return TypeAnomaly, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -0,0 +1,175 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// aggregationGroup holds the values that share the same labels after applying the grouping of an aggregation.
+type aggregationGroup struct {
+	labels data.Labels
+	values []Value
+}
+
+// walkAggregate groups the numbers or series of the argument by the labels of the aggregation and
+// combines the values of each group into a single number or series. Series are combined point by
+// point for each timestamp that exists in at least one series of the group.
+func (e *State) walkAggregate(node *parse.AggregateNode) (Results, error) {
+	res, err := e.walk(node.Arg)
+	if err != nil {
+		return Results{}, err
+	}
+
+	var groups []*aggregationGroup
+	byKey := map[string]*aggregationGroup{}
+	noData := false
+	for _, val := range res.Values {
+		switch val.(type) {
+		case Number, Series:
+		case NoData:
+			noData = true
+			continue
+		default:
+			return Results{}, fmt.Errorf("can not perform aggregation %s on type %v", node.Op, val.Type())
+		}
+		labels := groupingLabels(val.GetLabels(), node)
+		key := labels.String()
+		g, ok := byKey[key]
+		if !ok {
+			g = &aggregationGroup{labels: labels}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.values = append(g.values, val)
+	}
+
+	newRes := Results{}
+	if len(groups) == 0 {
+		if noData {
+			newRes.Values = append(newRes.Values, NewNoData())
+		}
+		return newRes, nil
+	}
+	for _, g := range groups {
+		var newVal Value
+		switch g.values[0].(type) {
+		case Number:
+			newVal, err = e.aggregateNumbers(node.Op, g)
+		case Series:
+			newVal, err = e.aggregateSeries(node.Op, g)
+		}
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+func (e *State) aggregateNumbers(op string, g *aggregationGroup) (Number, error) {
+	var values []float64
+	for _, val := range g.values {
+		n, ok := val.(Number)
+		if !ok {
+			return Number{}, fmt.Errorf("can not perform aggregation %s on mixed numbers and series with labels %s", op, g.labels)
+		}
+		if f := n.GetFloat64Value(); f != nil {
+			values = append(values, *f)
+		}
+	}
+	newNumber := NewNumber(e.RefID, g.labels)
+	newNumber.SetValue(aggregateOp(op, values))
+	return newNumber, nil
+}
+
+func (e *State) aggregateSeries(op string, g *aggregationGroup) (Series, error) {
+	var times []time.Time
+	points := map[int64][]float64{}
+	for _, val := range g.values {
+		s, ok := val.(Series)
+		if !ok {
+			return Series{}, fmt.Errorf("can not perform aggregation %s on mixed numbers and series with labels %s", op, g.labels)
+		}
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			key := t.UnixNano()
+			values, ok := points[key]
+			if !ok {
+				times = append(times, t)
+				values = []float64{}
+			}
+			if f != nil {
+				values = append(values, *f)
+			}
+			points[key] = values
+		}
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	newSeries := NewSeries(e.RefID, g.labels, len(times))
+	for i, t := range times {
+		newSeries.SetPoint(i, t, aggregateOp(op, points[t.UnixNano()]))
+	}
+	return newSeries, nil
+}
+
+// groupingLabels returns the labels that identify the group of a value.
+func groupingLabels(labels data.Labels, node *parse.AggregateNode) data.Labels {
+	result := data.Labels{}
+	if node.Without {
+		for name, value := range labels {
+			result[name] = value
+		}
+		for _, name := range node.Grouping {
+			delete(result, name)
+		}
+		return result
+	}
+	for _, name := range node.Grouping {
+		if value, ok := labels[name]; ok {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// aggregateOp combines the non-null values of a group. It returns nil if there are no values, except for count.
+func aggregateOp(op string, values []float64) *float64 {
+	if op == "count" {
+		f := float64(len(values))
+		return &f
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	var f float64
+	switch op {
+	case "sum", "avg":
+		for _, v := range values {
+			f += v
+		}
+		if op == "avg" {
+			f /= float64(len(values))
+		}
+	case "min":
+		f = math.Inf(1)
+		for _, v := range values {
+			f = math.Min(f, v)
+		}
+	case "max":
+		f = math.Inf(-1)
+		for _, v := range values {
+			f = math.Max(f, v)
+		}
+	default:
+		f = math.NaN()
+	}
+	return &f
+}
@@ -0,0 +1,154 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateExpr(t *testing.T) {
+	hosts := Vars{
+		"A": Results{
+			[]Value{
+				makeSeries("", data.Labels{"service": "api", "host": "a"},
+					tp{time.Unix(5, 0), float64Pointer(1)},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+				),
+				makeSeries("", data.Labels{"service": "api", "host": "b"},
+					tp{time.Unix(5, 0), float64Pointer(3)},
+					tp{time.Unix(10, 0), nil},
+				),
+				makeSeries("", data.Labels{"service": "db", "host": "c"},
+					tp{time.Unix(10, 0), float64Pointer(7)},
+				),
+			},
+		},
+		"B": Results{
+			[]Value{
+				makeNumber("", data.Labels{"service": "api", "host": "a"}, float64Pointer(4)),
+				makeNumber("", data.Labels{"service": "api", "host": "b"}, float64Pointer(8)),
+				makeNumber("", data.Labels{"service": "db", "host": "c"}, nil),
+			},
+		},
+		"C": Results{
+			[]Value{
+				makeNumber("", data.Labels{"service": "api", "region": "eu"}, float64Pointer(2)),
+				makeNumber("", data.Labels{"service": "db", "region": "eu"}, float64Pointer(10)),
+			},
+		},
+	}
+
+	var tests = []struct {
+		name    string
+		expr    string
+		vars    Vars
+		results Results
+	}{
+		{
+			name: "sum by label on series",
+			expr: "sum by (service) ($A)",
+			vars: hosts,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"service": "api"},
+						tp{time.Unix(5, 0), float64Pointer(4)},
+						tp{time.Unix(10, 0), float64Pointer(2)},
+					),
+					makeSeries("", data.Labels{"service": "db"},
+						tp{time.Unix(10, 0), float64Pointer(7)},
+					),
+				},
+			},
+		},
+		{
+			name: "grouping after the argument",
+			expr: "max($A) by (service)",
+			vars: hosts,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"service": "api"},
+						tp{time.Unix(5, 0), float64Pointer(3)},
+						tp{time.Unix(10, 0), float64Pointer(2)},
+					),
+					makeSeries("", data.Labels{"service": "db"},
+						tp{time.Unix(10, 0), float64Pointer(7)},
+					),
+				},
+			},
+		},
+		{
+			name: "avg without label on numbers",
+			expr: "avg without (host) ($B)",
+			vars: hosts,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{"service": "api"}, float64Pointer(6)),
+					makeNumber("", data.Labels{"service": "db"}, nil),
+				},
+			},
+		},
+		{
+			name: "count without grouping aggregates everything",
+			expr: "count($B)",
+			vars: hosts,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{}, float64Pointer(2)),
+				},
+			},
+		},
+		{
+			name: "aggregation in binary operation with on matching",
+			expr: "sum by (service) ($B) / on(service) $C",
+			vars: hosts,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{"service": "api", "region": "eu"}, float64Pointer(6)),
+					makeNumber("", data.Labels{"service": "db", "region": "eu"}, nil),
+				},
+			},
+		},
+		{
+			name: "ignoring matching keeps labels of the side with more labels",
+			expr: "$B > ignoring(host) sum by (service) ($B)",
+			vars: hosts,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{"service": "api", "host": "a"}, float64Pointer(0)),
+					makeNumber("", data.Labels{"service": "api", "host": "b"}, float64Pointer(0)),
+					makeNumber("", data.Labels{"service": "db", "host": "c"}, nil),
+				},
+			},
+		},
+		{
+			name:    "aggregation of no data is no data",
+			expr:    "sum by (service) ($A)",
+			vars:    Vars{"A": Results{[]Value{NewNoData()}}},
+			results: Results{[]Value{NewNoData()}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", tt.vars)
+			require.NoError(t, err)
+			require.Equal(t, tt.results, res)
+		})
+	}
+
+	t.Run("should fail to parse invalid aggregations", func(t *testing.T) {
+		for _, expr := range []string{
+			"sum by service ($A)",
+			"sum by (service) $A",
+			"sum(1)",
+			"$A + on service $B",
+			"sum by (service) ($A) by (host)",
+		} {
+			_, err := New(expr)
+			require.Errorf(t, err, "expected error for %s", expr)
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// AnomalyZScore uses the mean and the standard deviation of the series as baseline.
+	AnomalyZScore = "zscore"
+	// AnomalyMAD uses the median and the median absolute deviation of the series as baseline.
+	AnomalyMAD = "mad"
+	// AnomalySeasonal uses the one step ahead forecast of additive Holt-Winters smoothing as baseline.
+	AnomalySeasonal = "seasonal"
+)
+
+// AnomalyBandLabel is the label that is added to the lower and upper bound series of an anomaly band.
+const AnomalyBandLabel = "band"
+
+// smoothing factors of the level, trend and seasonal components of the Holt-Winters model.
+const (
+	holtWintersAlpha = 0.5
+	holtWintersBeta  = 0.1
+	holtWintersGamma = 0.3
+)
+
+// madScale makes the median absolute deviation a consistent estimator of the standard deviation of normally distributed data.
+const madScale = 1.4826
+
+// IsSupportedAnomalyAlgorithm returns true if algorithm can be used to detect anomalies.
+func IsSupportedAnomalyAlgorithm(algorithm string) bool {
+	switch algorithm {
+	case AnomalyZScore, AnomalyMAD, AnomalySeasonal:
+		return true
+	default:
+		return false
+	}
+}
+
+// AnomalyBand returns the lower and upper bound of the expected values of the series. A value is
+// expected if it deviates from the baseline by at most sensitivity times the standard deviation estimated
+// by the algorithm. The returned series have the labels of the series and an additional band label.
+// The season is only used by the seasonal algorithm.
+func (s Series) AnomalyBand(refID, algorithm string, sensitivity float64, season time.Duration) (Series, Series, error) {
+	points, lower, upper, err := anomalyBounds(s, algorithm, sensitivity, season)
+	if err != nil {
+		return Series{}, Series{}, err
+	}
+	lowerSeries := NewSeries(refID, bandLabels(s.GetLabels(), "lower"), len(points))
+	upperSeries := NewSeries(refID, bandLabels(s.GetLabels(), "upper"), len(points))
+	for i, p := range points {
+		lowerSeries.SetPoint(i, p.t, lower[i])
+		upperSeries.SetPoint(i, p.t, upper[i])
+	}
+	return lowerSeries, upperSeries, nil
+}
+
+// Outliers returns a series that is 1 for each point of the series that is outside of the band returned
+// by AnomalyBand, and 0 otherwise. Points without a value stay null, and points without a baseline are 0.
+func (s Series) Outliers(refID, algorithm string, sensitivity float64, season time.Duration) (Series, error) {
+	points, lower, upper, err := anomalyBounds(s, algorithm, sensitivity, season)
+	if err != nil {
+		return Series{}, err
+	}
+	newSeries := NewSeries(refID, s.GetLabels(), len(points))
+	for i, p := range points {
+		if !hasValue(p.f) {
+			newSeries.SetPoint(i, p.t, nil)
+			continue
+		}
+		outlier := 0.0
+		if lower[i] != nil && upper[i] != nil && (*p.f < *lower[i] || *p.f > *upper[i]) {
+			outlier = 1
+		}
+		newSeries.SetPoint(i, p.t, &outlier)
+	}
+	return newSeries, nil
+}
+
+func bandLabels(labels data.Labels, band string) data.Labels {
+	result := labels.Copy()
+	if result == nil {
+		result = data.Labels{}
+	}
+	result[AnomalyBandLabel] = band
+	return result
+}
+
+// anomalyBounds returns the points of the series sorted by time and the lower and upper bound for each point.
+// The bounds are nil for points without a baseline.
+func anomalyBounds(s Series, algorithm string, sensitivity float64, season time.Duration) ([]seriesPoint, []*float64, []*float64, error) {
+	if sensitivity <= 0 {
+		return nil, nil, nil, fmt.Errorf("anomaly sensitivity must be greater than zero, got %v", sensitivity)
+	}
+	points := sortedPoints(s)
+	lower := make([]*float64, len(points))
+	upper := make([]*float64, len(points))
+
+	switch algorithm {
+	case AnomalyZScore, AnomalyMAD:
+		values := make([]float64, 0, len(points))
+		for _, p := range points {
+			if hasValue(p.f) {
+				values = append(values, *p.f)
+			}
+		}
+		if len(values) < 2 {
+			return points, lower, upper, nil
+		}
+		var baseline, deviation float64
+		if algorithm == AnomalyZScore {
+			baseline, deviation = meanStdDev(values)
+		} else {
+			baseline = median(values)
+			absDeviations := make([]float64, 0, len(values))
+			for _, v := range values {
+				absDeviations = append(absDeviations, math.Abs(v-baseline))
+			}
+			deviation = madScale * median(absDeviations)
+		}
+		for i := range points {
+			lower[i], upper[i] = bounds(baseline, sensitivity*deviation)
+		}
+	case AnomalySeasonal:
+		forecast, deviation, err := holtWinters(points, season)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for i, f := range forecast {
+			if f != nil {
+				lower[i], upper[i] = bounds(*f, sensitivity*deviation)
+			}
+		}
+	default:
+		return nil, nil, nil, fmt.Errorf("anomaly detection algorithm %q is not supported", algorithm)
+	}
+	return points, lower, upper, nil
+}
+
+// holtWinters returns the one step ahead forecast of additive Holt-Winters smoothing for each point and the
+// standard deviation of the forecast errors. The points are expected to have a regular interval. The first
+// season is used to initialize the model and has no forecast.
+func holtWinters(points []seriesPoint, season time.Duration) ([]*float64, float64, error) {
+	if season <= 0 {
+		return nil, 0, fmt.Errorf("seasonal anomaly detection requires a season")
+	}
+	if len(points) < 2 {
+		return nil, 0, fmt.Errorf("seasonal anomaly detection requires at least two seasons of data")
+	}
+	intervals := make([]float64, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		intervals = append(intervals, float64(points[i].t.Sub(points[i-1].t)))
+	}
+	interval := time.Duration(median(intervals))
+	if interval <= 0 {
+		return nil, 0, fmt.Errorf("seasonal anomaly detection requires points with distinct time stamps")
+	}
+	period := int(math.Round(float64(season) / float64(interval)))
+	if period < 2 {
+		return nil, 0, fmt.Errorf("season %s must be at least two times the interval %s of the series", season, interval)
+	}
+	if len(points) < 2*period {
+		return nil, 0, fmt.Errorf("seasonal anomaly detection requires at least two seasons of data, got %d points for a season of %d points", len(points), period)
+	}
+
+	firstMean := meanOf(points[:period])
+	level := firstMean
+	trend := (meanOf(points[period:2*period]) - firstMean) / float64(period)
+	seasonal := make([]float64, period)
+	for i, p := range points[:period] {
+		if hasValue(p.f) {
+			seasonal[i] = *p.f - level
+		}
+	}
+
+	forecast := make([]*float64, len(points))
+	var squaredErrors float64
+	var errorCount int
+	for i := period; i < len(points); i++ {
+		idx := i % period
+		f := level + trend + seasonal[idx]
+		forecast[i] = &f
+		p := points[i]
+		if !hasValue(p.f) {
+			level += trend
+			continue
+		}
+		squaredErrors += (*p.f - f) * (*p.f - f)
+		errorCount++
+		prevLevel := level
+		level = holtWintersAlpha*(*p.f-seasonal[idx]) + (1-holtWintersAlpha)*(level+trend)
+		trend = holtWintersBeta*(level-prevLevel) + (1-holtWintersBeta)*trend
+		seasonal[idx] = holtWintersGamma*(*p.f-level) + (1-holtWintersGamma)*seasonal[idx]
+	}
+	if errorCount == 0 {
+		return forecast, 0, nil
+	}
+	return forecast, math.Sqrt(squaredErrors / float64(errorCount)), nil
+}
+
+func bounds(baseline, deviation float64) (*float64, *float64) {
+	lower, upper := baseline-deviation, baseline+deviation
+	return &lower, &upper
+}
+
+func hasValue(f *float64) bool {
+	return f != nil && !math.IsNaN(*f) && !math.IsInf(*f, 0)
+}
+
+// meanOf returns the mean of the points with a value.
+func meanOf(points []seriesPoint) float64 {
+	var sum float64
+	var count int
+	for _, p := range points {
+		if hasValue(p.f) {
+			sum += *p.f
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
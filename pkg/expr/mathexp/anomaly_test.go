@@ -0,0 +1,85 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesAnomalyBand(t *testing.T) {
+	t.Run("zscore", func(t *testing.T) {
+		s := makeSeries("", nil,
+			tp{time.Unix(0, 0), float64Pointer(2)},
+			tp{time.Unix(60, 0), float64Pointer(4)},
+			tp{time.Unix(120, 0), nil},
+			tp{time.Unix(180, 0), float64Pointer(6)},
+		)
+		lower, upper, err := s.AnomalyBand("B", AnomalyZScore, 2, 0)
+		require.NoError(t, err)
+		stdDev := math.Sqrt(8.0 / 3)
+		for i := 0; i < s.Len(); i++ {
+			require.InDelta(t, 4-2*stdDev, *lower.GetValue(i), 1e-9)
+			require.InDelta(t, 4+2*stdDev, *upper.GetValue(i), 1e-9)
+		}
+		require.Equal(t, "lower", lower.GetLabels()[AnomalyBandLabel])
+		require.Equal(t, "upper", upper.GetLabels()[AnomalyBandLabel])
+	})
+
+	t.Run("mad is robust against outliers", func(t *testing.T) {
+		s := makeSeries("", nil,
+			tp{time.Unix(0, 0), float64Pointer(1)},
+			tp{time.Unix(60, 0), float64Pointer(2)},
+			tp{time.Unix(120, 0), float64Pointer(3)},
+			tp{time.Unix(180, 0), float64Pointer(4)},
+			tp{time.Unix(240, 0), float64Pointer(1000)},
+		)
+		lower, upper, err := s.AnomalyBand("B", AnomalyMAD, 1, 0)
+		require.NoError(t, err)
+		require.InDelta(t, 3-madScale, *lower.GetValue(0), 1e-9)
+		require.InDelta(t, 3+madScale, *upper.GetValue(0), 1e-9)
+	})
+
+	t.Run("seasonal follows the season", func(t *testing.T) {
+		// a season of 4 points that repeats 6 times with an outlier in the last season
+		pattern := []float64{10, 20, 30, 20}
+		s := NewSeries("", nil, 0)
+		for i := 0; i < 24; i++ {
+			v := pattern[i%len(pattern)]
+			if i == 21 {
+				v = 60
+			}
+			s.AppendPoint(time.Unix(int64(i*60), 0), &v)
+		}
+		outliers, err := s.Outliers("B", AnomalySeasonal, 3, 4*time.Minute)
+		require.NoError(t, err)
+		for i := 0; i < outliers.Len(); i++ {
+			expected := 0.0
+			if i == 21 {
+				expected = 1
+			}
+			require.Equal(t, expected, *outliers.GetValue(i), "point %d", i)
+		}
+
+		lower, _, err := s.AnomalyBand("B", AnomalySeasonal, 3, 4*time.Minute)
+		require.NoError(t, err)
+		require.Nil(t, lower.GetValue(0), "first season has no forecast")
+		require.NotNil(t, lower.GetValue(4))
+	})
+
+	t.Run("seasonal requires two seasons", func(t *testing.T) {
+		s := makeSeries("", nil,
+			tp{time.Unix(0, 0), float64Pointer(1)},
+			tp{time.Unix(60, 0), float64Pointer(2)},
+			tp{time.Unix(120, 0), float64Pointer(3)},
+		)
+		_, err := s.Outliers("B", AnomalySeasonal, 3, 2*time.Minute)
+		require.ErrorContains(t, err, "at least two seasons")
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		_, err := makeSeries("", nil).Outliers("B", "magic", 3, 0)
+		require.Error(t, err)
+	})
+}
@@ -89,6 +89,8 @@ func (e *State) walk(node parse.Node) (res Results, err error) {
 		res, err = e.walkUnary(node)
 	case *parse.FuncNode:
 		res, err = e.walkFunc(node)
+	case *parse.AggregateNode:
+		res, err = e.walkAggregate(node)
 	default:
 		return res, fmt.Errorf("expr: can not walk node type: %s", node.Type())
 	}
@@ -253,6 +255,77 @@ func union(aResults, bResults Results) []*Union {
 	return unions
 }
 
+// unionMatching creates Union objects like union but only compares the labels selected by the matching
+// modifier of the binary operation. The labels of the Union are taken from the value with more labels,
+// so many-to-one matches keep the labels of the "many" side. If both values have the same number of labels,
+// only the labels used for matching are kept.
+func unionMatching(aResults, bResults Results, matching *parse.VectorMatching) []*Union {
+	unions := []*Union{}
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return unions
+	}
+	if len(aResults.Values) == 1 || len(bResults.Values) == 1 {
+		if aResults.Values[0].Type() == parse.TypeNoData || bResults.Values[0].Type() == parse.TypeNoData {
+			return append(unions, &Union{
+				A: aResults.Values[0],
+				B: bResults.Values[0],
+			})
+		}
+	}
+	for _, a := range aResults.Values {
+		for _, b := range bResults.Values {
+			aLabels := a.GetLabels()
+			bLabels := b.GetLabels()
+			var labels data.Labels
+			switch {
+			case len(aLabels) == 0:
+				labels = bLabels
+			case len(bLabels) == 0:
+				labels = aLabels
+			default:
+				aSignature := matchingLabels(aLabels, matching)
+				if !aSignature.Equals(matchingLabels(bLabels, matching)) {
+					continue
+				}
+				switch {
+				case len(aLabels) > len(bLabels):
+					labels = aLabels
+				case len(bLabels) > len(aLabels):
+					labels = bLabels
+				default:
+					labels = aSignature
+				}
+			}
+			unions = append(unions, &Union{
+				Labels: labels,
+				A:      a,
+				B:      b,
+			})
+		}
+	}
+	return unions
+}
+
+// matchingLabels returns the subset of labels that are used to match values with the given matching modifier.
+func matchingLabels(labels data.Labels, matching *parse.VectorMatching) data.Labels {
+	result := data.Labels{}
+	if matching.On {
+		for _, name := range matching.Labels {
+			if value, ok := labels[name]; ok {
+				result[name] = value
+			}
+		}
+		return result
+	}
+	for name, value := range labels {
+		result[name] = value
+	}
+	for _, name := range matching.Labels {
+		delete(result, name)
+	}
+	return result
+}
+
 func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	res := Results{Values{}}
 	ar, err := e.walk(node.Args[0])
@@ -263,7 +336,12 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := union(ar, br)
+	var unions []*Union
+	if node.Matching != nil {
+		unions = unionMatching(ar, br, node.Matching)
+	} else {
+		unions = union(ar, br)
+	}
 	for _, uni := range unions {
 		var value Value
 		switch at := uni.A.(type) {
@@ -515,12 +593,16 @@ func (e *State) walkFunc(node *parse.FuncNode) (Results, error) {
 			v = e.Vars[t.Name]
 		case *parse.ScalarNode:
 			v = NewScalarResults(e.RefID, &t.Float64)
+		case *parse.DurationNode:
+			v = t.Duration
 		case *parse.FuncNode:
 			v, err = e.walkFunc(t)
 		case *parse.UnaryNode:
 			v, err = e.walkUnary(t)
 		case *parse.BinaryNode:
 			v, err = e.walkBinary(t)
+		case *parse.AggregateNode:
+			v, err = e.walkAggregate(t)
 		default:
 			return res, fmt.Errorf("expr: unknown func arg type: %T", t)
 		}
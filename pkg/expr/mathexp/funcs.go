@@ -1,7 +1,10 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"sort"
+	"time"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +71,31 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"timeShift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeDuration},
+		Return: parse.TypeSeriesSet,
+		F:      timeShift,
+	},
+	"movingAvg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeDuration},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"cumulativeSum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumulativeSum,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +335,150 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// timeShift moves every point of each series forward in time by the given duration,
+// so that for example $A - timeShift($A, 1d) compares each point with the value one day earlier.
+func timeShift(e *State, varSet Results, d time.Duration) (Results, error) {
+	return perSeries(e, varSet, "timeShift", func(points []seriesPoint, newSeries Series) {
+		for _, p := range points {
+			newSeries.AppendPoint(p.t.Add(d), p.f)
+		}
+	})
+}
+
+// movingAvg returns for each point of each series the average of the non-null values
+// within the preceding window of the given duration, including the point itself.
+func movingAvg(e *State, varSet Results, window time.Duration) (Results, error) {
+	if window <= 0 {
+		return Results{}, fmt.Errorf("movingAvg: window must be greater than zero")
+	}
+	return perSeries(e, varSet, "movingAvg", func(points []seriesPoint, newSeries Series) {
+		start, sum, count := 0, 0.0, 0
+		for _, p := range points {
+			if p.f != nil {
+				sum += *p.f
+				count++
+			}
+			for ; !points[start].t.After(p.t.Add(-window)); start++ {
+				if points[start].f != nil {
+					sum -= *points[start].f
+					count--
+				}
+			}
+			var avg *float64
+			if count > 0 {
+				v := sum / float64(count)
+				avg = &v
+			}
+			newSeries.AppendPoint(p.t, avg)
+		}
+	})
+}
+
+// rate returns the per-second rate of increase between consecutive points of each series.
+// A decrease of the value is treated as a counter reset. The first point of each series is dropped.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "rate", func(points []seriesPoint, newSeries Series) {
+		perPointPair(points, newSeries, func(prev, cur seriesPoint) *float64 {
+			seconds := cur.t.Sub(prev.t).Seconds()
+			if seconds <= 0 {
+				return nil
+			}
+			diff := *cur.f - *prev.f
+			if diff < 0 { // counter reset
+				diff = *cur.f
+			}
+			v := diff / seconds
+			return &v
+		})
+	})
+}
+
+// delta returns the difference between consecutive points of each series.
+// The first point of each series is dropped.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "delta", func(points []seriesPoint, newSeries Series) {
+		perPointPair(points, newSeries, func(prev, cur seriesPoint) *float64 {
+			v := *cur.f - *prev.f
+			return &v
+		})
+	})
+}
+
+// cumulativeSum returns the running total of the values of each series. Null values are kept null and
+// do not change the total.
+func cumulativeSum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "cumulativeSum", func(points []seriesPoint, newSeries Series) {
+		sum := 0.0
+		for _, p := range points {
+			if p.f == nil {
+				newSeries.AppendPoint(p.t, nil)
+				continue
+			}
+			sum += *p.f
+			v := sum
+			newSeries.AppendPoint(p.t, &v)
+		}
+	})
+}
+
+type seriesPoint struct {
+	t time.Time
+	f *float64
+}
+
+// perSeries passes the points of each series, sorted by time, to seriesF which appends the
+// resulting points to a new series with the same labels. The input series is not modified.
+// NoData is passed through, any other type of value is an error.
+func perSeries(e *State, varSet Results, name string, seriesF func(points []seriesPoint, newSeries Series)) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch res.Type() {
+		case parse.TypeSeriesSet:
+			s := res.(Series)
+			newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+			seriesF(sortedPoints(s), newSeries)
+			newRes.Values = append(newRes.Values, newSeries)
+		case parse.TypeNoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected a time series, got %s", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// sortedPoints returns a copy of the points of the series sorted by time.
+func sortedPoints(s Series) []seriesPoint {
+	points := make([]seriesPoint, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		points = append(points, seriesPoint{t: t, f: f})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+	return points
+}
+
+// perPointPair appends the result of pairF for each non-null point and the closest
+// preceding non-null point. Null points stay null and the first point is dropped.
+func perPointPair(points []seriesPoint, newSeries Series, pairF func(prev, cur seriesPoint) *float64) {
+	var prev *seriesPoint
+	for i, p := range points {
+		if i == 0 {
+			if p.f != nil {
+				prev = &points[i]
+			}
+			continue
+		}
+		if p.f == nil || prev == nil {
+			newSeries.AppendPoint(p.t, nil)
+		} else {
+			newSeries.AppendPoint(p.t, pairF(*prev, p))
+		}
+		if p.f != nil {
+			prev = &points[i]
+		}
+	}
+}
@@ -159,3 +159,106 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	series := func(points ...tp) Results {
+		return Results{[]Value{makeSeries("", nil, points...)}}
+	}
+	input := Vars{
+		"A": series(
+			tp{time.Unix(0, 0), float64Pointer(1)},
+			tp{time.Unix(60, 0), float64Pointer(3)},
+			tp{time.Unix(120, 0), nil},
+			tp{time.Unix(180, 0), float64Pointer(9)},
+			tp{time.Unix(240, 0), float64Pointer(2)},
+		),
+	}
+
+	var tests = []struct {
+		name    string
+		expr    string
+		vars    Vars
+		results Results
+	}{
+		{
+			name: "timeShift moves points forward",
+			expr: "timeShift($A, 1d)",
+			vars: input,
+			results: series(
+				tp{time.Unix(86400, 0), float64Pointer(1)},
+				tp{time.Unix(86460, 0), float64Pointer(3)},
+				tp{time.Unix(86520, 0), nil},
+				tp{time.Unix(86580, 0), float64Pointer(9)},
+				tp{time.Unix(86640, 0), float64Pointer(2)},
+			),
+		},
+		{
+			name: "movingAvg averages non-null values within the window",
+			expr: "movingAvg($A, 2m)",
+			vars: input,
+			results: series(
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(2)},
+				tp{time.Unix(120, 0), float64Pointer(3)},
+				tp{time.Unix(180, 0), float64Pointer(9)},
+				tp{time.Unix(240, 0), float64Pointer(5.5)},
+			),
+		},
+		{
+			name: "rate handles nulls and counter resets",
+			expr: "rate($A)",
+			vars: input,
+			results: series(
+				tp{time.Unix(60, 0), float64Pointer(2.0 / 60)},
+				tp{time.Unix(120, 0), nil},
+				tp{time.Unix(180, 0), float64Pointer(6.0 / 120)},
+				tp{time.Unix(240, 0), float64Pointer(2.0 / 60)},
+			),
+		},
+		{
+			name: "delta returns difference to previous value",
+			expr: "delta($A)",
+			vars: input,
+			results: series(
+				tp{time.Unix(60, 0), float64Pointer(2)},
+				tp{time.Unix(120, 0), nil},
+				tp{time.Unix(180, 0), float64Pointer(6)},
+				tp{time.Unix(240, 0), float64Pointer(-7)},
+			),
+		},
+		{
+			name: "cumulativeSum returns running total",
+			expr: "cumulativeSum($A)",
+			vars: input,
+			results: series(
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(4)},
+				tp{time.Unix(120, 0), nil},
+				tp{time.Unix(180, 0), float64Pointer(13)},
+				tp{time.Unix(240, 0), float64Pointer(15)},
+			),
+		},
+		{
+			name:    "functions pass no data through",
+			expr:    "rate($A)",
+			vars:    Vars{"A": Results{[]Value{NewNoData()}}},
+			results: Results{[]Value{NewNoData()}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", tt.vars)
+			require.NoError(t, err)
+			require.Equal(t, tt.results, res)
+		})
+	}
+
+	t.Run("durations can only be used as function arguments", func(t *testing.T) {
+		_, err := New("$A + 5m")
+		require.Error(t, err)
+		_, err = New("timeShift($A, 5)")
+		require.Error(t, err)
+	})
+}
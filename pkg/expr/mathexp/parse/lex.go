@@ -56,8 +56,9 @@ const (
 	itemRightParen
 	itemString
 	itemFunc
-	itemVar // e.g. $A
-	itemPow // '**'
+	itemVar      // e.g. $A
+	itemPow      // '**'
+	itemDuration // e.g. 5m, 1h30m
 )
 
 const eof = -1
@@ -89,7 +90,6 @@ func (l *lexer) next() rune {
 }
 
 // peek returns but does not consume the next rune in the input.
-// nolint:unused
 func (l *lexer) peek() rune {
 	r := l.next()
 	l.backup()
@@ -210,10 +210,18 @@ func lexNumber(l *lexer) stateFn {
 	if !l.scanNumber() {
 		return l.errorf("bad number syntax: %q", l.input[l.start:l.pos])
 	}
+	// A number that is directly followed by a unit is a duration, e.g. 5m or 1h30m.
+	if strings.ContainsRune(durationUnits, l.peek()) {
+		l.acceptRun(durationUnits + "0123456789")
+		l.emit(itemDuration)
+		return lexItem
+	}
 	l.emit(itemNumber)
 	return lexItem
 }
 
+const durationUnits = "smhdwy"
+
 func (l *lexer) scanNumber() bool {
 	// Is it hex?
 	digits := "0123456789"
@@ -276,7 +284,7 @@ func lexSymbol(l *lexer) stateFn {
 func lexFunc(l *lexer) stateFn {
 	for {
 		switch r := l.next(); {
-		case unicode.IsLetter(r) || r == '_':
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
 			// absorb
 		default:
 			l.backup()
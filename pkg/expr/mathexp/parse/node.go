@@ -9,6 +9,10 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -62,6 +66,10 @@ const (
 	NodeNumber
 	// NodeVar is variable: $A
 	NodeVar
+	// NodeDuration is a duration constant: 5m
+	NodeDuration
+	// NodeAggregate is an aggregation over labels: sum by (host) ($A)
+	NodeAggregate
 )
 
 // String returns the string representation of the NodeType
@@ -77,6 +85,10 @@ func (t NodeType) String() string {
 		return "NodeString"
 	case NodeNumber:
 		return "NodeNumber"
+	case NodeDuration:
+		return "NodeDuration"
+	case NodeAggregate:
+		return "NodeAggregate"
 	default:
 		return "NodeUnknown"
 	}
@@ -291,6 +303,58 @@ func (s *StringNode) Return() ReturnType {
 	return TypeString
 }
 
+// DurationNode holds a duration constant like 5m or 1h30m.
+type DurationNode struct {
+	NodeType
+	Pos
+	Duration time.Duration // The parsed duration.
+	Text     string        // The original textual representation from the input.
+}
+
+func newDuration(pos Pos, text string) (*DurationNode, error) {
+	d, err := model.ParseDuration(text)
+	if err != nil {
+		return nil, fmt.Errorf("illegal duration syntax: %q", text)
+	}
+	return &DurationNode{NodeType: NodeDuration, Pos: pos, Duration: time.Duration(d), Text: text}, nil
+}
+
+// String returns the string representation of the DurationNode so it fulfills the Node interface.
+func (d *DurationNode) String() string {
+	return d.Text
+}
+
+// StringAST returns the string representation of abstract syntax tree of the DurationNode so it fulfills the Node interface.
+func (d *DurationNode) StringAST() string {
+	return d.String()
+}
+
+// Check performs parse time checking on the DurationNode so it fulfills the Node interface.
+func (d *DurationNode) Check(*Tree) error {
+	return nil
+}
+
+// Return returns the result type of the DurationNode so it fulfills the Node interface.
+func (d *DurationNode) Return() ReturnType {
+	return TypeDuration
+}
+
+// VectorMatching describes how the values of the two sides of a binary operation are matched.
+type VectorMatching struct {
+	// On is true if only Labels are used for matching (on), otherwise all labels except Labels are used (ignoring).
+	On     bool
+	Labels []string
+}
+
+// String returns the string representation of the VectorMatching.
+func (m *VectorMatching) String() string {
+	op := "ignoring"
+	if m.On {
+		op = "on"
+	}
+	return fmt.Sprintf("%s(%s)", op, strings.Join(m.Labels, ", "))
+}
+
 // BinaryNode holds two arguments and an operator.
 type BinaryNode struct {
 	NodeType
@@ -298,6 +362,8 @@ type BinaryNode struct {
 	Args     [2]Node
 	Operator item
 	OpStr    string
+	// Matching is nil if the default union by labels is used.
+	Matching *VectorMatching
 }
 
 func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
@@ -306,6 +372,9 @@ func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
 
 // String returns the string representation of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) String() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s %s %s", b.Args[0], b.Operator.val, b.Matching, b.Args[1])
+	}
 	return fmt.Sprintf("%s %s %s", b.Args[0], b.Operator.val, b.Args[1])
 }
 
@@ -316,6 +385,11 @@ func (b *BinaryNode) StringAST() string {
 
 // Check performs parse time checking on the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Check(t *Tree) error {
+	for _, arg := range b.Args {
+		if rt := arg.Return(); rt == TypeDuration {
+			return fmt.Errorf(`parse: type error in %s, durations can only be used as function arguments`, b)
+		}
+	}
 	return nil
 }
 
@@ -329,6 +403,61 @@ func (b *BinaryNode) Return() ReturnType {
 	return t0
 }
 
+var aggregateOps = map[string]struct{}{
+	"sum":   {},
+	"avg":   {},
+	"min":   {},
+	"max":   {},
+	"count": {},
+}
+
+// IsAggregateOp returns true if name is an aggregation operator.
+func IsAggregateOp(name string) bool {
+	_, ok := aggregateOps[name]
+	return ok
+}
+
+// AggregateNode holds an aggregation of the values of its argument, grouped by labels.
+type AggregateNode struct {
+	NodeType
+	Pos
+	Op       string
+	Arg      Node
+	Grouping []string // Labels to group by, or to remove when Without is true.
+	Without  bool
+}
+
+func newAggregate(pos Pos, op string) *AggregateNode {
+	return &AggregateNode{NodeType: NodeAggregate, Pos: pos, Op: op}
+}
+
+// String returns the string representation of the AggregateNode so it fulfills the Node interface.
+func (a *AggregateNode) String() string {
+	if a.Grouping == nil {
+		return fmt.Sprintf("%s(%s)", a.Op, a.Arg)
+	}
+	grouping := "by"
+	if a.Without {
+		grouping = "without"
+	}
+	return fmt.Sprintf("%s %s (%s) (%s)", a.Op, grouping, strings.Join(a.Grouping, ", "), a.Arg)
+}
+
+// StringAST returns the string representation of abstract syntax tree of the AggregateNode so it fulfills the Node interface.
+func (a *AggregateNode) StringAST() string {
+	return a.String()
+}
+
+// Check performs parse time checking on the AggregateNode so it fulfills the Node interface.
+func (a *AggregateNode) Check(t *Tree) error {
+	return a.Arg.Check(t)
+}
+
+// Return returns the result type of the AggregateNode so it fulfills the Node interface.
+func (a *AggregateNode) Return() ReturnType {
+	return a.Arg.Return()
+}
+
 // UnaryNode holds one argument and an operator.
 type UnaryNode struct {
 	NodeType
@@ -378,10 +507,12 @@ func Walk(n Node, f func(Node)) {
 		for _, a := range n.Args {
 			Walk(a, f)
 		}
-	case *ScalarNode, *StringNode:
+	case *ScalarNode, *StringNode, *DurationNode:
 		// Ignore since these node types have no sub nodes.
 	case *UnaryNode:
 		Walk(n.Arg, f)
+	case *AggregateNode:
+		Walk(n.Arg, f)
 	default:
 		panic(fmt.Errorf("other type: %T", n))
 	}
@@ -403,6 +534,8 @@ const (
 	TypeVariantSet
 	// TypeNoData is a no data response without a known data type.
 	TypeNoData
+	// TypeDuration is a duration constant.
+	TypeDuration
 )
 
 // String returns a string representation of the ReturnType.
@@ -420,6 +553,8 @@ func (f ReturnType) String() string {
 		return "variant"
 	case TypeNoData:
 		return "noData"
+	case TypeDuration:
+		return "duration"
 	default:
 		return "unknown"
 	}
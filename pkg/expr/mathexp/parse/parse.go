@@ -165,16 +165,21 @@ func (t *Tree) parse() {
 }
 
 /* Grammar:
-O -> A {"||" A}
-A -> C {"&&" C}
-C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") P}
-P -> M {( "+" | "-" ) M}
-M -> E {( "*" | "/" ) F}
-E -> F {( "**" ) F}
+O -> A {"||" [matching] A}
+A -> C {"&&" [matching] C}
+C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") [matching] P}
+P -> M {( "+" | "-" ) [matching] M}
+M -> E {( "*" | "/" ) [matching] F}
+E -> F {( "**" ) [matching] F}
 F -> v | "(" O ")" | "!" O | "-" O
-v -> number | func(..) | queryVar
+v -> number | duration | aggregate | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
 param -> number | "string" | queryVar
+aggregate -> aggOp [grouping] "(" O ")" [grouping]
+aggOp -> "sum" | "avg" | "min" | "max" | "count"
+grouping -> ( "by" | "without" ) labels
+matching -> ( "on" | "ignoring" ) labels
+labels -> "(" [label {"," label}] ")"
 */
 
 // expr:
@@ -185,7 +190,7 @@ func (t *Tree) O() Node {
 	for {
 		switch t.peek().typ {
 		case itemOr:
-			n = newBinary(t.next(), n, t.A())
+			n = t.binary(t.next(), n, t.A)
 		default:
 			return n
 		}
@@ -198,7 +203,7 @@ func (t *Tree) A() Node {
 	for {
 		switch t.peek().typ {
 		case itemAnd:
-			n = newBinary(t.next(), n, t.C())
+			n = t.binary(t.next(), n, t.C)
 		default:
 			return n
 		}
@@ -211,7 +216,7 @@ func (t *Tree) C() Node {
 	for {
 		switch t.peek().typ {
 		case itemEq, itemNotEq, itemGreater, itemGreaterEq, itemLess, itemLessEq:
-			n = newBinary(t.next(), n, t.P())
+			n = t.binary(t.next(), n, t.P)
 		default:
 			return n
 		}
@@ -224,7 +229,7 @@ func (t *Tree) P() Node {
 	for {
 		switch t.peek().typ {
 		case itemPlus, itemMinus:
-			n = newBinary(t.next(), n, t.M())
+			n = t.binary(t.next(), n, t.M)
 		default:
 			return n
 		}
@@ -237,7 +242,7 @@ func (t *Tree) M() Node {
 	for {
 		switch t.peek().typ {
 		case itemMult, itemDiv, itemMod:
-			n = newBinary(t.next(), n, t.E())
+			n = t.binary(t.next(), n, t.E)
 		default:
 			return n
 		}
@@ -250,7 +255,7 @@ func (t *Tree) E() Node {
 	for {
 		switch t.peek().typ {
 		case itemPow:
-			n = newBinary(t.next(), n, t.F())
+			n = t.binary(t.next(), n, t.F)
 		default:
 			return n
 		}
@@ -260,7 +265,7 @@ func (t *Tree) E() Node {
 // F is v | "(" O ")" | "!" O | "-" O in the grammar.
 func (t *Tree) F() Node {
 	switch token := t.peek(); token.typ {
-	case itemNumber, itemFunc, itemVar:
+	case itemNumber, itemDuration, itemFunc, itemVar:
 		return t.v()
 	case itemNot, itemMinus:
 		return newUnary(t.next(), t.F())
@@ -275,7 +280,19 @@ func (t *Tree) F() Node {
 	return nil
 }
 
-// V is number | func(..) | queryVar in the grammar.
+// binary parses the optional matching modifier after the operator and the right hand side of a binary operation.
+func (t *Tree) binary(operator item, lhs Node, rhs func() Node) Node {
+	var matching *VectorMatching
+	if token := t.peek(); token.typ == itemFunc && (token.val == "on" || token.val == "ignoring") {
+		t.next()
+		matching = &VectorMatching{On: token.val == "on", Labels: t.labels(token.val)}
+	}
+	b := newBinary(operator, lhs, rhs())
+	b.Matching = matching
+	return b
+}
+
+// V is number | duration | aggregate | func(..) | queryVar in the grammar.
 func (t *Tree) v() Node {
 	switch token := t.next(); token.typ {
 	case itemNumber:
@@ -284,8 +301,17 @@ func (t *Tree) v() Node {
 			t.error(err)
 		}
 		return n
+	case itemDuration:
+		n, err := newDuration(token.pos, token.val)
+		if err != nil {
+			t.error(err)
+		}
+		return n
 	case itemFunc:
 		t.backup()
+		if IsAggregateOp(token.val) {
+			return t.Aggregate()
+		}
 		return t.Func()
 	case itemVar:
 		t.backup()
@@ -332,6 +358,74 @@ func (t *Tree) Func() (f *FuncNode) {
 		case itemRightParen:
 			return
 		}
+		// arguments are separated by commas
+		switch token = t.next(); token.typ {
+		case itemComma:
+		case itemRightParen:
+			return
+		default:
+			t.unexpected(token, "func")
+		}
+	}
+}
+
+// Aggregate parses an AggregateNode. The grouping can be written before or after the argument,
+// e.g. sum by (service) ($A) or sum($A) by (service).
+func (t *Tree) Aggregate() (a *AggregateNode) {
+	token := t.next()
+	a = newAggregate(token.pos, token.val)
+	grouped := t.grouping(a)
+	t.expect(itemLeftParen, "aggregation")
+	a.Arg = t.O()
+	t.expect(itemRightParen, "aggregation")
+	if !grouped {
+		t.grouping(a)
+	}
+	switch rt := a.Arg.Return(); rt {
+	case TypeNumberSet, TypeSeriesSet:
+	default:
+		t.errorf("%s expects a number or series argument, got %s", a.Op, rt)
+	}
+	return a
+}
+
+// grouping parses the optional by or without clause of an aggregation.
+func (t *Tree) grouping(a *AggregateNode) bool {
+	token := t.peek()
+	if token.typ != itemFunc || (token.val != "by" && token.val != "without") {
+		return false
+	}
+	t.next()
+	a.Grouping = t.labels(token.val)
+	a.Without = token.val == "without"
+	return true
+}
+
+// labels parses a parenthesized and comma separated list of label names. Label names
+// that are not valid identifiers can be quoted.
+func (t *Tree) labels(context string) []string {
+	t.expect(itemLeftParen, context)
+	labels := []string{}
+	for {
+		switch token := t.next(); token.typ {
+		case itemRightParen:
+			return labels
+		case itemFunc:
+			labels = append(labels, token.val)
+		case itemString:
+			s, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			labels = append(labels, s)
+		default:
+			t.unexpected(token, context)
+		}
+		if token := t.next(); token.typ == itemRightParen {
+			return labels
+		} else if token.typ != itemComma {
+			t.unexpected(token, context)
+		}
 	}
 }
 
@@ -3,12 +3,23 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
-type ReducerFunc = func(fv *Float64Field) *float64
+// ReducerFunc reduces a series to a single value.
+type ReducerFunc = func(s Series) *float64
+
+// valueReducer creates a ReducerFunc from a function that only needs the values of the series.
+func valueReducer(f func(fv *Float64Field) *float64) ReducerFunc {
+	return func(s Series) *float64 {
+		fv := Float64Field(*s.Frame.Fields[seriesTypeValIdx])
+		return f(&fv)
+	}
+}
 
 func Sum(fv *Float64Field) *float64 {
 	var sum float64
@@ -81,28 +92,195 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// Diff returns the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	first, last := First(fv), Last(fv)
+	if first == nil || last == nil {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Range returns the difference between the maximum and the minimum value.
+func Range(fv *Float64Field) *float64 {
+	f := *Max(fv) - *Min(fv)
+	return &f
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+func Variance(fv *Float64Field) *float64 {
+	values, ok := numbers(fv)
+	if !ok {
+		nan := math.NaN()
+		return &nan
+	}
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	var f float64
+	for _, v := range values {
+		f += (v - mean) * (v - mean)
+	}
+	f /= float64(len(values))
+	return &f
+}
+
+func StdDev(fv *Float64Field) *float64 {
+	f := math.Sqrt(*Variance(fv))
+	return &f
+}
+
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a function that calculates the p-th percentile of the values.
+// It interpolates linearly between the closest ranks.
+func Percentile(p float64) func(fv *Float64Field) *float64 {
+	return func(fv *Float64Field) *float64 {
+		values, ok := numbers(fv)
+		if !ok {
+			nan := math.NaN()
+			return &nan
+		}
+		sort.Float64s(values)
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// Rate returns the per-second rate of increase between the first and the last point of the series.
+// Like the rate function in Prometheus it treats any decrease of the value as a counter reset.
+func Rate(s Series) *float64 {
+	nan := math.NaN()
+	if s.Len() < 2 {
+		return &nan
+	}
+	var increase float64
+	prev := s.GetValue(0)
+	if prev == nil || math.IsNaN(*prev) {
+		return &nan
+	}
+	for i := 1; i < s.Len(); i++ {
+		v := s.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return &nan
+		}
+		if *v < *prev {
+			// counter reset
+			increase += *v
+		} else {
+			increase += *v - *prev
+		}
+		prev = v
+	}
+	elapsed := s.GetTime(s.Len() - 1).Sub(s.GetTime(0)).Seconds()
+	if elapsed <= 0 {
+		return &nan
+	}
+	f := increase / elapsed
+	return &f
+}
+
+// numbers returns the values of the field. It returns false if the field is empty or any of the values is null or NaN.
+func numbers(fv *Float64Field) ([]float64, bool) {
+	if fv.Len() == 0 {
+		return nil, false
+	}
+	values := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return nil, false
+		}
+		values = append(values, *v)
+	}
+	return values, true
+}
+
+// ParsePercentile parses the percentile reducer names in the format pN, e.g. p50, p90 or p99.9,
+// and returns the percentile. It returns false if the name is not a valid percentile reducer.
+func ParsePercentile(rFunc string) (float64, bool) {
+	name := strings.ToLower(rFunc)
+	if len(name) < 2 || name[0] != 'p' {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(name[1:], 64)
+	if err != nil || p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, false
+	}
+	return p, true
+}
+
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 	switch strings.ToLower(rFunc) {
 	case "sum":
-		return Sum, nil
+		return valueReducer(Sum), nil
 	case "mean":
-		return Avg, nil
+		return valueReducer(Avg), nil
 	case "min":
-		return Min, nil
+		return valueReducer(Min), nil
 	case "max":
-		return Max, nil
+		return valueReducer(Max), nil
 	case "count":
-		return Count, nil
+		return valueReducer(Count), nil
 	case "last":
-		return Last, nil
+		return valueReducer(Last), nil
+	case "first":
+		return valueReducer(First), nil
+	case "median":
+		return valueReducer(Median), nil
+	case "stddev":
+		return valueReducer(StdDev), nil
+	case "variance":
+		return valueReducer(Variance), nil
+	case "diff":
+		return valueReducer(Diff), nil
+	case "range":
+		return valueReducer(Range), nil
+	case "count_non_null":
+		return valueReducer(CountNonNull), nil
+	case "rate":
+		return Rate, nil
 	default:
+		if p, ok := ParsePercentile(rFunc); ok {
+			return valueReducer(Percentile(p)), nil
+		}
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// GetSupportedReduceFuncs returns collection of supported function names.
+// Any percentile in the format pN, e.g. p90 or p99.9, is supported as well.
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "first", "median", "stddev", "variance", "diff", "range", "count_non_null", "rate"}
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
@@ -119,13 +297,11 @@ func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error)
 	if mapper != nil {
 		series = mapSeries(s, mapper)
 	}
-	fVec := series.Frame.Fields[seriesTypeValIdx]
-	floatField := Float64Field(*fVec)
 	reduceFunc, err := GetReduceFunc(rFunc)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
-	f = reduceFunc(&floatField)
+	f = reduceFunc(series)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
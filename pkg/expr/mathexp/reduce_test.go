@@ -266,6 +266,75 @@ func TestSeriesReduce(t *testing.T) {
 	}
 }
 
+func TestSeriesReduceFunctions(t *testing.T) {
+	// values are 4, 1, 3, 2, 5 with one point every 10 seconds
+	series := makeSeries("temp", nil,
+		tp{time.Unix(0, 0), float64Pointer(4)},
+		tp{time.Unix(10, 0), float64Pointer(1)},
+		tp{time.Unix(20, 0), float64Pointer(3)},
+		tp{time.Unix(30, 0), float64Pointer(2)},
+		tp{time.Unix(40, 0), float64Pointer(5)},
+	)
+
+	var tests = []struct {
+		red      string
+		expected float64
+	}{
+		{red: "first", expected: 4},
+		{red: "median", expected: 3},
+		{red: "p50", expected: 3},
+		{red: "p90", expected: 4.6},
+		{red: "p99.9", expected: 4.996},
+		{red: "P100", expected: 5},
+		{red: "p0", expected: 1},
+		{red: "variance", expected: 2},
+		{red: "stddev", expected: math.Sqrt(2)},
+		{red: "diff", expected: 1},
+		{red: "range", expected: 4},
+		{red: "count_non_null", expected: 5},
+		// increases: 4->1 is a counter reset (+1), 1->3 (+2), 3->2 is a counter reset (+2), 2->5 (+3)
+		{red: "rate", expected: 8.0 / 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.red, func(t *testing.T) {
+			number, err := series.Reduce("", tt.red, nil)
+			require.NoError(t, err)
+			require.NotNil(t, number.GetFloat64Value())
+			require.InDelta(t, tt.expected, *number.GetFloat64Value(), 1e-9)
+		})
+	}
+
+	t.Run("should return NaN if series contains null", func(t *testing.T) {
+		for _, red := range []string{"median", "p90", "stddev", "variance", "diff", "range", "rate"} {
+			number, err := seriesWithNil["A"].Values[0].(Series).Reduce("", red, nil)
+			require.NoError(t, err)
+			require.Truef(t, math.IsNaN(*number.GetFloat64Value()), "reducer %s should return NaN", red)
+		}
+	})
+
+	t.Run("count_non_null should skip null", func(t *testing.T) {
+		number, err := seriesWithNil["A"].Values[0].(Series).Reduce("", "count_non_null", nil)
+		require.NoError(t, err)
+		require.Equal(t, float64(1), *number.GetFloat64Value())
+	})
+
+	t.Run("should return NaN if series is empty", func(t *testing.T) {
+		for _, red := range []string{"first", "median", "p90", "stddev", "variance", "diff", "range", "rate"} {
+			number, err := seriesEmpty["A"].Values[0].(Series).Reduce("", red, nil)
+			require.NoError(t, err)
+			require.Truef(t, math.IsNaN(*number.GetFloat64Value()), "reducer %s should return NaN", red)
+		}
+	})
+
+	t.Run("should fail if percentile is not valid", func(t *testing.T) {
+		for _, red := range []string{"p", "p101", "p-1", "pfoo"} {
+			_, err := series.Reduce("", red, nil)
+			require.Errorf(t, err, "reducer %s should fail", red)
+		}
+	})
+}
+
 var seriesNonNumbers = Vars{
 	"A": Results{
 		[]Value{
@@ -3,6 +3,7 @@ package remotewrite
 import (
 	"fmt"
 	"hash/fnv"
+	"sort"
 	"strings"
 	"time"
 
@@ -190,6 +191,34 @@ func TimeSeriesFromFramesLabelsColumn(frames ...*data.Frame) []prompb.TimeSeries
 	return promTimeSeriesBatch
 }
 
+// NewTimeSeries creates a Prometheus TimeSeries with the given metric name, labels and samples.
+// The metric name and label names are sanitized, and labels are sorted by name. It returns false
+// if the metric name cannot be converted to a valid Prometheus metric name.
+func NewTimeSeries(metricName string, labels map[string]string, samples ...prompb.Sample) (prompb.TimeSeries, bool) {
+	metricName, ok := sanitizeMetricName(metricName)
+	if !ok {
+		return prompb.TimeSeries{}, false
+	}
+	promLabels := createLabels(labels)
+	promLabels = append(promLabels, prompb.Label{
+		Name:  "__name__",
+		Value: metricName,
+	})
+	sort.Slice(promLabels, func(i, j int) bool {
+		return promLabels[i].Name < promLabels[j].Name
+	})
+	return prompb.TimeSeries{Labels: promLabels, Samples: samples}, true
+}
+
+// NewSample creates a Prometheus sample with the given value at the given time.
+func NewSample(tm time.Time, value float64) prompb.Sample {
+	return prompb.Sample{
+		// Timestamp is int milliseconds for remote write.
+		Timestamp: toSampleTime(tm),
+		Value:     value,
+	}
+}
+
 func timeFieldIndex(frame *data.Frame) (int, bool) {
 	timeFieldIndex := -1
 	for i, field := range frame.Fields {
@@ -85,3 +85,20 @@ func TestSerialize(t *testing.T) {
 	_, err := Serialize(frame)
 	require.NoError(t, err)
 }
+
+func TestNewTimeSeries(t *testing.T) {
+	t1 := time.Now()
+	ts, ok := NewTimeSeries("test metric", map[string]string{"z": "1", "a-b": "2"}, NewSample(t1, 1.5))
+	require.True(t, ok)
+	require.Len(t, ts.Samples, 1)
+	require.Equal(t, toSampleTime(t1), ts.Samples[0].Timestamp)
+	require.Equal(t, 1.5, ts.Samples[0].Value)
+	require.Len(t, ts.Labels, 3)
+	require.Equal(t, "__name__", ts.Labels[0].Name)
+	require.Equal(t, "test_metric", ts.Labels[0].Value)
+	require.Equal(t, "a_b", ts.Labels[1].Name)
+	require.Equal(t, "z", ts.Labels[2].Name)
+
+	_, ok = NewTimeSeries("", nil)
+	require.False(t, ok)
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
@@ -22,6 +24,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -53,6 +56,10 @@ type Alertmanager interface {
 	// Receivers
 	GetReceivers(ctx context.Context) []apimodels.Receiver
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error)
+
+	// Notification history
+	NotificationHistoryEnabled() bool
+	GetNotificationHistory(ctx context.Context, query models.NotificationHistoryQuery) (apimodels.NotificationHistory, error)
 }
 
 type AlertingStore interface {
@@ -80,10 +87,14 @@ type API struct {
 	ContactPointService  *provisioning.ContactPointService
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
+	RecurringSilences    *provisioning.RecurringSilenceService
 	AlertRules           *provisioning.AlertRuleService
+	AlertRuleTemplates   *provisioning.AlertRuleTemplateService
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
 	FeatureManager       featuremgmt.FeatureToggles
+	ImageStorage         *image.StorageService
+	OrgService           org.Service
 
 	AppUrl *url.URL
 }
@@ -100,7 +111,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager},
+		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager, stateManager: api.StateManager},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
@@ -132,8 +143,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			accessControl:   api.AccessControl,
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
-			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory),
+			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, &api.Cfg.UnifiedAlerting),
 			featureManager:  api.FeatureManager,
+			amConfigStore:   api.AlertingStore,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewConfiguration(
 		&ConfigSrv{
@@ -141,6 +153,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			store:                api.AdminConfigStore,
 			log:                  logger,
 			alertmanagerProvider: api.AlertsRouter,
+			orgService:           api.OrgService,
 		},
 	), m)
 
@@ -150,8 +163,25 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		contactPointService: api.ContactPointService,
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
+		recurringSilences:   api.RecurringSilences,
 		alertRules:          api.AlertRules,
+		alertRuleTemplates:  api.AlertRuleTemplates,
 	}), m)
+
+	if api.ImageStorage != nil {
+		// Stored images are linked from notifications, so instead of requiring a signed in user
+		// the URLs of the images are signed.
+		srv := &ImageSrv{log: logger, storage: api.ImageStorage}
+		api.RouteRegister.Get(
+			toMacaronPath(image.ImagesPath+"{Token}"),
+			metrics.Instrument(
+				http.MethodGet,
+				image.ImagesPath+"{Token}",
+				srv.RouteGetImage,
+				m,
+			),
+		)
+	}
 }
 
 func (api *API) Usage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
@@ -11,13 +11,18 @@ import (
 
 	"github.com/go-openapi/strfmt"
 	"github.com/grafana/alerting/alerting"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -25,13 +30,20 @@ import (
 const (
 	defaultTestReceiversTimeout = 15 * time.Second
 	maxTestReceiversTimeout     = 30 * time.Second
+
+	defaultNotificationHistoryLimit = 100
+	maxNotificationHistoryLimit     = 1000
+
+	defaultSilencePreviewLookback = 24 * time.Hour
+	maxSilencePreviewLookback     = 7 * 24 * time.Hour
 )
 
 type AlertmanagerSrv struct {
-	log    log.Logger
-	ac     accesscontrol.AccessControl
-	mam    *notifier.MultiOrgAlertmanager
-	crypto notifier.Crypto
+	log          log.Logger
+	ac           accesscontrol.AccessControl
+	mam          *notifier.MultiOrgAlertmanager
+	crypto       notifier.Crypto
+	stateManager state.AlertInstanceManager
 }
 
 type UnknownReceiverError struct {
@@ -92,6 +104,144 @@ func (srv AlertmanagerSrv) RouteCreateSilence(c *contextmodel.ReqContext, postab
 	})
 }
 
+// RoutePreviewSilence returns the firing alerts and the recent notifications that a silence or a mute timing
+// would affect. Notifications are matched by the fingerprints of their alerts, so alerts that are no longer
+// known to the state manager are only considered for previews without matchers.
+// Notifications can only be previewed if the notification history is enabled, otherwise the preview is flagged.
+func (srv AlertmanagerSrv) RoutePreviewSilence(c *contextmodel.ReqContext, body apimodels.SilencePreviewRequest) response.Response {
+	if err := body.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "silence preview failed validation")
+	}
+	lookback := time.Duration(body.Lookback)
+	if lookback == 0 {
+		lookback = defaultSilencePreviewLookback
+	} else if lookback > maxSilencePreviewLookback {
+		lookback = maxSilencePreviewLookback
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	now := timeNow()
+	var history apimodels.NotificationHistory
+	historyEnabled := am.NotificationHistoryEnabled()
+	if historyEnabled {
+		var err error
+		history, err = am.GetNotificationHistory(c.Req.Context(), ngmodels.NotificationHistoryQuery{
+			OrgID: c.OrgID,
+			From:  now.Add(-lookback),
+			To:    now,
+			Limit: maxNotificationHistoryLimit,
+		})
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get notification history")
+		}
+	}
+
+	preview, err := previewSilence(body, srv.stateManager.GetAll(c.OrgID), history, now)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "silence preview failed validation")
+	}
+	preview.NotificationHistoryDisabled = !historyEnabled
+	return response.JSON(http.StatusOK, preview)
+}
+
+func previewSilence(req apimodels.SilencePreviewRequest, states []*state.State, history apimodels.NotificationHistory, now time.Time) (apimodels.SilencePreview, error) {
+	matchers, err := req.LabelMatchers()
+	if err != nil {
+		return apimodels.SilencePreview{}, err
+	}
+	inTimeIntervals := func(t time.Time) bool {
+		if len(req.TimeIntervals) == 0 {
+			return true
+		}
+		for _, interval := range req.TimeIntervals {
+			if interval.ContainsTime(t.UTC()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := apimodels.SilencePreview{
+		Alerts:        []apimodels.SilencePreviewAlert{},
+		Notifications: []apimodels.SilencePreviewNotification{},
+	}
+
+	alertLabels := make(map[string]model.LabelSet, len(states))
+	activeNow := inTimeIntervals(now)
+	for _, s := range states {
+		lset := make(model.LabelSet, len(s.Labels))
+		for k, v := range schedule.AlertLabels(s) {
+			lset[model.LabelName(k)] = model.LabelValue(v)
+		}
+		fingerprint := lset.Fingerprint().String()
+		alertLabels[fingerprint] = lset
+
+		firing := s.State == eval.Alerting || s.State == eval.NoData || s.State == eval.Error
+		if !firing || !activeNow || !matchers.Matches(lset) {
+			continue
+		}
+		result.Alerts = append(result.Alerts, apimodels.SilencePreviewAlert{
+			Labels:      s.GetLabels(ngmodels.WithoutInternalLabels()),
+			Fingerprint: fingerprint,
+			State:       state.FormatStateAndReason(s.State, s.StateReason),
+			ActiveAt:    s.StartsAt,
+		})
+	}
+
+	for _, attempt := range history {
+		if !inTimeIntervals(attempt.At) {
+			continue
+		}
+		var muted []string
+		for _, fingerprint := range attempt.Fingerprints {
+			if len(matchers) == 0 {
+				muted = append(muted, fingerprint)
+				continue
+			}
+			if lset, ok := alertLabels[fingerprint]; ok && matchers.Matches(lset) {
+				muted = append(muted, fingerprint)
+			}
+		}
+		if len(muted) == 0 {
+			continue
+		}
+		result.Notifications = append(result.Notifications, apimodels.SilencePreviewNotification{
+			Attempt:           attempt,
+			MutedFingerprints: muted,
+			Muted:             len(muted) == len(attempt.Fingerprints),
+		})
+	}
+	return result, nil
+}
+
+// RouteTestRouting returns the notification policies that an alert with the given labels is routed to. The saved
+// configuration of the organization is used unless the request contains a configuration.
+func (srv AlertmanagerSrv) RouteTestRouting(c *contextmodel.ReqContext, body apimodels.RoutingTestRequest) response.Response {
+	if err := body.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "routing test failed validation")
+	}
+
+	var cfg apimodels.Config
+	if body.AlertmanagerConfig != nil {
+		cfg = body.AlertmanagerConfig.Config
+	} else {
+		current, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.OrgID)
+		if err != nil {
+			if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "failed to get alertmanager configuration")
+		}
+		cfg = current.AlertmanagerConfig.Config
+	}
+
+	return response.JSON(http.StatusOK, notifier.TestRouting(cfg, body.Labels, timeNow()))
+}
+
 func (srv AlertmanagerSrv) RouteDeleteAlertingConfig(c *contextmodel.ReqContext) response.Response {
 	am, errResp := srv.AlertmanagerFor(c.OrgID)
 	if errResp != nil {
@@ -258,6 +408,44 @@ func (srv AlertmanagerSrv) RouteGetReceivers(c *contextmodel.ReqContext) respons
 	return response.JSON(http.StatusOK, rcvs)
 }
 
+func (srv AlertmanagerSrv) RouteGetNotificationHistory(c *contextmodel.ReqContext) response.Response {
+	query := ngmodels.NotificationHistoryQuery{
+		OrgID:       c.OrgID,
+		Receiver:    c.Query("receiver"),
+		Integration: c.Query("integration"),
+		Status:      c.Query("status"),
+		GroupKey:    c.Query("groupKey"),
+		Limit:       c.QueryInt("limit"),
+	}
+	switch query.Status {
+	case "", ngmodels.NotificationStatusSuccess, ngmodels.NotificationStatusFailed:
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status %q, expected %s or %s", query.Status, ngmodels.NotificationStatusSuccess, ngmodels.NotificationStatusFailed), "")
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.UnixMilli(from)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.UnixMilli(to)
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultNotificationHistoryLimit
+	} else if query.Limit > maxNotificationHistoryLimit {
+		query.Limit = maxNotificationHistoryLimit
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	history, err := am.GetNotificationHistory(c.Req.Context(), query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification history")
+	}
+	return response.JSON(http.StatusOK, history)
+}
+
 func (srv AlertmanagerSrv) RoutePostTestReceivers(c *contextmodel.ReqContext, body apimodels.TestReceiversConfigBodyParams) response.Response {
 	if err := srv.crypto.LoadSecureSettings(c.Req.Context(), c.OrgID, body.Receivers); err != nil {
 		var unknownReceiverError UnknownReceiverError
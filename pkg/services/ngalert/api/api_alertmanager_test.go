@@ -10,8 +10,11 @@ import (
 
 	"github.com/go-openapi/strfmt"
 	"github.com/grafana/alerting/alerting"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -20,10 +23,13 @@ import (
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
@@ -291,6 +297,141 @@ func TestAlertmanagerConfig(t *testing.T) {
 	})
 }
 
+func TestRouteGetNotificationHistory(t *testing.T) {
+	sut := createSut(t, nil)
+
+	requestCtx := func(orgID int64, query string) *contextmodel.ReqContext {
+		rc := createRequestCtxInOrg(orgID)
+		req, err := http.NewRequest(http.MethodGet, "/api/alertmanager/grafana/api/v1/notifications?"+query, nil)
+		require.NoError(t, err)
+		rc.Req = req
+		return rc
+	}
+
+	t.Run("assert 400 Bad Request when status is invalid", func(t *testing.T) {
+		response := sut.RouteGetNotificationHistory(requestCtx(1, "status=pending"))
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 404 Not Found for nonexistent org", func(t *testing.T) {
+		response := sut.RouteGetNotificationHistory(requestCtx(12, ""))
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("assert 200 with empty history", func(t *testing.T) {
+		response := sut.RouteGetNotificationHistory(requestCtx(1, "receiver=grafana-default-email&status=failed&limit=5000"))
+		require.Equal(t, http.StatusOK, response.Status())
+		require.JSONEq(t, "[]", string(response.Body()))
+	})
+}
+
+func TestRoutePreviewSilence(t *testing.T) {
+	sut := createSut(t, nil)
+	fakeAIM := NewFakeAlertInstanceManager(t)
+	fakeAIM.GenerateAlertInstances(1, util.GenerateShortUID(), 3, func(s *state.State) *state.State {
+		s.State = eval.Alerting
+		return s
+	})
+	sut.stateManager = fakeAIM
+
+	matcher := func(name, value string, isRegex bool) *amv2.Matcher {
+		return &amv2.Matcher{Name: &name, Value: &value, IsRegex: &isRegex}
+	}
+
+	t.Run("assert 400 Bad Request without matchers and time intervals", func(t *testing.T) {
+		response := sut.RoutePreviewSilence(createRequestCtxInOrg(1), apimodels.SilencePreviewRequest{})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 400 Bad Request with invalid regex", func(t *testing.T) {
+		body := apimodels.SilencePreviewRequest{Matchers: amv2.Matchers{matcher("alertname", "(", true)}}
+		response := sut.RoutePreviewSilence(createRequestCtxInOrg(1), body)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 404 Not Found for nonexistent org", func(t *testing.T) {
+		body := apimodels.SilencePreviewRequest{Matchers: amv2.Matchers{matcher("alertname", "test_title_0", false)}}
+		response := sut.RoutePreviewSilence(createRequestCtxInOrg(12), body)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("assert 200 with the matching firing alerts", func(t *testing.T) {
+		body := apimodels.SilencePreviewRequest{Matchers: amv2.Matchers{matcher("alertname", "test_title_[01]", true)}}
+		response := sut.RoutePreviewSilence(createRequestCtxInOrg(1), body)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var preview apimodels.SilencePreview
+		require.NoError(t, json.Unmarshal(response.Body(), &preview))
+		require.Len(t, preview.Alerts, 2)
+		require.Empty(t, preview.Notifications)
+		require.True(t, preview.NotificationHistoryDisabled)
+		for _, a := range preview.Alerts {
+			require.NotContains(t, a.Labels, "__alert_rule_uid__")
+			require.Equal(t, "Alerting", a.State)
+		}
+	})
+}
+
+func TestPreviewSilence(t *testing.T) {
+	now := time.Date(2023, 1, 28, 12, 0, 0, 0, time.UTC) // a Saturday
+	newState := func(name string, evalState eval.State) *state.State {
+		return &state.State{
+			OrgID:  1,
+			Labels: data.Labels{"alertname": name, "team": "a"},
+			State:  evalState,
+		}
+	}
+	states := []*state.State{newState("firing", eval.Alerting), newState("normal", eval.Normal), newState("pending", eval.Pending)}
+	fingerprint := func(s *state.State) string {
+		lset := model.LabelSet{}
+		for k, v := range schedule.AlertLabels(s) {
+			lset[model.LabelName(k)] = model.LabelValue(v)
+		}
+		return lset.Fingerprint().String()
+	}
+	firing, normal := fingerprint(states[0]), fingerprint(states[1])
+	history := apimodels.NotificationHistory{
+		{Receiver: "a", Fingerprints: []string{firing}, At: now.Add(-time.Hour)},
+		{Receiver: "b", Fingerprints: []string{firing, normal, "unknown"}, At: now.Add(-25 * time.Hour)},
+	}
+	name, value, isRegex := "alertname", "firing|normal", true
+	byName := amv2.Matchers{{Name: &name, Value: &value, IsRegex: &isRegex}}
+	weekdays := []timeinterval.TimeInterval{{
+		Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 1, End: 5}}},
+	}}
+
+	t.Run("matchers affect firing alerts and notifications of matching alerts", func(t *testing.T) {
+		preview, err := previewSilence(apimodels.SilencePreviewRequest{Matchers: byName}, states, history, now)
+		require.NoError(t, err)
+
+		require.Len(t, preview.Alerts, 1)
+		require.Equal(t, firing, preview.Alerts[0].Fingerprint)
+		require.Len(t, preview.Notifications, 2)
+		require.True(t, preview.Notifications[0].Muted)
+		require.False(t, preview.Notifications[1].Muted)
+		require.ElementsMatch(t, []string{firing, normal}, preview.Notifications[1].MutedFingerprints)
+	})
+
+	t.Run("time intervals affect notifications that fall into them", func(t *testing.T) {
+		preview, err := previewSilence(apimodels.SilencePreviewRequest{TimeIntervals: weekdays}, states, history, now)
+		require.NoError(t, err)
+
+		require.Empty(t, preview.Alerts)
+		require.Len(t, preview.Notifications, 1)
+		require.Equal(t, "b", preview.Notifications[0].Attempt.Receiver)
+		require.True(t, preview.Notifications[0].Muted)
+	})
+
+	t.Run("matchers and time intervals have to apply both", func(t *testing.T) {
+		preview, err := previewSilence(apimodels.SilencePreviewRequest{Matchers: byName, TimeIntervals: weekdays}, states, history, now)
+		require.NoError(t, err)
+
+		require.Empty(t, preview.Alerts)
+		require.Len(t, preview.Notifications, 1)
+		require.False(t, preview.Notifications[0].Muted)
+	})
+}
+
 func TestSilenceCreate(t *testing.T) {
 	makeSilence := func(comment string, createdBy string,
 		startsAt, endsAt strfmt.DateTime, matchers amv2.Matchers) amv2.Silence {
@@ -483,6 +624,61 @@ func TestRouteCreateSilence(t *testing.T) {
 	}
 }
 
+func TestRouteTestRouting(t *testing.T) {
+	sut := createSut(t, nil)
+
+	t.Run("assert 400 Bad Request without labels", func(t *testing.T) {
+		response := sut.RouteTestRouting(createRequestCtxInOrg(1), apimodels.RoutingTestRequest{})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 400 Bad Request for a configuration without a route", func(t *testing.T) {
+		body := apimodels.RoutingTestRequest{
+			Labels:             map[string]string{"team": "a"},
+			AlertmanagerConfig: &apimodels.PostableApiAlertingConfig{},
+		}
+		response := sut.RouteTestRouting(createRequestCtxInOrg(1), body)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 404 Not Found for an org without configuration", func(t *testing.T) {
+		body := apimodels.RoutingTestRequest{Labels: map[string]string{"team": "a"}}
+		response := sut.RouteTestRouting(createRequestCtxInOrg(12), body)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("assert 200 with the saved configuration", func(t *testing.T) {
+		body := apimodels.RoutingTestRequest{Labels: map[string]string{"team": "a"}}
+		response := sut.RouteTestRouting(createRequestCtxInOrg(1), body)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RoutingTestResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Matches, 1)
+		require.Equal(t, "grafana-default-email", result.Matches[0].Receiver)
+	})
+
+	t.Run("assert 200 with a proposed configuration", func(t *testing.T) {
+		cfg := apimodels.PostableApiAlertingConfig{}
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"route": {
+				"receiver": "default",
+				"routes": [{"receiver": "team-a", "object_matchers": [["team", "=", "a"]]}]
+			},
+			"receivers": [{"name": "default"}, {"name": "team-a"}]
+		}`), &cfg))
+		body := apimodels.RoutingTestRequest{Labels: map[string]string{"team": "a"}, AlertmanagerConfig: &cfg}
+		response := sut.RouteTestRouting(createRequestCtxInOrg(1), body)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RoutingTestResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Matches, 1)
+		require.Equal(t, "team-a", result.Matches[0].Receiver)
+		require.Len(t, result.Matches[0].Path, 2)
+	})
+}
+
 func createSut(t *testing.T, accessControl accesscontrol.AccessControl) AlertmanagerSrv {
 	t.Helper()
 
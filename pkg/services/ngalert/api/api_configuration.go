@@ -23,6 +23,7 @@ type ConfigSrv struct {
 	datasourceService    datasources.DataSourceService
 	alertmanagerProvider ExternalAlertmanagerProvider
 	store                store.AdminConfigurationStore
+	orgService           org.Service
 	log                  log.Logger
 }
 
@@ -61,6 +62,7 @@ func (srv ConfigSrv) RouteGetNGalertConfig(c *contextmodel.ReqContext) response.
 
 	resp := apimodels.GettableNGalertConfig{
 		AlertmanagersChoice: apimodels.AlertmanagersChoice(cfg.SendAlertsTo.String()),
+		ParentOrgID:         cfg.ParentOrgID,
 	}
 	return response.JSON(http.StatusOK, resp)
 }
@@ -84,9 +86,40 @@ func (srv ConfigSrv) RoutePostNGalertConfig(c *contextmodel.ReqContext, body api
 		return response.Error(400, "At least one Alertmanager must be provided or configured as a datasource that handles alerts to choose this option", nil)
 	}
 
+	var parentOrgID int64
+	current, err := srv.store.GetAdminConfiguration(c.OrgID)
+	if err != nil && !errors.Is(err, store.ErrNoAdminConfiguration) {
+		msg := "failed to fetch admin configuration from the database"
+		srv.log.Error(msg, "error", err)
+		return ErrResp(http.StatusInternalServerError, err, msg)
+	}
+	if current != nil {
+		parentOrgID = current.ParentOrgID
+	}
+	if body.ParentOrgID != nil && *body.ParentOrgID != parentOrgID {
+		// Forwarding alerts exposes them to another organization, so only server admins can set it up.
+		if !c.IsGrafanaAdmin {
+			return ErrResp(http.StatusForbidden, errors.New("only Grafana server administrators can change the parent organization"), "")
+		}
+		if *body.ParentOrgID < 0 || *body.ParentOrgID == c.OrgID {
+			return response.Error(400, "Invalid parent organization specified", nil)
+		}
+		// Zero removes the parent organization.
+		if *body.ParentOrgID > 0 {
+			if _, err := srv.orgService.GetByID(c.Req.Context(), &org.GetOrgByIDQuery{ID: *body.ParentOrgID}); err != nil {
+				if errors.Is(err, org.ErrOrgNotFound) {
+					return response.Error(400, "Parent organization does not exist", err)
+				}
+				return ErrResp(http.StatusInternalServerError, err, "failed to fetch the parent organization")
+			}
+		}
+		parentOrgID = *body.ParentOrgID
+	}
+
 	cfg := &ngmodels.AdminConfiguration{
 		SendAlertsTo: sendAlertsTo,
 		OrgID:        c.OrgID,
+		ParentOrgID:  parentOrgID,
 	}
 
 	cmd := store.UpdateAdminConfigurationCmd{AdminConfiguration: cfg}
@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
 )
 
 func TestExternalAlertmanagerChoice(t *testing.T) {
@@ -92,12 +93,67 @@ func TestExternalAlertmanagerChoice(t *testing.T) {
 	}
 }
 
+func TestParentOrgConfiguration(t *testing.T) {
+	orgAdmin := createRequestCtxInOrg(2)
+	orgAdmin.OrgRole = org.RoleAdmin
+	serverAdmin := createRequestCtxInOrg(2)
+	serverAdmin.OrgRole = org.RoleAdmin
+	serverAdmin.IsGrafanaAdmin = true
+
+	sut := createAPIAdminSut(t, nil)
+	parentOrgID := func(id int64) *int64 { return &id }
+
+	t.Run("org admins can not set the parent organization", func(t *testing.T) {
+		resp := sut.RoutePostNGalertConfig(orgAdmin, definitions.PostableNGalertConfig{ParentOrgID: parentOrgID(1)})
+		require.Equal(t, http.StatusForbidden, resp.Status())
+	})
+
+	t.Run("the parent organization can not be the organization itself", func(t *testing.T) {
+		resp := sut.RoutePostNGalertConfig(serverAdmin, definitions.PostableNGalertConfig{ParentOrgID: parentOrgID(2)})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("the parent organization must exist", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedError = org.ErrOrgNotFound
+		sut := createAPIAdminSut(t, nil)
+		sut.orgService = orgService
+
+		resp := sut.RoutePostNGalertConfig(serverAdmin, definitions.PostableNGalertConfig{ParentOrgID: parentOrgID(3)})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		cfg, err := sut.store.GetAdminConfiguration(2)
+		require.NoError(t, err)
+		require.Nil(t, cfg)
+	})
+
+	t.Run("server admins can set the parent organization", func(t *testing.T) {
+		resp := sut.RoutePostNGalertConfig(serverAdmin, definitions.PostableNGalertConfig{ParentOrgID: parentOrgID(1)})
+		require.Equal(t, http.StatusCreated, resp.Status())
+
+		resp = sut.RouteGetNGalertConfig(orgAdmin)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var cfg definitions.GettableNGalertConfig
+		require.NoError(t, json.Unmarshal(resp.Body(), &cfg))
+		require.Equal(t, int64(1), cfg.ParentOrgID)
+	})
+
+	t.Run("org admins keep the parent organization when they omit it", func(t *testing.T) {
+		resp := sut.RoutePostNGalertConfig(orgAdmin, definitions.PostableNGalertConfig{AlertmanagersChoice: definitions.InternalAlertmanager})
+		require.Equal(t, http.StatusCreated, resp.Status())
+
+		cfg, err := sut.store.GetAdminConfiguration(2)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), cfg.ParentOrgID)
+	})
+}
+
 func createAPIAdminSut(t *testing.T,
 	datasources []*datasources.DataSource) ConfigSrv {
 	return ConfigSrv{
 		datasourceService: &fakeDatasources.FakeDataSourceService{
 			DataSources: datasources,
 		},
-		store: store.NewFakeAdminConfigStore(t),
+		store:      store.NewFakeAdminConfigStore(t),
+		orgService: orgtest.NewOrgServiceFake(),
 	}
 }
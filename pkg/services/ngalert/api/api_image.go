@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// ImageSrv serves the images of notifications that are stored by Grafana.
+type ImageSrv struct {
+	log     log.Logger
+	storage *image.StorageService
+}
+
+// RouteGetImage returns the contents of a stored image. The request must have the expiration time and the
+// signature of the URL that was created when the image was stored.
+func (srv ImageSrv) RouteGetImage(c *contextmodel.ReqContext) response.Response {
+	token := web.Params(c.Req)[":Token"]
+	contents, err := srv.storage.Get(c.Req.Context(), token, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if errors.Is(err, image.ErrInvalidSignature) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		if errors.Is(err, ngmodels.ErrImageNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		srv.log.Error("Failed to get image", "token", token, "error", err)
+		return ErrResp(http.StatusInternalServerError, err, "failed to get image")
+	}
+	return response.Respond(http.StatusOK, contents).
+		SetHeader("Content-Type", "image/png").
+		SetHeader("Cache-Control", "private, max-age=3600")
+}
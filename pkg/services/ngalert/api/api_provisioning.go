@@ -26,7 +26,9 @@ type ProvisioningSrv struct {
 	contactPointService ContactPointService
 	templates           TemplateService
 	muteTimings         MuteTimingService
+	recurringSilences   RecurringSilenceService
 	alertRules          AlertRuleService
+	alertRuleTemplates  AlertRuleTemplateService
 }
 
 type ContactPointService interface {
@@ -55,6 +57,25 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64) error
 }
 
+type RecurringSilenceService interface {
+	GetRecurringSilences(ctx context.Context, orgID int64) ([]definitions.RecurringSilence, error)
+	GetRecurringSilence(ctx context.Context, orgID int64, uid string) (definitions.RecurringSilence, error)
+	CreateRecurringSilence(ctx context.Context, silence definitions.RecurringSilence, orgID int64) (definitions.RecurringSilence, error)
+	UpdateRecurringSilence(ctx context.Context, silence definitions.RecurringSilence, orgID int64) (*definitions.RecurringSilence, error)
+	DeleteRecurringSilence(ctx context.Context, uid string, orgID int64) error
+}
+
+type AlertRuleTemplateService interface {
+	GetAlertRuleTemplates(ctx context.Context, orgID int64) ([]definitions.AlertRuleTemplate, error)
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (definitions.AlertRuleTemplate, error)
+	CreateAlertRuleTemplate(ctx context.Context, template definitions.AlertRuleTemplate, orgID int64) (definitions.AlertRuleTemplate, error)
+	UpdateAlertRuleTemplate(ctx context.Context, template definitions.AlertRuleTemplate, orgID int64) (*definitions.AlertRuleTemplate, error)
+	DeleteAlertRuleTemplate(ctx context.Context, uid string, orgID int64) error
+	GetAlertRuleTemplateInstances(ctx context.Context, orgID int64, uid string) ([]definitions.AlertRuleTemplateInstance, error)
+	CreateAlertRuleFromTemplate(ctx context.Context, orgID int64, uid string, instance definitions.AlertRuleTemplateInstance, provenance alerting_models.Provenance, userID int64) (alerting_models.AlertRule, error)
+	RolloutAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (definitions.AlertRuleTemplateRollout, error)
+}
+
 type AlertRuleService interface {
 	GetAlertRules(ctx context.Context, orgID int64) ([]*alerting_models.AlertRule, error)
 	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
@@ -254,6 +275,156 @@ func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, na
 	return response.JSON(http.StatusNoContent, nil)
 }
 
+func (srv *ProvisioningSrv) RouteGetRecurringSilences(c *contextmodel.ReqContext) response.Response {
+	silences, err := srv.recurringSilences.GetRecurringSilences(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, silences)
+}
+
+func (srv *ProvisioningSrv) RouteGetRecurringSilence(c *contextmodel.ReqContext, UID string) response.Response {
+	silence, err := srv.recurringSilences.GetRecurringSilence(c.Req.Context(), c.OrgID, UID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, silence)
+}
+
+func (srv *ProvisioningSrv) RoutePostRecurringSilence(c *contextmodel.ReqContext, silence definitions.RecurringSilence) response.Response {
+	silence.Provenance = determineProvenance(c)
+	created, err := srv.recurringSilences.CreateRecurringSilence(c.Req.Context(), silence, c.OrgID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutRecurringSilence(c *contextmodel.ReqContext, silence definitions.RecurringSilence, UID string) response.Response {
+	silence.UID = UID
+	silence.Provenance = determineProvenance(c)
+	updated, err := srv.recurringSilences.UpdateRecurringSilence(c.Req.Context(), silence, c.OrgID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if updated == nil {
+		return response.Empty(http.StatusNotFound)
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteRecurringSilence(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.recurringSilences.DeleteRecurringSilence(c.Req.Context(), UID, c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, err := srv.alertRuleTemplates.GetAlertRuleTemplates(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, templates)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	template, err := srv.alertRuleTemplates.GetAlertRuleTemplate(c.Req.Context(), c.OrgID, UID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, template)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTemplate(c *contextmodel.ReqContext, template definitions.AlertRuleTemplate) response.Response {
+	template.Provenance = determineProvenance(c)
+	created, err := srv.alertRuleTemplates.CreateAlertRuleTemplate(c.Req.Context(), template, c.OrgID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleTemplate(c *contextmodel.ReqContext, template definitions.AlertRuleTemplate, UID string) response.Response {
+	template.UID = UID
+	template.Provenance = determineProvenance(c)
+	updated, err := srv.alertRuleTemplates.UpdateAlertRuleTemplate(c.Req.Context(), template, c.OrgID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if updated == nil {
+		return response.Empty(http.StatusNotFound)
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.alertRuleTemplates.DeleteAlertRuleTemplate(c.Req.Context(), UID, c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplateInstances(c *contextmodel.ReqContext, UID string) response.Response {
+	instances, err := srv.alertRuleTemplates.GetAlertRuleTemplateInstances(c.Req.Context(), c.OrgID, UID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, instances)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTemplateInstance(c *contextmodel.ReqContext, instance definitions.AlertRuleTemplateInstance, UID string) response.Response {
+	provenance := determineProvenance(c)
+	created, err := srv.alertRuleTemplates.CreateAlertRuleFromTemplate(c.Req.Context(), c.OrgID, UID, instance, provenance, c.UserID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, alerting_models.ErrQuotaReached) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, definitions.NewAlertRule(created, provenance))
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTemplateRollout(c *contextmodel.ReqContext, UID string) response.Response {
+	rollout, err := srv.alertRuleTemplates.RolloutAlertRuleTemplate(c.Req.Context(), c.OrgID, UID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, rollout)
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
 	rules, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.OrgID)
 	if err != nil {
@@ -326,121 +326,150 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *contextmodel.ReqContext, ruleGro
 // All operations are performed in a single transaction
 func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule) response.Response {
 	var finalChanges *store.GroupDelta
-	hasAccess := accesscontrol.HasAccess(srv.ac, c)
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
 		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group", groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", c.UserID)
-		groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
+		var err error
+		finalChanges, err = srv.calculateAuthorizedChanges(tranCtx, c, groupKey, rules)
 		if err != nil {
 			return err
 		}
 
-		if groupChanges.IsEmpty() {
-			finalChanges = groupChanges
+		if finalChanges.IsEmpty() {
 			logger.Info("no changes detected in the request. Do nothing")
 			return nil
 		}
 
-		// if RBAC is disabled the permission are limited to folder access that is done upstream
-		if !srv.ac.IsDisabled() {
-			err = authorizeRuleChanges(groupChanges, func(evaluator accesscontrol.Evaluator) bool {
-				return hasAccess(accesscontrol.ReqOrgAdminOrEditor, evaluator)
-			})
-			if err != nil {
-				return err
-			}
-		}
+		return srv.saveGroupChanges(tranCtx, c, logger, finalChanges)
+	})
 
-		if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.OrgID, groupChanges); err != nil {
-			return err
-		}
+	if err != nil {
+		return toRuleGroupUpdateErrorResponse(err)
+	}
 
-		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
-		logger.Debug("updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
-
-		if len(finalChanges.Update) > 0 || len(finalChanges.New) > 0 {
-			updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
-			inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
-			for _, update := range finalChanges.Update {
-				logger.Debug("updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
-				updates = append(updates, ngmodels.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
-				})
-			}
-			for _, rule := range finalChanges.New {
-				inserts = append(inserts, *rule)
-			}
-			_, err = srv.store.InsertAlertRules(tranCtx, inserts)
-			if err != nil {
-				return fmt.Errorf("failed to add rules: %w", err)
-			}
-			err = srv.store.UpdateAlertRules(tranCtx, updates)
-			if err != nil {
-				return fmt.Errorf("failed to update rules: %w", err)
-			}
+	srv.notifySchedulerAboutChanges(c.SignedInUser.OrgID, finalChanges)
+
+	if finalChanges.IsEmpty() {
+		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule group"})
+	}
+
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
+}
+
+// calculateAuthorizedChanges calculates changes to the rule group, verifies that the user is authorized to do them and that they do not affect provisioned rules.
+// It must be called within a transaction.
+func (srv RulerSrv) calculateAuthorizedChanges(ctx context.Context, c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule) (*store.GroupDelta, error) {
+	groupChanges, err := store.CalculateChanges(ctx, srv.store, groupKey, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupChanges.IsEmpty() {
+		return groupChanges, nil
+	}
+
+	// if RBAC is disabled the permission are limited to folder access that is done upstream
+	if !srv.ac.IsDisabled() {
+		hasAccess := accesscontrol.HasAccess(srv.ac, c)
+		err = authorizeRuleChanges(groupChanges, func(evaluator accesscontrol.Evaluator) bool {
+			return hasAccess(accesscontrol.ReqOrgAdminOrEditor, evaluator)
+		})
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		if len(finalChanges.Delete) > 0 {
-			UIDs := make([]string, 0, len(finalChanges.Delete))
-			for _, rule := range finalChanges.Delete {
-				UIDs = append(UIDs, rule.UID)
-			}
+	if err := verifyProvisionedRulesNotAffected(ctx, srv.provenanceStore, c.OrgID, groupChanges); err != nil {
+		return nil, err
+	}
 
-			if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.OrgID, UIDs...); err != nil {
-				return fmt.Errorf("failed to delete rules: %w", err)
-			}
+	return store.UpdateCalculatedRuleFields(groupChanges), nil
+}
+
+// saveGroupChanges writes the changes calculated by calculateAuthorizedChanges to the database and checks the alert rule quota if rules were added.
+// It must be called within a transaction.
+func (srv RulerSrv) saveGroupChanges(ctx context.Context, c *contextmodel.ReqContext, logger log.Logger, changes *store.GroupDelta) error {
+	logger.Debug("updating database with the authorized changes", "add", len(changes.New), "update", len(changes.Update), "delete", len(changes.Delete))
+
+	if len(changes.Update) > 0 || len(changes.New) > 0 {
+		updates := make([]ngmodels.UpdateRule, 0, len(changes.Update))
+		inserts := make([]ngmodels.AlertRule, 0, len(changes.New))
+		for _, update := range changes.Update {
+			logger.Debug("updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
+			updates = append(updates, ngmodels.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
+		}
+		for _, rule := range changes.New {
+			inserts = append(inserts, *rule)
+		}
+		_, err := srv.store.InsertAlertRules(ctx, inserts)
+		if err != nil {
+			return fmt.Errorf("failed to add rules: %w", err)
+		}
+		err = srv.store.UpdateAlertRules(ctx, updates)
+		if err != nil {
+			return fmt.Errorf("failed to update rules: %w", err)
 		}
+	}
 
-		if len(finalChanges.New) > 0 {
-			limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
-				OrgID:  c.OrgID,
-				UserID: c.UserID,
-			}) // alert rule is table name
-			if err != nil {
-				return fmt.Errorf("failed to get alert rules quota: %w", err)
-			}
-			if limitReached {
-				return ngmodels.ErrQuotaReached
-			}
+	if len(changes.Delete) > 0 {
+		UIDs := make([]string, 0, len(changes.Delete))
+		for _, rule := range changes.Delete {
+			UIDs = append(UIDs, rule.UID)
 		}
-		return nil
-	})
 
-	if err != nil {
-		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-			return ErrResp(http.StatusNotFound, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
-			return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrQuotaReached) {
-			return ErrResp(http.StatusForbidden, err, "")
-		} else if errors.Is(err, ErrAuthorization) {
-			return ErrResp(http.StatusUnauthorized, err, "")
-		} else if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+		if err := srv.store.DeleteAlertRulesByUID(ctx, c.SignedInUser.OrgID, UIDs...); err != nil {
+			return fmt.Errorf("failed to delete rules: %w", err)
+		}
+	}
+
+	if len(changes.New) > 0 {
+		limitReached, err := srv.QuotaService.CheckQuotaReached(ctx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
+			OrgID:  c.OrgID,
+			UserID: c.UserID,
+		}) // alert rule is table name
+		if err != nil {
+			return fmt.Errorf("failed to get alert rules quota: %w", err)
+		}
+		if limitReached {
+			return ngmodels.ErrQuotaReached
 		}
-		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 	}
+	return nil
+}
 
-	for _, rule := range finalChanges.Update {
+// notifySchedulerAboutChanges lets the scheduler know about updated and deleted rules after the changes were saved.
+func (srv RulerSrv) notifySchedulerAboutChanges(orgID int64, changes *store.GroupDelta) {
+	for _, rule := range changes.Update {
 		srv.scheduleService.UpdateAlertRule(ngmodels.AlertRuleKey{
-			OrgID: c.SignedInUser.OrgID,
+			OrgID: orgID,
 			UID:   rule.Existing.UID,
 		}, rule.Existing.Version+1, rule.New.IsPaused)
 	}
 
-	if len(finalChanges.Delete) > 0 {
-		keys := make([]ngmodels.AlertRuleKey, 0, len(finalChanges.Delete))
-		for _, rule := range finalChanges.Delete {
+	if len(changes.Delete) > 0 {
+		keys := make([]ngmodels.AlertRuleKey, 0, len(changes.Delete))
+		for _, rule := range changes.Delete {
 			keys = append(keys, rule.GetKey())
 		}
 		srv.scheduleService.DeleteAlertRule(keys...)
 	}
+}
 
-	if finalChanges.IsEmpty() {
-		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule group"})
-	}
-
-	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
+func toRuleGroupUpdateErrorResponse(err error) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+		return ErrResp(http.StatusNotFound, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
+		return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	} else if errors.Is(err, ErrAuthorization) {
+		return ErrResp(http.StatusUnauthorized, err, "")
+	} else if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 }
 
 func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, namespaceID int64, provenanceRecords map[string]ngmodels.Provenance) apimodels.GettableRuleGroupConfig {
@@ -484,12 +513,28 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 			Provenance:      provenance,
 		},
 	}
+	if r.Type() == ngmodels.RuleTypeRecording {
+		gettableExtendedRuleNode.GrafanaManagedAlert.Record = &apimodels.Record{
+			Metric: r.Record.Metric,
+			From:   r.Record.From,
+		}
+	}
+	for _, dep := range r.DependsOn {
+		gettableExtendedRuleNode.GrafanaManagedAlert.DependsOn = append(gettableExtendedRuleNode.GrafanaManagedAlert.DependsOn, apimodels.RuleDependency{
+			RuleUID: dep.RuleUID,
+			State:   string(dep.State),
+		})
+	}
 	forDuration := model.Duration(r.For)
 	gettableExtendedRuleNode.ApiRuleNode = &apimodels.ApiRuleNode{
 		For:         &forDuration,
 		Annotations: r.Annotations,
 		Labels:      r.Labels,
 	}
+	if r.KeepFiringFor > 0 {
+		keepFiringFor := model.Duration(r.KeepFiringFor)
+		gettableExtendedRuleNode.ApiRuleNode.KeepFiringFor = &keepFiringFor
+	}
 	return gettableExtendedRuleNode
 }
 
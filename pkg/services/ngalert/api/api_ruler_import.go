@@ -0,0 +1,359 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/expr"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// prometheusImportQueryRefID is the RefID of the query that evaluates the expression of an imported rule.
+	prometheusImportQueryRefID = "A"
+	// prometheusImportConditionRefID is the RefID of the condition of an imported alerting rule.
+	prometheusImportConditionRefID = "B"
+	// prometheusImportConditionExpression fires for every series returned by the query, which mirrors how Prometheus evaluates alerting rules.
+	prometheusImportConditionExpression = "is_number($A) || is_nan($A) || is_inf($A)"
+	// prometheusImportQueryRange is the relative time range of the query of an imported rule.
+	prometheusImportQueryRange = 10 * time.Minute
+)
+
+// RouteImportPrometheusRules converts rule groups in the Prometheus rule file format to Grafana-managed alert rules
+// and replaces the groups with the same names in the folder. The changes are calculated but saved only if the request commits them.
+func (srv RulerSrv) RouteImportPrometheusRules(c *contextmodel.ReqContext, body apimodels.PostablePrometheusRulesImport) response.Response {
+	if body.Namespace == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("namespace must be specified"), "")
+	}
+	if body.DatasourceUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("datasource UID must be specified"), "")
+	}
+	if len(body.Groups) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("no rule groups to import"), "")
+	}
+
+	namespace, err := srv.store.GetNamespaceByTitle(c.Req.Context(), body.Namespace, c.SignedInUser.OrgID, c.SignedInUser, true)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	q := &ngmodels.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.OrgID,
+		NamespaceUIDs: []string{namespace.UID},
+	}
+	if err := srv.store.ListAlertRules(c.Req.Context(), q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules of the folder")
+	}
+	importedGroups := make(map[string]struct{}, len(body.Groups))
+	for _, group := range body.Groups {
+		importedGroups[group.Name] = struct{}{}
+	}
+	existingUIDs := make(map[prometheusImportRuleKey]string, len(q.Result))
+	otherGroups := make(map[string]string)
+	for _, rule := range q.Result {
+		existingUIDs[prometheusImportRuleKey{group: rule.RuleGroup, title: rule.Title}] = rule.UID
+		if _, ok := importedGroups[rule.RuleGroup]; !ok {
+			otherGroups[rule.Title] = rule.RuleGroup
+		}
+	}
+
+	groups, err := convertPrometheusRuleGroups(body.Groups, body.DatasourceUID, existingUIDs)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	// titles are unique in a folder, so rules of groups that are not imported keep their titles
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			if other, ok := otherGroups[rule.GrafanaManagedAlert.Title]; ok {
+				return ErrResp(http.StatusBadRequest, fmt.Errorf("rule %s of rule group %s already exists in rule group %s of the folder", rule.GrafanaManagedAlert.Title, group.Name, other), "")
+			}
+		}
+	}
+
+	keys := make([]ngmodels.AlertRuleGroupKey, 0, len(groups))
+	rules := make([][]*ngmodels.AlertRule, 0, len(groups))
+	for idx := range groups {
+		groupRules, err := validateRuleGroup(&groups[idx], c.SignedInUser.OrgID, namespace, func(condition ngmodels.Condition) error {
+			return srv.conditionValidator.Validate(eval.Context(c.Req.Context(), c.SignedInUser), condition)
+		}, srv.cfg)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid rule group %s: %w", groups[idx].Name, err), "")
+		}
+		keys = append(keys, ngmodels.AlertRuleGroupKey{
+			OrgID:        c.SignedInUser.OrgID,
+			NamespaceUID: namespace.UID,
+			RuleGroup:    groups[idx].Name,
+		})
+		rules = append(rules, groupRules)
+	}
+
+	changes := make([]*store.GroupDelta, 0, len(groups))
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		for idx, key := range keys {
+			groupChanges, err := srv.calculateAuthorizedChanges(tranCtx, c, key, rules[idx])
+			if err != nil {
+				return err
+			}
+			changes = append(changes, groupChanges)
+		}
+
+		if !body.Commit {
+			return nil
+		}
+		// A rule that moves to another imported group is deleted from its current group and created in the new one.
+		// Rules are deleted first, because titles are unique in a folder.
+		var deletes []string
+		for _, groupChanges := range changes {
+			for _, rule := range groupChanges.Delete {
+				deletes = append(deletes, rule.UID)
+			}
+		}
+		if len(deletes) > 0 {
+			if err := srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.OrgID, deletes...); err != nil {
+				return fmt.Errorf("failed to delete rules: %w", err)
+			}
+		}
+		for _, groupChanges := range changes {
+			if len(groupChanges.New)+len(groupChanges.Update) == 0 {
+				continue
+			}
+			logger := srv.log.New("namespace_uid", groupChanges.GroupKey.NamespaceUID, "group", groupChanges.GroupKey.RuleGroup, "org_id", groupChanges.GroupKey.OrgID, "user_id", c.UserID)
+			if err := srv.saveGroupChanges(tranCtx, c, logger, &store.GroupDelta{
+				GroupKey:       groupChanges.GroupKey,
+				AffectedGroups: groupChanges.AffectedGroups,
+				New:            groupChanges.New,
+				Update:         groupChanges.Update,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return toRuleGroupUpdateErrorResponse(err)
+	}
+
+	result := apimodels.PrometheusRulesImportResult{
+		DryRun: !body.Commit,
+		Groups: make([]apimodels.PrometheusRuleGroupImportResult, 0, len(changes)),
+	}
+	for _, groupChanges := range changes {
+		result.Groups = append(result.Groups, toPrometheusRuleGroupImportResult(groupChanges))
+	}
+
+	if !body.Commit {
+		return response.JSON(http.StatusOK, result)
+	}
+
+	for _, groupChanges := range changes {
+		srv.notifySchedulerAboutChanges(c.SignedInUser.OrgID, groupChanges)
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+func toPrometheusRuleGroupImportResult(changes *store.GroupDelta) apimodels.PrometheusRuleGroupImportResult {
+	result := apimodels.PrometheusRuleGroupImportResult{
+		Name: changes.GroupKey.RuleGroup,
+	}
+	for _, rule := range changes.New {
+		result.Created = append(result.Created, apimodels.PrometheusRuleImportChange{
+			Title: rule.Title,
+		})
+	}
+	for _, update := range changes.Update {
+		diff := make([]string, 0, len(update.Diff))
+		for _, d := range update.Diff {
+			diff = append(diff, d.Path)
+		}
+		result.Updated = append(result.Updated, apimodels.PrometheusRuleImportChange{
+			UID:   update.Existing.UID,
+			Title: update.New.Title,
+			Diff:  diff,
+		})
+	}
+	for _, rule := range changes.Delete {
+		result.Deleted = append(result.Deleted, apimodels.PrometheusRuleImportChange{
+			UID:   rule.UID,
+			Title: rule.Title,
+		})
+	}
+	return result
+}
+
+// prometheusImportRuleKey identifies an existing rule that an imported rule replaces.
+type prometheusImportRuleKey struct {
+	group string
+	title string
+}
+
+// convertPrometheusRuleGroups converts rule groups in the Prometheus rule file format to Grafana-managed rule groups
+// that query the data source with the given UID. Rules get the UID of the existing rule with the same title in the same group, if any.
+// Titles must be unique in a folder, so duplicate alert and metric names get a numeric suffix.
+func convertPrometheusRuleGroups(groups []apimodels.PrometheusRuleGroup, datasourceUID string, existingUIDs map[prometheusImportRuleKey]string) ([]apimodels.PostableRuleGroupConfig, error) {
+	groupNames := make(map[string]struct{}, len(groups))
+	titles := make(map[string]int)
+	result := make([]apimodels.PostableRuleGroupConfig, 0, len(groups))
+	for _, group := range groups {
+		if _, ok := groupNames[group.Name]; ok {
+			return nil, fmt.Errorf("rule group %s is defined more than once", group.Name)
+		}
+		groupNames[group.Name] = struct{}{}
+
+		converted := apimodels.PostableRuleGroupConfig{
+			Name:     group.Name,
+			Interval: group.Interval,
+			Rules:    make([]apimodels.PostableExtendedRuleNode, 0, len(group.Rules)),
+		}
+		for idx, rule := range group.Rules {
+			title := rule.Alert
+			if rule.Record != "" {
+				title = rule.Record
+			}
+			titles[title]++
+			if n := titles[title]; n > 1 {
+				title = fmt.Sprintf("%s (%d)", title, n)
+			}
+			node, err := convertPrometheusRule(rule, title, existingUIDs[prometheusImportRuleKey{group: group.Name, title: title}], datasourceUID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule at index [%d] of rule group %s: %w", idx, group.Name, err)
+			}
+			converted.Rules = append(converted.Rules, node)
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+// convertPrometheusRule converts a Prometheus alerting or recording rule to a Grafana-managed rule.
+// An alerting rule fires for every series returned by its expression. A recording rule records the result of its expression.
+func convertPrometheusRule(rule apimodels.ApiRuleNode, title, uid, datasourceUID string) (apimodels.PostableExtendedRuleNode, error) {
+	if rule.Alert == "" && rule.Record == "" {
+		return apimodels.PostableExtendedRuleNode{}, errors.New("rule must be either an alerting rule or a recording rule")
+	}
+	if rule.Alert != "" && rule.Record != "" {
+		return apimodels.PostableExtendedRuleNode{}, errors.New("rule cannot be both an alerting rule and a recording rule")
+	}
+	if rule.Expr == "" {
+		return apimodels.PostableExtendedRuleNode{}, errors.New("expression must not be empty")
+	}
+
+	query, err := json.Marshal(map[string]interface{}{
+		"refId":   prometheusImportQueryRefID,
+		"expr":    rule.Expr,
+		"instant": true,
+		"range":   false,
+	})
+	if err != nil {
+		return apimodels.PostableExtendedRuleNode{}, err
+	}
+	data := []ngmodels.AlertQuery{
+		{
+			RefID:             prometheusImportQueryRefID,
+			RelativeTimeRange: ngmodels.RelativeTimeRange{From: ngmodels.Duration(prometheusImportQueryRange)},
+			DatasourceUID:     datasourceUID,
+			Model:             query,
+		},
+	}
+
+	grafanaRule := &apimodels.PostableGrafanaRule{
+		Title:        title,
+		UID:          uid,
+		NoDataState:  apimodels.OK,
+		ExecErrState: apimodels.ErrorErrState,
+	}
+	labels, err := convertPrometheusTemplates(rule.Labels)
+	if err != nil {
+		return apimodels.PostableExtendedRuleNode{}, fmt.Errorf("invalid labels: %w", err)
+	}
+	annotations, err := convertPrometheusTemplates(rule.Annotations)
+	if err != nil {
+		return apimodels.PostableExtendedRuleNode{}, fmt.Errorf("invalid annotations: %w", err)
+	}
+
+	// for and keep_firing_for are always set so that re-importing a rule without them resets them
+	var zero model.Duration
+	node := &apimodels.ApiRuleNode{
+		For:           &zero,
+		KeepFiringFor: &zero,
+		Labels:        labels,
+		Annotations:   annotations,
+	}
+
+	if rule.Record != "" {
+		grafanaRule.Record = &apimodels.Record{
+			Metric: rule.Record,
+			From:   prometheusImportQueryRefID,
+		}
+	} else {
+		condition, err := json.Marshal(map[string]interface{}{
+			"refId":      prometheusImportConditionRefID,
+			"type":       "math",
+			"expression": prometheusImportConditionExpression,
+		})
+		if err != nil {
+			return apimodels.PostableExtendedRuleNode{}, err
+		}
+		data = append(data, ngmodels.AlertQuery{
+			RefID:         prometheusImportConditionRefID,
+			DatasourceUID: expr.DatasourceUID,
+			Model:         condition,
+		})
+		grafanaRule.Condition = prometheusImportConditionRefID
+		if rule.For != nil {
+			node.For = rule.For
+		}
+		if rule.KeepFiringFor != nil {
+			node.KeepFiringFor = rule.KeepFiringFor
+		}
+	}
+	grafanaRule.Data = data
+
+	return apimodels.PostableExtendedRuleNode{
+		ApiRuleNode:         node,
+		GrafanaManagedAlert: grafanaRule,
+	}, nil
+}
+
+var (
+	// prometheusTemplateActionRegexp matches the actions of a template, where the variables are used.
+	prometheusTemplateActionRegexp = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+	// prometheusTemplateValueRegexp matches the $value variable, which is the value of the series in Prometheus.
+	prometheusTemplateValueRegexp = regexp.MustCompile(`\$value\b`)
+	// prometheusTemplateUnsupportedRegexp matches the variables of Prometheus templates that Grafana does not have.
+	prometheusTemplateUnsupportedRegexp = regexp.MustCompile(`\$(externalLabels|externalURL)\b`)
+)
+
+// convertPrometheusTemplates translates the Prometheus templates of labels or annotations to Grafana templates.
+// $labels is the same in both, but $value is the value of the query in Grafana, which is $values.A.Value.
+// Templates that use variables that Grafana does not have are rejected.
+func convertPrometheusTemplates(m map[string]string) (map[string]string, error) {
+	if m == nil {
+		return nil, nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		var err error
+		result[k] = prometheusTemplateActionRegexp.ReplaceAllStringFunc(v, func(action string) string {
+			if match := prometheusTemplateUnsupportedRegexp.FindString(action); match != "" && err == nil {
+				err = fmt.Errorf("template of %s uses %s, which is not supported", k, match)
+			}
+			return prometheusTemplateValueRegexp.ReplaceAllLiteralString(action, "$values."+prometheusImportQueryRefID+".Value")
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeConditionValidator struct{}
+
+func (fakeConditionValidator) Validate(eval.EvaluationContext, models.Condition) error {
+	return nil
+}
+
+func TestConvertPrometheusRule(t *testing.T) {
+	forDuration := model.Duration(5 * time.Minute)
+
+	t.Run("alerting rule", func(t *testing.T) {
+		node, err := convertPrometheusRule(apimodels.ApiRuleNode{
+			Alert:       "HighErrorRate",
+			Expr:        `rate(errors_total[5m]) > 0.1`,
+			For:         &forDuration,
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "{{ $labels.instance }} has a high error rate"},
+		}, "HighErrorRate", "existing-uid", "prom")
+		require.NoError(t, err)
+
+		rule := node.GrafanaManagedAlert
+		require.Equal(t, "HighErrorRate", rule.Title)
+		require.Equal(t, "existing-uid", rule.UID)
+		require.Equal(t, "B", rule.Condition)
+		require.Nil(t, rule.Record)
+		require.Equal(t, apimodels.OK, rule.NoDataState)
+		require.Equal(t, apimodels.ErrorErrState, rule.ExecErrState)
+		require.Len(t, rule.Data, 2)
+		require.Equal(t, "prom", rule.Data[0].DatasourceUID)
+		require.JSONEq(t, `{"refId": "A", "expr": "rate(errors_total[5m]) > 0.1", "instant": true, "range": false}`, string(rule.Data[0].Model))
+		require.Equal(t, "__expr__", rule.Data[1].DatasourceUID)
+		require.JSONEq(t, `{"refId": "B", "type": "math", "expression": "is_number($A) || is_nan($A) || is_inf($A)"}`, string(rule.Data[1].Model))
+
+		require.Equal(t, forDuration, *node.ApiRuleNode.For)
+		require.Equal(t, model.Duration(0), *node.ApiRuleNode.KeepFiringFor)
+		require.Equal(t, map[string]string{"severity": "critical"}, node.ApiRuleNode.Labels)
+		require.Equal(t, map[string]string{"summary": "{{ $labels.instance }} has a high error rate"}, node.ApiRuleNode.Annotations)
+	})
+
+	t.Run("recording rule", func(t *testing.T) {
+		node, err := convertPrometheusRule(apimodels.ApiRuleNode{
+			Record: "job:errors:rate5m",
+			Expr:   `sum by (job) (rate(errors_total[5m]))`,
+			Labels: map[string]string{"team": "a"},
+		}, "job:errors:rate5m", "", "prom")
+		require.NoError(t, err)
+
+		rule := node.GrafanaManagedAlert
+		require.Empty(t, rule.Condition)
+		require.Equal(t, &apimodels.Record{Metric: "job:errors:rate5m", From: "A"}, rule.Record)
+		require.Len(t, rule.Data, 1)
+		require.Equal(t, model.Duration(0), *node.ApiRuleNode.For)
+		require.Equal(t, map[string]string{"team": "a"}, node.ApiRuleNode.Labels)
+	})
+
+	t.Run("translates templates", func(t *testing.T) {
+		node, err := convertPrometheusRule(apimodels.ApiRuleNode{
+			Alert:  "HighErrorRate",
+			Expr:   `rate(errors_total[5m]) > 0.1`,
+			Labels: map[string]string{"instance": "{{ $labels.instance }}"},
+			Annotations: map[string]string{
+				"summary":     "{{ $labels.instance }} has an error rate of {{ $value | humanizePercentage }}",
+				"description": "$value is {{ printf \"%.2f\" $value }}",
+			},
+		}, "HighErrorRate", "", "prom")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"instance": "{{ $labels.instance }}"}, node.ApiRuleNode.Labels)
+		require.Equal(t, map[string]string{
+			"summary":     "{{ $labels.instance }} has an error rate of {{ $values.A.Value | humanizePercentage }}",
+			"description": "$value is {{ printf \"%.2f\" $values.A.Value }}",
+		}, node.ApiRuleNode.Annotations)
+
+		_, err = convertPrometheusRule(apimodels.ApiRuleNode{
+			Alert:       "HighErrorRate",
+			Expr:        `rate(errors_total[5m]) > 0.1`,
+			Annotations: map[string]string{"runbook": "{{ $externalURL }}/runbook"},
+		}, "HighErrorRate", "", "prom")
+		require.EqualError(t, err, "invalid annotations: template of runbook uses $externalURL, which is not supported")
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		_, err := convertPrometheusRule(apimodels.ApiRuleNode{Expr: "up"}, "", "", "prom")
+		require.EqualError(t, err, "rule must be either an alerting rule or a recording rule")
+		_, err = convertPrometheusRule(apimodels.ApiRuleNode{Alert: "a", Record: "b", Expr: "up"}, "a", "", "prom")
+		require.EqualError(t, err, "rule cannot be both an alerting rule and a recording rule")
+		_, err = convertPrometheusRule(apimodels.ApiRuleNode{Alert: "a"}, "a", "", "prom")
+		require.EqualError(t, err, "expression must not be empty")
+	})
+}
+
+func TestConvertPrometheusRuleGroups(t *testing.T) {
+	t.Run("makes titles unique and reuses UIDs of existing rules", func(t *testing.T) {
+		groups, err := convertPrometheusRuleGroups([]apimodels.PrometheusRuleGroup{
+			{
+				Name:     "a",
+				Interval: model.Duration(time.Minute),
+				Rules: []apimodels.ApiRuleNode{
+					{Alert: "InstanceDown", Expr: `up{job="a"} == 0`},
+					{Record: "job:up:sum", Expr: "sum by (job) (up)"},
+				},
+			},
+			{
+				Name:  "b",
+				Rules: []apimodels.ApiRuleNode{{Alert: "InstanceDown", Expr: `up{job="b"} == 0`}},
+			},
+		}, "prom", map[prometheusImportRuleKey]string{
+			{group: "b", title: "InstanceDown (2)"}: "uid-2",
+			{group: "b", title: "job:up:sum"}:       "uid-other-group",
+		})
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+
+		require.Equal(t, "a", groups[0].Name)
+		require.Equal(t, model.Duration(time.Minute), groups[0].Interval)
+		require.Equal(t, "InstanceDown", groups[0].Rules[0].GrafanaManagedAlert.Title)
+		require.Empty(t, groups[0].Rules[0].GrafanaManagedAlert.UID)
+		require.Equal(t, "job:up:sum", groups[0].Rules[1].GrafanaManagedAlert.Title)
+		require.Empty(t, groups[0].Rules[1].GrafanaManagedAlert.UID)
+		require.Equal(t, "InstanceDown (2)", groups[1].Rules[0].GrafanaManagedAlert.Title)
+		require.Equal(t, "uid-2", groups[1].Rules[0].GrafanaManagedAlert.UID)
+	})
+
+	t.Run("fails on duplicate groups", func(t *testing.T) {
+		_, err := convertPrometheusRuleGroups([]apimodels.PrometheusRuleGroup{{Name: "a"}, {Name: "a"}}, "prom", nil)
+		require.EqualError(t, err, "rule group a is defined more than once")
+	})
+
+	t.Run("fails on invalid rules", func(t *testing.T) {
+		_, err := convertPrometheusRuleGroups([]apimodels.PrometheusRuleGroup{{Name: "a", Rules: []apimodels.ApiRuleNode{{Alert: "a"}}}}, "prom", nil)
+		require.EqualError(t, err, "invalid rule at index [0] of rule group a: expression must not be empty")
+	})
+}
+
+func TestRouteImportPrometheusRules(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	groupKey := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: folder.UID, RuleGroup: "node"}
+
+	setup := func(t *testing.T) (*fakes.RuleStore, *schedule.FakeScheduleService, *RulerSrv, *models.AlertRule, *models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		existing := models.AlertRuleGen(withGroupKey(groupKey), models.WithTitle("InstanceDown"))()
+		obsolete := models.AlertRuleGen(withGroupKey(groupKey), models.WithTitle("Obsolete"))()
+		ruleStore.PutRule(context.Background(), existing, obsolete)
+
+		scheduler := &schedule.FakeScheduleService{}
+		scheduler.On("UpdateAlertRule", mock.Anything, mock.Anything, mock.Anything).Return()
+		scheduler.On("DeleteAlertRule", mock.Anything).Return()
+
+		srv := createService(acMock.New().WithDisabled(), ruleStore, scheduler)
+		srv.QuotaService = quotatest.New(false, nil)
+		srv.conditionValidator = fakeConditionValidator{}
+		srv.cfg = &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second, DefaultRuleEvaluationInterval: time.Minute}
+		return ruleStore, scheduler, srv, existing, obsolete
+	}
+
+	body := func(commit bool) apimodels.PostablePrometheusRulesImport {
+		return apimodels.PostablePrometheusRulesImport{
+			Namespace:     folder.Title,
+			DatasourceUID: "prom",
+			Commit:        commit,
+			Groups: []apimodels.PrometheusRuleGroup{
+				{
+					Name: "node",
+					Rules: []apimodels.ApiRuleNode{
+						{Alert: "InstanceDown", Expr: "up == 0"},
+						{Alert: "HighLoad", Expr: "node_load1 > 10"},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("returns the changes without saving them by default", func(t *testing.T) {
+		ruleStore, scheduler, srv, existing, obsolete := setup(t)
+
+		resp := srv.RouteImportPrometheusRules(createRequestContext(orgID, org.RoleEditor, nil), body(false))
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		result := apimodels.PrometheusRulesImportResult{}
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.True(t, result.DryRun)
+		require.Len(t, result.Groups, 1)
+		group := result.Groups[0]
+		require.Equal(t, "node", group.Name)
+		require.Equal(t, []apimodels.PrometheusRuleImportChange{{Title: "HighLoad"}}, group.Created)
+		require.Len(t, group.Updated, 1)
+		require.Equal(t, existing.UID, group.Updated[0].UID)
+		require.Contains(t, group.Updated[0].Diff, "Data")
+		require.Equal(t, []apimodels.PrometheusRuleImportChange{{UID: obsolete.UID, Title: "Obsolete"}}, group.Deleted)
+
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			switch cmd.(type) {
+			case []models.AlertRule, []models.UpdateRule:
+				return cmd, true
+			}
+			return nil, false
+		}))
+		scheduler.AssertNotCalled(t, "UpdateAlertRule", mock.Anything, mock.Anything, mock.Anything)
+		scheduler.AssertNotCalled(t, "DeleteAlertRule", mock.Anything)
+	})
+
+	t.Run("saves the changes if they are committed", func(t *testing.T) {
+		ruleStore, scheduler, srv, existing, obsolete := setup(t)
+
+		resp := srv.RouteImportPrometheusRules(createRequestContext(orgID, org.RoleEditor, nil), body(true))
+		require.Equal(t, http.StatusAccepted, resp.Status())
+
+		inserts := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.AlertRule)
+			return c, ok
+		})
+		require.Len(t, inserts, 1)
+		inserted := inserts[0].([]models.AlertRule)
+		require.Len(t, inserted, 1)
+		require.Equal(t, "HighLoad", inserted[0].Title)
+		require.Equal(t, "B", inserted[0].Condition)
+
+		updates := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		updated := updates[0].([]models.UpdateRule)
+		require.Len(t, updated, 1)
+		require.Equal(t, existing.UID, updated[0].New.UID)
+
+		scheduler.AssertCalled(t, "DeleteAlertRule", []models.AlertRuleKey{obsolete.GetKey()})
+	})
+
+	t.Run("does not take over rules of other groups", func(t *testing.T) {
+		ruleStore, _, srv, existing, _ := setup(t)
+		other := models.AlertRuleGen(withGroupKey(models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: folder.UID, RuleGroup: "other"}), models.WithTitle("HighLoad"))()
+		ruleStore.PutRule(context.Background(), other)
+
+		resp := srv.RouteImportPrometheusRules(createRequestContext(orgID, org.RoleEditor, nil), body(false))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Contains(t, string(resp.Body()), "rule HighLoad of rule group node already exists in rule group other of the folder")
+
+		// a rule of an imported group moves to another imported group
+		b := body(false)
+		b.Groups = []apimodels.PrometheusRuleGroup{
+			{Name: "node", Rules: []apimodels.ApiRuleNode{{Alert: "Obsolete", Expr: "up == 0"}}},
+			{Name: "other", Rules: []apimodels.ApiRuleNode{{Alert: "HighLoad", Expr: "node_load1 > 10"}, {Alert: "InstanceDown", Expr: "up == 0"}}},
+		}
+		resp = srv.RouteImportPrometheusRules(createRequestContext(orgID, org.RoleEditor, nil), b)
+		require.Equal(t, http.StatusOK, resp.Status())
+		result := apimodels.PrometheusRulesImportResult{}
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, []apimodels.PrometheusRuleImportChange{{UID: existing.UID, Title: "InstanceDown"}}, result.Groups[0].Deleted)
+		require.Equal(t, []apimodels.PrometheusRuleImportChange{{Title: "InstanceDown"}}, result.Groups[1].Created)
+		require.Len(t, result.Groups[1].Updated, 1)
+		require.Equal(t, other.UID, result.Groups[1].Updated[0].UID)
+	})
+
+	t.Run("fails if the data source is not specified", func(t *testing.T) {
+		_, _, srv, _, _ := setup(t)
+		b := body(false)
+		b.DatasourceUID = ""
+		resp := srv.RouteImportPrometheusRules(createRequestContext(orgID, org.RoleEditor, nil), b)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("fails if the rules are not valid", func(t *testing.T) {
+		_, _, srv, _, _ := setup(t)
+		b := body(false)
+		b.Groups[0].Rules[0].Expr = ""
+		resp := srv.RouteImportPrometheusRules(createRequestContext(orgID, org.RoleEditor, nil), b)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
@@ -66,9 +66,27 @@ func validateRuleNode(
 		}
 	}
 
+	record, err := validateRecord(ruleNode.GrafanaManagedAlert.Record)
+	if err != nil {
+		return nil, err
+	}
+
+	dependsOn, err := validateDependsOn(ruleNode.GrafanaManagedAlert.UID, ruleNode.GrafanaManagedAlert.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+
+	condition := ruleNode.GrafanaManagedAlert.Condition
+	if !record.IsEmpty() {
+		if condition != "" {
+			return nil, fmt.Errorf("%w: recording rule cannot have a condition", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		condition = record.From
+	}
+
 	if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
 		if canPatch {
-			if ruleNode.GrafanaManagedAlert.Condition != "" {
+			if condition != "" {
 				return nil, fmt.Errorf("%w: query is not specified by condition is. You must specify both query and condition to update existing alert rule", ngmodels.ErrAlertRuleFailedValidation)
 			}
 		} else {
@@ -78,7 +96,7 @@ func validateRuleNode(
 
 	if len(ruleNode.GrafanaManagedAlert.Data) != 0 {
 		cond := ngmodels.Condition{
-			Condition: ruleNode.GrafanaManagedAlert.Condition,
+			Condition: condition,
 			Data:      ruleNode.GrafanaManagedAlert.Data,
 		}
 		if err = conditionValidator(cond); err != nil {
@@ -97,12 +115,25 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		Record:          record,
+		DependsOn:       dependsOn,
 	}
 
 	newAlertRule.For, err = validateForInterval(ruleNode)
 	if err != nil {
 		return nil, err
 	}
+	if !record.IsEmpty() && newAlertRule.For > 0 {
+		return nil, fmt.Errorf("%w: recording rule cannot have field `for`", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	newAlertRule.KeepFiringFor, err = validateKeepFiringFor(ruleNode)
+	if err != nil {
+		return nil, err
+	}
+	if !record.IsEmpty() && newAlertRule.KeepFiringFor > 0 {
+		return nil, fmt.Errorf("%w: recording rule cannot have field `keep_firing_for`", ngmodels.ErrAlertRuleFailedValidation)
+	}
 
 	if ruleNode.ApiRuleNode != nil {
 		newAlertRule.Annotations = ruleNode.ApiRuleNode.Annotations
@@ -116,6 +147,41 @@ func validateRuleNode(
 	return &newAlertRule, nil
 }
 
+// validateRecord validates the recording rule settings and converts them to models.Record.
+// It returns an empty models.Record if the rule is not a recording rule.
+func validateRecord(record *apimodels.Record) (ngmodels.Record, error) {
+	if record == nil {
+		return ngmodels.Record{}, nil
+	}
+	result := ngmodels.Record{
+		Metric: record.Metric,
+		From:   record.From,
+	}
+	if err := result.Validate(); err != nil {
+		return ngmodels.Record{}, err
+	}
+	return result, nil
+}
+
+// validateDependsOn validates the dependencies of the rule with the given UID and converts them to models.RuleDependency.
+// Dependencies on rules of the same group are validated by validateRuleGroup.
+func validateDependsOn(uid string, dependsOn []apimodels.RuleDependency) ([]ngmodels.RuleDependency, error) {
+	if len(dependsOn) == 0 {
+		return nil, nil
+	}
+	result := make([]ngmodels.RuleDependency, 0, len(dependsOn))
+	for _, dep := range dependsOn {
+		result = append(result, ngmodels.RuleDependency{
+			RuleUID: dep.RuleUID,
+			State:   ngmodels.RuleDependencyState(dep.State),
+		})
+	}
+	if err := ngmodels.ValidateRuleDependencies(uid, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func validateInterval(cfg *setting.UnifiedAlertingSettings, interval time.Duration) (int64, error) {
 	intervalSeconds := int64(interval.Seconds())
 
@@ -147,6 +213,21 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringFor validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringFor(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 func validateRuleGroup(
@@ -192,5 +273,8 @@ func validateRuleGroup(
 		rule.RuleGroupIndex = idx + 1
 		result = append(result, rule)
 	}
+	if err := ngmodels.ValidateRuleGroupDependencies(result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
@@ -75,6 +75,17 @@ func validRule() apimodels.PostableExtendedRuleNode {
 	}
 }
 
+func validRecordingRule() apimodels.PostableExtendedRuleNode {
+	r := validRule()
+	r.ApiRuleNode.For = nil
+	r.GrafanaManagedAlert.Condition = ""
+	r.GrafanaManagedAlert.Record = &apimodels.Record{
+		Metric: "test_metric:rate5m",
+		From:   "A",
+	}
+	return r
+}
+
 func validGroup(cfg *setting.UnifiedAlertingSettings, rules ...apimodels.PostableExtendedRuleNode) apimodels.PostableRuleGroupConfig {
 	return apimodels.PostableRuleGroupConfig{
 		Name:     "TEST-ALERTS-" + util.GenerateShortUID(),
@@ -130,6 +141,21 @@ func TestValidateRuleGroup(t *testing.T) {
 			require.Equal(t, int64(cfg.DefaultRuleEvaluationInterval.Seconds()), alert.IntervalSeconds)
 		}
 	})
+	t.Run("should accept dependencies between rules of the group", func(t *testing.T) {
+		upstream := validRule()
+		dependent := validRule()
+		dependent.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+			{RuleUID: upstream.GrafanaManagedAlert.UID, State: "Normal"},
+		}
+		g := validGroup(cfg, dependent, upstream)
+		alerts, err := validateRuleGroup(&g, orgId, folder, func(condition models.Condition) error {
+			return nil
+		}, cfg)
+		require.NoError(t, err)
+		require.Equal(t, []models.RuleDependency{
+			{RuleUID: upstream.GrafanaManagedAlert.UID, State: models.RuleDependencyStateNormal},
+		}, alerts[0].DependsOn)
+	})
 }
 
 func TestValidateRuleGroupFailures(t *testing.T) {
@@ -191,6 +217,44 @@ func TestValidateRuleGroupFailures(t *testing.T) {
 				require.Contains(t, err.Error(), apiModel.Rules[0].GrafanaManagedAlert.UID)
 			},
 		},
+		{
+			name: "fail if rule depends on rule of another group",
+			group: func() *apimodels.PostableRuleGroupConfig {
+				r := validRule()
+				r.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+					{RuleUID: util.GenerateShortUID(), State: "Normal"},
+				}
+				g := validGroup(cfg, r)
+				return &g
+			},
+			assert: func(t *testing.T, apiModel *apimodels.PostableRuleGroupConfig, err error) {
+				require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+				require.Contains(t, err.Error(), apiModel.Rules[0].GrafanaManagedAlert.DependsOn[0].RuleUID)
+			},
+		},
+		{
+			name: "fail if dependencies have a cycle",
+			group: func() *apimodels.PostableRuleGroupConfig {
+				r1 := validRule()
+				r2 := validRule()
+				r3 := validRule()
+				r1.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+					{RuleUID: r2.GrafanaManagedAlert.UID, State: "Normal"},
+				}
+				r2.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+					{RuleUID: r3.GrafanaManagedAlert.UID, State: "Alerting"},
+				}
+				r3.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+					{RuleUID: r1.GrafanaManagedAlert.UID, State: "Pending"},
+				}
+				g := validGroup(cfg, r1, r2, r3)
+				return &g
+			},
+			assert: func(t *testing.T, apiModel *apimodels.PostableRuleGroupConfig, err error) {
+				require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+				require.Contains(t, err.Error(), "cycle")
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -298,6 +362,32 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Equal(t, int64(panelId), *alert.PanelID)
 			},
 		},
+		{
+			name: "coverts keep_firing_for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(5 * time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, 5*time.Minute, alert.KeepFiringFor)
+			},
+		},
+		{
+			name: "coverts recording rule",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, models.RuleTypeRecording, alert.Type())
+				require.Equal(t, api.GrafanaManagedAlert.Record.Metric, alert.Record.Metric)
+				require.Equal(t, api.GrafanaManagedAlert.Record.From, alert.Record.From)
+				require.Equal(t, "A", alert.GetEvalCondition().Condition)
+				require.Empty(t, alert.Condition)
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -428,6 +518,77 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if recording rule has invalid metric name",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				r.GrafanaManagedAlert.Record.Metric = "invalid metric"
+				return &r
+			},
+		},
+		{
+			name: "fail if recording rule has no from",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				r.GrafanaManagedAlert.Record.From = ""
+				return &r
+			},
+		},
+		{
+			name: "fail if recording rule has condition",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				r.GrafanaManagedAlert.Condition = "A"
+				return &r
+			},
+		},
+		{
+			name: "fail if recording rule has for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				forDuration := model.Duration(time.Minute)
+				r.ApiRuleNode.For = &forDuration
+				return &r
+			},
+		},
+		{
+			name: "fail if recording rule has keep_firing_for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				keepFiringFor := model.Duration(time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
+		{
+			name: "fail if keep_firing_for is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(-time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
+		{
+			name: "fail if dependency has unknown state",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+					{RuleUID: util.GenerateShortUID(), State: "Firing"},
+				}
+				return &r
+			},
+		},
+		{
+			name: "fail if dependency has no rule UID",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.DependsOn = []apimodels.RuleDependency{
+					{State: "Normal"},
+				}
+				return &r
+			},
+		},
 		{
 			name: "fail if PanelID is specified but not Dashboard UID ",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -543,6 +704,17 @@ func TestValidateRuleNode_UID(t *testing.T) {
 				require.Equal(t, int64(panelId), *alert.PanelID)
 			},
 		},
+		{
+			name: "use -1 if KeepFiringFor is not specified",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.ApiRuleNode.KeepFiringFor = nil
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, time.Duration(-1), alert.KeepFiringFor)
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amConfig "github.com/prometheus/alertmanager/config"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -20,6 +21,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -33,6 +36,7 @@ type TestingApiSrv struct {
 	cfg             *setting.UnifiedAlertingSettings
 	backtesting     *backtesting.Engine
 	featureManager  featuremgmt.FeatureToggles
+	amConfigStore   AlertingStore
 }
 
 func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext, body apimodels.TestRulePayload) response.Response {
@@ -150,32 +154,89 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
 	}
 
+	rule, errResp := srv.backtestingRule(c, cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+
+	body, err := data.FrameToJSON(result, data.IncludeAll)
+	if err != nil {
+		return ErrResp(500, err, "Failed to convert frame to JSON")
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+// BacktestReplayAlertRule replays the rule over the requested range through the state manager and returns
+// the state transitions and the notifications that would have been sent according to the notification policy of the organization.
+func (srv TestingApiSrv) BacktestReplayAlertRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	if !srv.featureManager.IsEnabled(featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
+	}
+
+	rule, errResp := srv.backtestingRule(c, cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	routingTree, err := srv.routingTree(c)
+	if err != nil {
+		return ErrResp(500, err, "Failed to get notification policy")
+	}
+
+	result, err := srv.backtesting.Replay(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, routingTree)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+
+	return response.JSON(http.StatusOK, toBacktestReplayResult(result))
+}
+
+// backtestingRule validates the backtesting configuration and creates a temporary alert rule from it.
+func (srv TestingApiSrv) backtestingRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) (*ngmodels.AlertRule, response.Response) {
 	if cmd.From.After(cmd.To) {
-		return ErrResp(400, nil, "From cannot be greater than To")
+		return nil, ErrResp(400, nil, "From cannot be greater than To")
 	}
 
 	noDataState, err := ngmodels.NoDataStateFromString(string(cmd.NoDataState))
 
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
+	}
+	execErrState := ngmodels.AlertingErrState
+	if cmd.ExecErrState != "" {
+		execErrState, err = ngmodels.ErrStateFromString(string(cmd.ExecErrState))
+		if err != nil {
+			return nil, ErrResp(400, err, "")
+		}
 	}
 	forInterval := time.Duration(cmd.For)
 	if forInterval < 0 {
-		return ErrResp(400, nil, "Bad For interval")
+		return nil, ErrResp(400, nil, "Bad For interval")
 	}
 
 	intervalSeconds, err := validateInterval(srv.cfg, time.Duration(cmd.Interval))
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
 	}
 
 	if !authorizeDatasourceAccessForRule(&ngmodels.AlertRule{Data: cmd.Data}, func(evaluator accesscontrol.Evaluator) bool {
 		return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
 	}) {
-		return errorToResponse(fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization))
+		return nil, errorToResponse(fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization))
 	}
 
-	rule := &ngmodels.AlertRule{
+	return &ngmodels.AlertRule{
 		// ID:             0,
 		// Updated:        time.Time{},
 		// Version:        0,
@@ -184,7 +245,6 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		// PanelID:        nil,
 		// RuleGroup:      "",
 		// RuleGroupIndex: 0,
-		// ExecErrState:   "",
 		Title: cmd.Title,
 		// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs (like expression engine, evaluator, state manager etc)
 		UID:             "backtesting-" + util.GenerateShortUID(),
@@ -193,22 +253,64 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		Data:            cmd.Data,
 		IntervalSeconds: intervalSeconds,
 		NoDataState:     noDataState,
+		ExecErrState:    execErrState,
 		For:             forInterval,
 		Annotations:     cmd.Annotations,
 		Labels:          cmd.Labels,
-	}
+	}, nil
+}
 
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
-	if err != nil {
-		if errors.Is(err, backtesting.ErrInvalidInputData) {
-			return ErrResp(400, err, "Failed to evaluate")
+// routingTree returns the root of the notification policy tree of the current organization.
+// It returns nil if the organization does not have an Alertmanager configuration yet.
+func (srv TestingApiSrv) routingTree(c *contextmodel.ReqContext) (*amConfig.Route, error) {
+	query := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: c.OrgID}
+	if err := srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), &query); err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return nil, nil
 		}
-		return ErrResp(500, err, "Failed to evaluate")
+		return nil, fmt.Errorf("failed to get latest configuration: %w", err)
 	}
-
-	body, err := data.FrameToJSON(result, data.IncludeAll)
+	cfg, err := notifier.Load([]byte(query.Result.AlertmanagerConfiguration))
 	if err != nil {
-		return ErrResp(500, err, "Failed to convert frame to JSON")
+		return nil, fmt.Errorf("failed to unmarshal alertmanager configuration: %w", err)
+	}
+	if cfg.AlertmanagerConfig.Route == nil {
+		return nil, nil
+	}
+	return cfg.AlertmanagerConfig.Route.AsAMRoute(), nil
+}
+
+func toBacktestReplayResult(result *backtesting.ReplayResult) apimodels.BacktestReplayResult {
+	transitions := make([]apimodels.BacktestStateTransition, 0, len(result.Transitions))
+	for _, t := range result.Transitions {
+		transitions = append(transitions, apimodels.BacktestStateTransition{
+			At:            t.At,
+			Labels:        t.Labels,
+			PreviousState: t.PreviousState,
+			State:         t.State,
+			Values:        t.Values,
+		})
+	}
+	groups := make([]apimodels.BacktestNotificationGroup, 0, len(result.Notifications))
+	for _, g := range result.Notifications {
+		notifications := make([]apimodels.BacktestNotification, 0, len(g.Notifications))
+		for _, n := range g.Notifications {
+			notifications = append(notifications, apimodels.BacktestNotification{
+				At:       n.At,
+				Labels:   n.Labels,
+				State:    n.State,
+				StartsAt: n.StartsAt,
+				Resolved: n.Resolved,
+			})
+		}
+		groups = append(groups, apimodels.BacktestNotificationGroup{
+			Receiver:      g.Receiver,
+			GroupLabels:   g.GroupLabels,
+			Notifications: notifications,
+		})
+	}
+	return apimodels.BacktestReplayResult{
+		Transitions:   transitions,
+		Notifications: groups,
 	}
-	return response.JSON(http.StatusOK, body)
 }
@@ -58,6 +58,14 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingRuleCreate, scope),
 			ac.EvalPermission(ac.ActionAlertingRuleDelete, scope),
 		)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/import/prometheus":
+		fallback = middleware.ReqSignedIn
+		// the folder is specified in the body. Folder access and granular permissions are enforced by the handler
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingRuleUpdate),
+			ac.EvalPermission(ac.ActionAlertingRuleCreate),
+			ac.EvalPermission(ac.ActionAlertingRuleDelete),
+		)
 
 	// Grafana, Prometheus-compatible Paths
 	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules":
@@ -73,6 +81,10 @@ func (api *API) authorize(method, path string) web.Handler {
 		fallback = middleware.ReqSignedIn
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/backtest/replay":
+		fallback = middleware.ReqSignedIn
+		// additional authorization is done in the request handler
+		eval = ac.EvalAll(ac.EvalPermission(ac.ActionAlertingRuleRead), ac.EvalPermission(ac.ActionAlertingNotificationsRead))
 	case http.MethodPost + "/api/v1/eval":
 		fallback = middleware.ReqSignedIn
 		// additional authorization is done in the request handler
@@ -113,6 +125,8 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/alertmanager/grafana/api/v2/silences":
 		// additional authorization is done in the request handler
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingInstanceCreate), ac.EvalPermission(ac.ActionAlertingInstanceUpdate))
+	case http.MethodPost + "/api/alertmanager/grafana/api/v2/silences/preview":
+		eval = ac.EvalAll(ac.EvalPermission(ac.ActionAlertingInstanceRead), ac.EvalPermission(ac.ActionAlertingNotificationsRead))
 
 	// Alert Instances. Grafana Paths
 	case http.MethodGet + "/api/alertmanager/grafana/api/v2/alerts/groups":
@@ -161,9 +175,14 @@ func (api *API) authorize(method, path string) web.Handler {
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingNotificationsWrite))
 	case http.MethodGet + "/api/alertmanager/grafana/config/api/v1/receivers":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+	case http.MethodGet + "/api/alertmanager/grafana/api/v1/notifications":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/receivers/test":
 		fallback = middleware.ReqEditorRole
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/routes/test":
+		fallback = middleware.ReqEditorRole
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 
 	// External Alertmanager Paths
 	case http.MethodDelete + "/api/alertmanager/{DatasourceUID}/config/api/v1/alerts":
@@ -199,6 +218,11 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
 		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodGet + "/api/v1/provisioning/recurring-silences",
+		http.MethodGet + "/api/v1/provisioning/recurring-silences/{UID}",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}/instances",
 		http.MethodGet + "/api/v1/provisioning/alert-rules",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/export",
@@ -218,6 +242,14 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodPost + "/api/v1/provisioning/mute-timings",
 		http.MethodPut + "/api/v1/provisioning/mute-timings/{name}",
 		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodPost + "/api/v1/provisioning/recurring-silences",
+		http.MethodPut + "/api/v1/provisioning/recurring-silences/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/recurring-silences/{UID}",
+		http.MethodPost + "/api/v1/provisioning/alert-rule-templates",
+		http.MethodPut + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodPost + "/api/v1/provisioning/alert-rule-templates/{UID}/instances",
+		http.MethodPost + "/api/v1/provisioning/alert-rule-templates/{UID}/rollout",
 		http.MethodPost + "/api/v1/provisioning/alert-rules",
 		http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/alert-rules/{UID}",
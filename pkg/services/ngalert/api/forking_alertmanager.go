@@ -143,6 +143,14 @@ func (f *AlertmanagerApiHandler) handleRouteCreateGrafanaSilence(ctx *contextmod
 	return f.GrafanaSvc.RouteCreateSilence(ctx, body)
 }
 
+func (f *AlertmanagerApiHandler) handleRoutePreviewGrafanaSilence(ctx *contextmodel.ReqContext, body apimodels.SilencePreviewRequest) response.Response {
+	return f.GrafanaSvc.RoutePreviewSilence(ctx, body)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteTestGrafanaRouting(ctx *contextmodel.ReqContext, body apimodels.RoutingTestRequest) response.Response {
+	return f.GrafanaSvc.RouteTestRouting(ctx, body)
+}
+
 func (f *AlertmanagerApiHandler) handleRouteGetGrafanaAMStatus(ctx *contextmodel.ReqContext) response.Response {
 	return f.GrafanaSvc.RouteGetAMStatus(ctx)
 }
@@ -178,6 +186,10 @@ func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceivers(ctx *contextmode
 	return f.GrafanaSvc.RouteGetReceivers(ctx)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationHistory(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationHistory(ctx)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contextmodel.ReqContext, conf apimodels.TestReceiversConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
@@ -101,6 +101,10 @@ func (f *RulerApiHandler) handleRoutePostNameGrafanaRulesConfig(ctx *contextmode
 	return f.GrafanaRuler.RoutePostNameRulesConfig(ctx, conf, namespace)
 }
 
+func (f *RulerApiHandler) handleRouteImportPrometheusRules(ctx *contextmodel.ReqContext, conf apimodels.PostablePrometheusRulesImport) response.Response {
+	return f.GrafanaRuler.RouteImportPrometheusRules(ctx, conf)
+}
+
 func (f *RulerApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexRuler, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
@@ -20,29 +20,42 @@ import (
 
 type ProvisioningApi interface {
 	RouteDeleteAlertRule(*contextmodel.ReqContext) response.Response
+	RouteDeleteAlertRuleTemplate(*contextmodel.ReqContext) response.Response
 	RouteDeleteContactpoints(*contextmodel.ReqContext) response.Response
 	RouteDeleteMuteTiming(*contextmodel.ReqContext) response.Response
+	RouteDeleteRecurringSilence(*contextmodel.ReqContext) response.Response
 	RouteDeleteTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRule(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleExport(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroup(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroupExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplateInstances(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplates(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRules(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRulesExport(*contextmodel.ReqContext) response.Response
 	RouteGetContactpoints(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTimings(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTree(*contextmodel.ReqContext) response.Response
+	RouteGetRecurringSilence(*contextmodel.ReqContext) response.Response
+	RouteGetRecurringSilences(*contextmodel.ReqContext) response.Response
 	RouteGetTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetTemplates(*contextmodel.ReqContext) response.Response
 	RoutePostAlertRule(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleTemplateInstance(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleTemplateRollout(*contextmodel.ReqContext) response.Response
 	RoutePostContactpoints(*contextmodel.ReqContext) response.Response
 	RoutePostMuteTiming(*contextmodel.ReqContext) response.Response
+	RoutePostRecurringSilence(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleTemplate(*contextmodel.ReqContext) response.Response
 	RoutePutContactpoint(*contextmodel.ReqContext) response.Response
 	RoutePutMuteTiming(*contextmodel.ReqContext) response.Response
 	RoutePutPolicyTree(*contextmodel.ReqContext) response.Response
+	RoutePutRecurringSilence(*contextmodel.ReqContext) response.Response
 	RoutePutTemplate(*contextmodel.ReqContext) response.Response
 	RouteResetPolicyTree(*contextmodel.ReqContext) response.Response
 }
@@ -52,6 +65,11 @@ func (f *ProvisioningApiHandler) RouteDeleteAlertRule(ctx *contextmodel.ReqConte
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	return f.handleRouteDeleteAlertRule(ctx, uIDParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteAlertRuleTemplate(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -62,6 +80,11 @@ func (f *ProvisioningApiHandler) RouteDeleteMuteTiming(ctx *contextmodel.ReqCont
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.handleRouteDeleteMuteTiming(ctx, nameParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteRecurringSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteRecurringSilence(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -89,6 +112,19 @@ func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupExport(ctx *contextmodel.
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteGetAlertRuleGroupExport(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleTemplate(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplateInstances(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleTemplateInstances(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertRuleTemplates(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertRules(ctx)
 }
@@ -109,6 +145,14 @@ func (f *ProvisioningApiHandler) RouteGetMuteTimings(ctx *contextmodel.ReqContex
 func (f *ProvisioningApiHandler) RouteGetPolicyTree(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetPolicyTree(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetRecurringSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetRecurringSilence(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetRecurringSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRecurringSilences(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -125,6 +169,29 @@ func (f *ProvisioningApiHandler) RoutePostAlertRule(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePostAlertRule(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleTemplate(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleTemplateInstance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplateInstance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleTemplateInstance(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleTemplateRollout(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRoutePostAlertRuleTemplateRollout(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EmbeddedContactPoint{}
@@ -141,6 +208,14 @@ func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContex
 	}
 	return f.handleRoutePostMuteTiming(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostRecurringSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RecurringSilence{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostRecurringSilence(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePutAlertRule(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -162,6 +237,16 @@ func (f *ProvisioningApiHandler) RoutePutAlertRuleGroup(ctx *contextmodel.ReqCon
 	}
 	return f.handleRoutePutAlertRuleGroup(ctx, conf, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleTemplate(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -190,6 +275,16 @@ func (f *ProvisioningApiHandler) RoutePutPolicyTree(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePutPolicyTree(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePutRecurringSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.RecurringSilence{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutRecurringSilence(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -216,6 +311,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				srv.RouteDeleteAlertRuleTemplate,
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			api.authorize(http.MethodDelete, "/api/v1/provisioning/contact-points/{UID}"),
@@ -236,6 +341,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/recurring-silences/{UID}"),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/recurring-silences/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/recurring-silences/{UID}",
+				srv.RouteDeleteRecurringSilence,
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			api.authorize(http.MethodDelete, "/api/v1/provisioning/templates/{name}"),
@@ -286,6 +401,36 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				srv.RouteGetAlertRuleTemplate,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/instances"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates/{UID}/instances"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/instances",
+				srv.RouteGetAlertRuleTemplateInstances,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates",
+				srv.RouteGetAlertRuleTemplates,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/alert-rules"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules"),
@@ -346,6 +491,26 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/recurring-silences/{UID}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/recurring-silences/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/recurring-silences/{UID}",
+				srv.RouteGetRecurringSilence,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/recurring-silences"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/recurring-silences"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/recurring-silences",
+				srv.RouteGetRecurringSilences,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/{name}"),
@@ -376,6 +541,36 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rule-templates"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rule-templates",
+				srv.RoutePostAlertRuleTemplate,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/instances"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rule-templates/{UID}/instances"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/instances",
+				srv.RoutePostAlertRuleTemplateInstance,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/rollout"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rule-templates/{UID}/rollout"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/rollout",
+				srv.RoutePostAlertRuleTemplateRollout,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points"),
@@ -396,6 +591,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/recurring-silences"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/recurring-silences"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/recurring-silences",
+				srv.RoutePostRecurringSilence,
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"),
 			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rules/{UID}"),
@@ -416,6 +621,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				srv.RoutePutAlertRuleTemplate,
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			api.authorize(http.MethodPut, "/api/v1/provisioning/contact-points/{UID}"),
@@ -446,6 +661,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/recurring-silences/{UID}"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/recurring-silences/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/recurring-silences/{UID}",
+				srv.RoutePutRecurringSilence,
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			api.authorize(http.MethodPut, "/api/v1/provisioning/templates/{name}"),
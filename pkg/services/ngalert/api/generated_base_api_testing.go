@@ -20,6 +20,7 @@ import (
 
 type TestingApi interface {
 	BacktestConfig(*contextmodel.ReqContext) response.Response
+	BacktestReplay(*contextmodel.ReqContext) response.Response
 	RouteEvalQueries(*contextmodel.ReqContext) response.Response
 	RouteTestRuleConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
@@ -33,6 +34,14 @@ func (f *TestingApiHandler) BacktestConfig(ctx *contextmodel.ReqContext) respons
 	}
 	return f.handleBacktestConfig(ctx, conf)
 }
+func (f *TestingApiHandler) BacktestReplay(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BacktestConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleBacktestReplay(ctx, conf)
+}
 func (f *TestingApiHandler) RouteEvalQueries(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EvalQueriesPayload{}
@@ -72,6 +81,16 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest/replay"),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest/replay"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest/replay",
+				srv.BacktestReplay,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/eval"),
 			api.authorize(http.MethodPost, "/api/v1/eval"),
@@ -76,6 +76,58 @@ func (f *ProvisioningApiHandler) handleRouteDeleteMuteTiming(ctx *contextmodel.R
 	return f.svc.RouteDeleteMuteTiming(ctx, name)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetRecurringSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetRecurringSilences(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetRecurringSilence(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetRecurringSilence(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostRecurringSilence(ctx *contextmodel.ReqContext, silence apimodels.RecurringSilence) response.Response {
+	return f.svc.RoutePostRecurringSilence(ctx, silence)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutRecurringSilence(ctx *contextmodel.ReqContext, silence apimodels.RecurringSilence, UID string) response.Response {
+	return f.svc.RoutePutRecurringSilence(ctx, silence, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteRecurringSilence(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteRecurringSilence(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRuleTemplates(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTemplate(ctx *contextmodel.ReqContext, template apimodels.AlertRuleTemplate) response.Response {
+	return f.svc.RoutePostAlertRuleTemplate(ctx, template)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext, template apimodels.AlertRuleTemplate, UID string) response.Response {
+	return f.svc.RoutePutAlertRuleTemplate(ctx, template, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteAlertRuleTemplate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplateInstances(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplateInstances(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTemplateInstance(ctx *contextmodel.ReqContext, instance apimodels.AlertRuleTemplateInstance, UID string) response.Response {
+	return f.svc.RoutePostAlertRuleTemplateInstance(ctx, instance, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTemplateRollout(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RoutePostAlertRuleTemplateRollout(ctx, UID)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteGetAlertRules(ctx)
 }
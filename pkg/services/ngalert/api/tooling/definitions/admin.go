@@ -46,6 +46,7 @@ import (
 //     Responses:
 //       201: Ack
 //       400: ValidationError
+//       403: PermissionDenied
 
 // swagger:route DELETE /api/v1/ngalert/admin_config configuration RouteDeleteNGalertConfig
 //
@@ -77,11 +78,16 @@ const (
 // swagger:model
 type PostableNGalertConfig struct {
 	AlertmanagersChoice AlertmanagersChoice `json:"alertmanagersChoice"`
+	// The organization whose Grafana Alertmanager also receives the alerts of this organization, with the
+	// grafana_org_id label added. Set to 0 to stop forwarding alerts. The current value is kept if it is omitted.
+	// Only Grafana server administrators can change it.
+	ParentOrgID *int64 `json:"parentOrgId,omitempty"`
 }
 
 // swagger:model
 type GettableNGalertConfig struct {
 	AlertmanagersChoice AlertmanagersChoice `json:"alertmanagersChoice"`
+	ParentOrgID         int64               `json:"parentOrgId,omitempty"`
 }
 
 // swagger:model
@@ -14,6 +14,7 @@ import (
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 
@@ -140,6 +141,15 @@ import (
 //     Responses:
 //       200: receiversResponse
 
+// swagger:route GET /api/alertmanager/grafana/api/v1/notifications alertmanager RouteGetGrafanaNotificationHistory
+//
+// Get the attempts of the Grafana Alertmanager to deliver notifications, newest first.
+//
+//     Responses:
+//       200: NotificationHistory
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:route POST /api/alertmanager/grafana/config/api/v1/receivers/test alertmanager RoutePostTestGrafanaReceivers
 //
 // Test Grafana managed receivers without saving them.
@@ -154,6 +164,15 @@ import (
 //       408: Failure
 //       409: AlertManagerNotReady
 
+// swagger:route POST /api/alertmanager/grafana/config/api/v1/routes/test alertmanager RouteTestGrafanaRouting
+//
+// Find the notification policies that an alert with the given labels is routed to.
+//
+//     Responses:
+//       200: RoutingTestResult
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:route GET /api/alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -179,6 +198,14 @@ import (
 //       201: postSilencesOKBody
 //       400: ValidationError
 
+// swagger:route POST /api/alertmanager/grafana/api/v2/silences/preview alertmanager RoutePreviewGrafanaSilence
+//
+// Preview the alerts and notifications that a silence or a mute timing would affect, without creating it.
+//
+//     Responses:
+//       200: SilencePreview
+//       400: ValidationError
+
 // swagger:route POST /api/alertmanager/{DatasourceUID}/api/v2/silences alertmanager RouteCreateSilence
 //
 // create silence
@@ -278,6 +305,18 @@ type CreateSilenceParams struct {
 	Silence PostableSilence
 }
 
+// swagger:parameters RoutePreviewGrafanaSilence
+type PreviewSilenceParams struct {
+	// in:body
+	Body SilencePreviewRequest
+}
+
+// swagger:parameters RouteTestGrafanaRouting
+type TestRoutingParams struct {
+	// in:body
+	Body RoutingTestRequest
+}
+
 // swagger:parameters RouteGetSilence RouteDeleteSilence RouteGetGrafanaSilence RouteDeleteGrafanaSilence
 type GetDeleteSilenceParams struct {
 	// in:path
@@ -402,6 +441,153 @@ type ReceiversResponse struct {
 // swagger:model integration
 type Integration = amv2.Integration
 
+// swagger:parameters RouteGetGrafanaNotificationHistory
+type NotificationHistoryParams struct {
+	// Only return attempts of this receiver
+	// in: query
+	// required: false
+	Receiver string `json:"receiver"`
+
+	// Only return attempts of this type of integration, e.g. pagerduty
+	// in: query
+	// required: false
+	Integration string `json:"integration"`
+
+	// Only return attempts with this status
+	// in: query
+	// required: false
+	// enum: success,failed
+	Status string `json:"status"`
+
+	// Only return attempts of this aggregation group
+	// in: query
+	// required: false
+	GroupKey string `json:"groupKey"`
+
+	// Only return attempts that started at or after this time, in epoch milliseconds
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// Only return attempts that started at or before this time, in epoch milliseconds
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// The maximum number of attempts to return
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type NotificationHistory []NotificationAttempt
+
+// NotificationAttempt is a single attempt of an integration of a receiver to deliver a notification.
+// swagger:model
+type NotificationAttempt struct {
+	Receiver         string `json:"receiver"`
+	Integration      string `json:"integration"`
+	IntegrationIndex int    `json:"integrationIndex"`
+	IntegrationUID   string `json:"integrationUid,omitempty"`
+	GroupKey         string `json:"groupKey"`
+	// The fingerprints of the alerts in the notification
+	Fingerprints []string `json:"fingerprints"`
+	// enum: success,failed
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// How long the attempt took, in milliseconds
+	Duration int64 `json:"duration"`
+	// format: date-time
+	At time.Time `json:"at"`
+}
+
+// SilencePreviewRequest describes a silence or a mute timing that is previewed.
+// swagger:model
+type SilencePreviewRequest struct {
+	// Matchers of the silence. Alerts are affected if they match all matchers.
+	Matchers amv2.Matchers `json:"matchers,omitempty"`
+	// TimeIntervals of the mute timing. Notifications are affected if they fall into any of the time intervals.
+	// If both matchers and time intervals are given, both have to apply.
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals,omitempty"`
+	// How far back to look for notifications that would have been muted. Defaults to 24h.
+	// example: 24h
+	Lookback model.Duration `json:"lookback,omitempty"`
+}
+
+// SilencePreview lists what a silence or a mute timing would affect.
+// swagger:model
+type SilencePreview struct {
+	// The alerts that are currently firing and would be affected
+	Alerts []SilencePreviewAlert `json:"alerts"`
+	// The notifications within the lookback window that would have been muted, newest first
+	Notifications []SilencePreviewNotification `json:"notifications"`
+	// True if notifications cannot be previewed because the notification history is disabled.
+	// The notification history is enabled in the [unified_alerting.notification_history] section of the configuration.
+	NotificationHistoryDisabled bool `json:"notificationHistoryDisabled,omitempty"`
+}
+
+// swagger:model
+type SilencePreviewAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Fingerprint string            `json:"fingerprint"`
+	State       string            `json:"state"`
+	// format: date-time
+	ActiveAt time.Time `json:"activeAt"`
+}
+
+// swagger:model
+type SilencePreviewNotification struct {
+	Attempt NotificationAttempt `json:"attempt"`
+	// The fingerprints of the alerts in the notification that would have been muted
+	MutedFingerprints []string `json:"mutedFingerprints"`
+	// Whether all alerts of the notification would have been muted
+	Muted bool `json:"muted"`
+}
+
+// RoutingTestRequest describes the labels of an alert that is routed through the notification policies.
+// swagger:model
+type RoutingTestRequest struct {
+	// The labels of the alert.
+	Labels map[string]string `json:"labels"`
+	// A configuration that is used instead of the saved configuration of the organization, for example to
+	// test changes to the notification policies before saving them.
+	AlertmanagerConfig *PostableApiAlertingConfig `json:"alertmanager_config,omitempty"`
+}
+
+// RoutingTestResult lists the notification policies that an alert is routed to, in the order the
+// Alertmanager matches them.
+// swagger:model
+type RoutingTestResult struct {
+	Matches []RoutingTestMatch `json:"matches"`
+}
+
+// RoutingTestMatch is a notification policy that an alert is routed to, with the settings that are
+// inherited from its parents applied.
+// swagger:model
+type RoutingTestMatch struct {
+	// The policies from the root of the tree to the matched policy.
+	Path           []RoutingTestPolicy `json:"path"`
+	Receiver       string              `json:"receiver"`
+	GroupBy        []string            `json:"group_by"`
+	GroupWait      model.Duration      `json:"group_wait"`
+	GroupInterval  model.Duration      `json:"group_interval"`
+	RepeatInterval model.Duration      `json:"repeat_interval"`
+	// The mute timings of the matched policy.
+	MuteTimeIntervals []string `json:"mute_time_intervals"`
+	// The mute timings of the matched policy that are currently active.
+	ActiveMuteTimeIntervals []string `json:"active_mute_time_intervals"`
+}
+
+// RoutingTestPolicy is a notification policy on the path to a matched policy.
+// swagger:model
+type RoutingTestPolicy struct {
+	Receiver string   `json:"receiver"`
+	Matchers []string `json:"matchers"`
+	Continue bool     `json:"continue"`
+}
+
 // swagger:parameters RouteGetAMAlerts RouteGetAMAlertGroups RouteGetGrafanaAMAlerts RouteGetGrafanaAMAlertGroups
 type AlertsParams struct {
 
@@ -7,9 +7,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-openapi/strfmt"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/common/model"
+	"github.com/robfig/cron/v3"
 	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 // Validate normalizes a possibly nested Route r, and returns errors if r is invalid.
@@ -142,3 +147,97 @@ func (mt *MuteTimeInterval) Validate() error {
 	}
 	return nil
 }
+
+// Validate returns an error if the recurring silence would not create valid silences or if it does not have
+// exactly one kind of schedule.
+func (s *RecurringSilence) Validate() error {
+	if s.Comment == "" {
+		return fmt.Errorf("comment must not be empty")
+	}
+	if s.CreatedBy == "" {
+		return fmt.Errorf("createdBy must not be empty")
+	}
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("at least one matcher is required")
+	}
+	allMatchEmpty := true
+	for _, m := range s.Matchers {
+		if m.Name == models.RecurringSilenceUIDLabel {
+			return fmt.Errorf("matcher %s is reserved", m.Name)
+		}
+		allMatchEmpty = allMatchEmpty && m.Matches("")
+	}
+	if allMatchEmpty {
+		return fmt.Errorf("at least one matcher must not match the empty string")
+	}
+
+	hasCron := s.Cron != ""
+	if hasCron == (len(s.TimeIntervals) > 0) {
+		return fmt.Errorf("either a cron expression or time intervals must be set")
+	}
+	if !hasCron {
+		if s.Duration != 0 {
+			return fmt.Errorf("duration can only be used with a cron expression")
+		}
+		return nil
+	}
+	if _, err := cron.ParseStandard(s.Cron); err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", s.Cron, err)
+	}
+	if s.Duration <= 0 {
+		return fmt.Errorf("duration must be greater than zero")
+	}
+	return nil
+}
+
+// Validate returns an error if the preview has neither matchers nor time intervals or if a matcher is invalid.
+func (r *SilencePreviewRequest) Validate() error {
+	if len(r.Matchers) == 0 && len(r.TimeIntervals) == 0 {
+		return fmt.Errorf("at least one matcher or time interval is required")
+	}
+	if _, err := r.LabelMatchers(); err != nil {
+		return err
+	}
+	if r.Lookback < 0 {
+		return fmt.Errorf("lookback must not be negative")
+	}
+	return nil
+}
+
+// LabelMatchers converts the matchers of the previewed silence to label matchers.
+func (r *SilencePreviewRequest) LabelMatchers() (labels.Matchers, error) {
+	result := make(labels.Matchers, 0, len(r.Matchers))
+	for _, m := range r.Matchers {
+		if err := m.Validate(strfmt.Default); err != nil {
+			return nil, fmt.Errorf("invalid matcher: %w", err)
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		var t labels.MatchType
+		switch {
+		case *m.IsRegex && isEqual:
+			t = labels.MatchRegexp
+		case *m.IsRegex:
+			t = labels.MatchNotRegexp
+		case isEqual:
+			t = labels.MatchEqual
+		default:
+			t = labels.MatchNotEqual
+		}
+		matcher, err := labels.NewMatcher(t, *m.Name, *m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %s: %w", *m.Name, err)
+		}
+		result = append(result, matcher)
+	}
+	return result, nil
+}
+
+func (r *RoutingTestRequest) Validate() error {
+	if len(r.Labels) == 0 {
+		return fmt.Errorf("at least one label is required")
+	}
+	if r.AlertmanagerConfig != nil && r.AlertmanagerConfig.Route == nil {
+		return fmt.Errorf("the configuration must have a root route")
+	}
+	return nil
+}
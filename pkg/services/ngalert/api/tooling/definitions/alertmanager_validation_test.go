@@ -2,8 +2,10 @@ package definitions
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
@@ -365,3 +367,114 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateRecurringSilence(t *testing.T) {
+	matcher := func(mt labels.MatchType, name, value string) *labels.Matcher {
+		m, err := labels.NewMatcher(mt, name, value)
+		require.NoError(t, err)
+		return m
+	}
+	weekend := []timeinterval.TimeInterval{
+		{
+			Weekdays: []timeinterval.WeekdayRange{
+				{InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}},
+				{InclusiveRange: timeinterval.InclusiveRange{Begin: 6, End: 6}},
+			},
+		},
+	}
+	valid := func() RecurringSilence {
+		return RecurringSilence{
+			UID:       "maintenance",
+			Comment:   "weekly maintenance",
+			CreatedBy: "ops",
+			Matchers:  ObjectMatchers{matcher(labels.MatchEqual, "cluster", "eu-1")},
+			Cron:      "0 22 * * SAT",
+			Duration:  model.Duration(4 * time.Hour),
+		}
+	}
+
+	t.Run("valid recurring silence", func(t *testing.T) {
+		cases := map[string]func(s *RecurringSilence){
+			"cron":               func(s *RecurringSilence) {},
+			"cron with location": func(s *RecurringSilence) { s.Cron = "CRON_TZ=Europe/Berlin 0 22 * * SAT" },
+			"time intervals": func(s *RecurringSilence) {
+				s.Cron = ""
+				s.Duration = 0
+				s.TimeIntervals = weekend
+			},
+		}
+		for desc, mutate := range cases {
+			t.Run(desc, func(t *testing.T) {
+				s := valid()
+				mutate(&s)
+				require.NoError(t, s.Validate())
+			})
+		}
+	})
+
+	t.Run("invalid recurring silence", func(t *testing.T) {
+		cases := []struct {
+			desc   string
+			mutate func(s *RecurringSilence)
+			expMsg string
+		}{
+			{
+				desc:   "no comment",
+				mutate: func(s *RecurringSilence) { s.Comment = "" },
+				expMsg: "comment must not be empty",
+			},
+			{
+				desc:   "no matchers",
+				mutate: func(s *RecurringSilence) { s.Matchers = nil },
+				expMsg: "at least one matcher is required",
+			},
+			{
+				desc:   "matchers match everything",
+				mutate: func(s *RecurringSilence) { s.Matchers = ObjectMatchers{matcher(labels.MatchRegexp, "cluster", ".*")} },
+				expMsg: "must not match the empty string",
+			},
+			{
+				desc: "reserved matcher",
+				mutate: func(s *RecurringSilence) {
+					s.Matchers = append(s.Matchers, matcher(labels.MatchNotEqual, "__grafana_recurring_silence_uid__", "other"))
+				},
+				expMsg: "matcher __grafana_recurring_silence_uid__ is reserved",
+			},
+			{
+				desc:   "no schedule",
+				mutate: func(s *RecurringSilence) { s.Cron = "" },
+				expMsg: "either a cron expression or time intervals must be set",
+			},
+			{
+				desc:   "cron and time intervals",
+				mutate: func(s *RecurringSilence) { s.TimeIntervals = weekend },
+				expMsg: "either a cron expression or time intervals must be set",
+			},
+			{
+				desc:   "invalid cron",
+				mutate: func(s *RecurringSilence) { s.Cron = "0 22 * *" },
+				expMsg: "invalid cron expression",
+			},
+			{
+				desc:   "cron without duration",
+				mutate: func(s *RecurringSilence) { s.Duration = 0 },
+				expMsg: "duration must be greater than zero",
+			},
+			{
+				desc: "time intervals with duration",
+				mutate: func(s *RecurringSilence) {
+					s.Cron = ""
+					s.TimeIntervals = weekend
+				},
+				expMsg: "duration can only be used with a cron expression",
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				s := valid()
+				c.mutate(&s)
+				require.ErrorContains(t, s.Validate(), c.expMsg)
+			})
+		}
+	})
+}
@@ -260,12 +260,13 @@ func (c *GettableRuleGroupConfig) validate() error {
 }
 
 type ApiRuleNode struct {
-	Record      string            `yaml:"record,omitempty" json:"record,omitempty"`
-	Alert       string            `yaml:"alert,omitempty" json:"alert,omitempty"`
-	Expr        string            `yaml:"expr" json:"expr"`
-	For         *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
-	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	Record        string            `yaml:"record,omitempty" json:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty" json:"alert,omitempty"`
+	Expr          string            `yaml:"expr" json:"expr"`
+	For           *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
+	KeepFiringFor *model.Duration   `yaml:"keep_firing_for,omitempty" json:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 }
 
 type RuleType int
@@ -374,6 +375,8 @@ type PostableGrafanaRule struct {
 	UID          string              `json:"uid" yaml:"uid"`
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
+	Record       *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+	DependsOn    []RuleDependency    `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // swagger:model
@@ -393,4 +396,32 @@ type GettableGrafanaRule struct {
 	NoDataState     NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	Provenance      models.Provenance   `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+	Record          *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+	DependsOn       []RuleDependency    `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+}
+
+// Record defines how the result of a recording rule is written.
+// swagger:model
+type Record struct {
+	// Name of the metric the result of the rule is written to.
+	// required: true
+	// example: grafana_requests:rate5m
+	Metric string `json:"metric" yaml:"metric"`
+	// RefID of the query or expression whose result is written.
+	// required: true
+	// example: A
+	From string `json:"from" yaml:"from"`
+}
+
+// RuleDependency defines a dependency of a rule on another rule of the same rule group.
+// The rule is evaluated after the upstream rule and can fire only while the upstream rule is in the given state.
+// swagger:model
+type RuleDependency struct {
+	// UID of the upstream rule. It must be a rule of the same rule group.
+	// required: true
+	RuleUID string `json:"rule_uid" yaml:"rule_uid"`
+	// State the upstream rule must be in for the rule to fire.
+	// required: true
+	// enum: Normal, Pending, Alerting
+	State string `json:"state" yaml:"state"`
 }
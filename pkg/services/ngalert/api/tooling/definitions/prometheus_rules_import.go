@@ -0,0 +1,83 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /api/ruler/grafana/api/v1/import/prometheus ruler RouteImportPrometheusRules
+//
+// Imports rule groups in the Prometheus rule file format as Grafana-managed alert rules.
+// Rules that already exist in the rule group are matched by title and updated.
+// Rules of an imported group that are not in the request are deleted.
+// The changes are saved only if the request commits them, otherwise they are only returned.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: PrometheusRulesImportResult
+//       202: PrometheusRulesImportResult
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RouteImportPrometheusRules
+type ImportPrometheusRulesParams struct {
+	// in:body
+	Body PostablePrometheusRulesImport
+}
+
+// swagger:model
+type PostablePrometheusRulesImport struct {
+	// Title of the folder the rule groups are imported into.
+	// required: true
+	Namespace string `json:"namespace"`
+	// UID of the Prometheus data source that evaluates the expressions of the rules.
+	// required: true
+	DatasourceUID string `json:"datasourceUid"`
+	// If true, the changes are saved. Otherwise, they are calculated and returned but not saved.
+	Commit bool `json:"commit,omitempty"`
+	// required: true
+	Groups []PrometheusRuleGroup `json:"groups"`
+}
+
+// PrometheusRuleGroup is a rule group in the Prometheus rule file format.
+// swagger:model
+type PrometheusRuleGroup struct {
+	Name     string         `yaml:"name" json:"name"`
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Rules    []ApiRuleNode  `yaml:"rules" json:"rules"`
+}
+
+// PrometheusRuleFile is the content of a Prometheus rule file.
+type PrometheusRuleFile struct {
+	Groups []PrometheusRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// swagger:model
+type PrometheusRulesImportResult struct {
+	// True if the changes were not saved.
+	DryRun bool                              `json:"dryRun"`
+	Groups []PrometheusRuleGroupImportResult `json:"groups"`
+}
+
+// PrometheusRuleGroupImportResult describes the changes made to a rule group by the import.
+// swagger:model
+type PrometheusRuleGroupImportResult struct {
+	Name    string                       `json:"name"`
+	Created []PrometheusRuleImportChange `json:"created,omitempty"`
+	Updated []PrometheusRuleImportChange `json:"updated,omitempty"`
+	Deleted []PrometheusRuleImportChange `json:"deleted,omitempty"`
+}
+
+// PrometheusRuleImportChange describes a change of a single alert rule.
+// swagger:model
+type PrometheusRuleImportChange struct {
+	// UID of the rule. Empty for rules that are created.
+	UID   string `json:"uid,omitempty"`
+	Title string `json:"title"`
+	// Fields of the rule that are changed by the import.
+	Diff []string `json:"diff,omitempty"`
+}
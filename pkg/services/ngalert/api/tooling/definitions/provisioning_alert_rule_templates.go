@@ -0,0 +1,374 @@
+package definitions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/v1/provisioning/alert-rule-templates provisioning stable RouteGetAlertRuleTemplates
+//
+// Get all the alert rule templates.
+//
+//     Responses:
+//       200: AlertRuleTemplates
+
+// swagger:route GET /api/v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteGetAlertRuleTemplate
+//
+// Get an alert rule template.
+//
+//     Responses:
+//       200: AlertRuleTemplate
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/alert-rule-templates provisioning stable RoutePostAlertRuleTemplate
+//
+// Create a new alert rule template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: AlertRuleTemplate
+//       400: ValidationError
+
+// swagger:route PUT /api/v1/provisioning/alert-rule-templates/{UID} provisioning stable RoutePutAlertRuleTemplate
+//
+// Replace an existing alert rule template and roll out the new version to the alert rules created from it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: AlertRuleTemplate
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route DELETE /api/v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteDeleteAlertRuleTemplate
+//
+// Delete an alert rule template. The alert rules created from it are kept but no longer updated.
+//
+//     Responses:
+//       204: description: The alert rule template was deleted successfully.
+
+// swagger:route GET /api/v1/provisioning/alert-rule-templates/{UID}/instances provisioning stable RouteGetAlertRuleTemplateInstances
+//
+// Get the alert rules created from an alert rule template.
+//
+//     Responses:
+//       200: AlertRuleTemplateInstances
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/alert-rule-templates/{UID}/instances provisioning stable RoutePostAlertRuleTemplateInstance
+//
+// Create a new alert rule from an alert rule template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedAlertRule
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/alert-rule-templates/{UID}/rollout provisioning stable RoutePostAlertRuleTemplateRollout
+//
+// Update the alert rules that were not updated to the current version of an alert rule template.
+//
+//     Responses:
+//       200: AlertRuleTemplateRollout
+//       404: description: Not found.
+
+// swagger:parameters RouteGetAlertRuleTemplate RoutePutAlertRuleTemplate RouteDeleteAlertRuleTemplate RouteGetAlertRuleTemplateInstances RoutePostAlertRuleTemplateInstance RoutePostAlertRuleTemplateRollout
+type AlertRuleTemplateUIDParam struct {
+	// Alert rule template UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostAlertRuleTemplate RoutePutAlertRuleTemplate
+type AlertRuleTemplatePayload struct {
+	// in:body
+	Body AlertRuleTemplate
+}
+
+// swagger:parameters RoutePostAlertRuleTemplateInstance
+type AlertRuleTemplateInstancePayload struct {
+	// in:body
+	Body AlertRuleTemplateInstance
+}
+
+// swagger:parameters RoutePostAlertRuleTemplate RoutePutAlertRuleTemplate RoutePostAlertRuleTemplateInstance
+type AlertRuleTemplateHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type AlertRuleTemplates []AlertRuleTemplate
+
+// AlertRuleTemplate is an alert rule with parameters. A parameter is referenced as ${name} in any string of the
+// rule. The reference is replaced with the value of the parameter when an alert rule is created from the template,
+// and a string in the queries of the rule that only contains the reference to a number parameter is replaced
+// with the number.
+//
+// swagger:model
+type AlertRuleTemplate struct {
+	// UID of the alert rule template. A UID is generated if it is empty when the template is created.
+	UID string `json:"uid"`
+	// required: true
+	// example: Disk full
+	Title       string                       `json:"title"`
+	Description string                       `json:"description,omitempty"`
+	Parameters  []AlertRuleTemplateParameter `json:"parameters"`
+	// Rule in the same format as ProvisionedAlertRule. The uid and orgID of the rule are ignored.
+	// required: true
+	// example: {"folderUID":"${folder}","ruleGroup":"disk","title":"Disk full","condition":"B","data":[{"refId":"A","datasourceUid":"${datasource}","model":{"expr":"disk_used_percent"}},{"refId":"B","datasourceUid":"__expr__","model":{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":["${threshold}"]}}]}}],"noDataState":"NoData","execErrState":"Error","for":"5m","labels":{"team":"${team}"}}
+	Rule json.RawMessage `json:"rule"`
+	// Version of the template. It is incremented every time the template is updated.
+	// readonly: true
+	Version int64 `json:"version"`
+	// readonly: true
+	Updated    time.Time         `json:"updated,omitempty"`
+	Provenance models.Provenance `json:"provenance,omitempty"`
+}
+
+// AlertRuleTemplateParameterType is the type of the value of a parameter.
+type AlertRuleTemplateParameterType string
+
+const (
+	AlertRuleTemplateParameterTypeString AlertRuleTemplateParameterType = "string"
+	AlertRuleTemplateParameterTypeNumber AlertRuleTemplateParameterType = "number"
+)
+
+type AlertRuleTemplateParameter struct {
+	// required: true
+	// example: threshold
+	Name string `json:"name"`
+	// Type of the parameter, either string or number. Defaults to string.
+	// example: number
+	Type        AlertRuleTemplateParameterType `json:"type,omitempty"`
+	Description string                         `json:"description,omitempty"`
+	// Default value of the parameter. Parameters without a default value are required.
+	// example: 90
+	Default *string `json:"default,omitempty"`
+}
+
+// swagger:model
+type AlertRuleTemplateInstances []AlertRuleTemplateInstance
+
+// AlertRuleTemplateInstance is an alert rule created from a template.
+//
+// swagger:model
+type AlertRuleTemplateInstance struct {
+	// UID of the alert rule. A UID is generated if it is empty when the alert rule is created.
+	RuleUID string `json:"ruleUID"`
+	// Values of the parameters of the template.
+	// example: {"folder": "team-a", "datasource": "P1809F7CD0C75ACF3", "threshold": "95", "team": "team-a"}
+	Values map[string]string `json:"values"`
+	// Version of the template the alert rule was last updated to.
+	// readonly: true
+	TemplateVersion int64 `json:"templateVersion"`
+}
+
+// AlertRuleTemplateRollout is the result of updating the alert rules created from a template to its current version.
+//
+// swagger:model
+type AlertRuleTemplateRollout struct {
+	Version int64 `json:"version"`
+	// UIDs of the alert rules that were updated.
+	Updated []string `json:"updated"`
+	// Alert rules that could not be updated. They are updated by the next rollout.
+	Failed []AlertRuleTemplateRolloutFailure `json:"failed"`
+}
+
+type AlertRuleTemplateRolloutFailure struct {
+	RuleUID string `json:"ruleUID"`
+	Error   string `json:"error"`
+}
+
+var (
+	alertRuleTemplateParamName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	alertRuleTemplateParamRef  = regexp.MustCompile(`\$\{([^}]*)\}`)
+)
+
+func (t *AlertRuleTemplate) ResourceType() string {
+	return "alertRuleTemplate"
+}
+
+func (t *AlertRuleTemplate) ResourceID() string {
+	return t.UID
+}
+
+// Validate returns an error if the template has no title, a parameter is invalid, or the rule is not a JSON
+// object or references a parameter that is not defined.
+func (t *AlertRuleTemplate) Validate() error {
+	if t.Title == "" {
+		return fmt.Errorf("title must not be empty")
+	}
+	params := make(map[string]struct{}, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if !alertRuleTemplateParamName.MatchString(p.Name) {
+			return fmt.Errorf("invalid parameter name %q: it must only contain letters, digits and underscores and not start with a digit", p.Name)
+		}
+		if _, ok := params[p.Name]; ok {
+			return fmt.Errorf("parameter %q is defined more than once", p.Name)
+		}
+		params[p.Name] = struct{}{}
+		switch p.Type {
+		case "", AlertRuleTemplateParameterTypeString:
+		case AlertRuleTemplateParameterTypeNumber:
+			if p.Default != nil {
+				if _, err := strconv.ParseFloat(*p.Default, 64); err != nil {
+					return fmt.Errorf("default value of parameter %q must be a number", p.Name)
+				}
+			}
+		default:
+			return fmt.Errorf("parameter %q has unknown type %q", p.Name, p.Type)
+		}
+	}
+
+	var rule map[string]interface{}
+	if err := json.Unmarshal(t.Rule, &rule); err != nil || rule == nil {
+		return fmt.Errorf("rule must be a JSON object")
+	}
+	for _, match := range alertRuleTemplateParamRef.FindAllSubmatch(t.Rule, -1) {
+		if _, ok := params[string(match[1])]; !ok {
+			return fmt.Errorf("rule references undefined parameter %q", match[1])
+		}
+	}
+	return nil
+}
+
+// Render returns the alert rule of the template with the references to parameters replaced with the values.
+// Missing values are replaced with the default value of the parameter. It returns an error if a value is missing
+// for a parameter without a default value, a value is not a valid number for a number parameter, or a value is
+// given for an unknown parameter.
+func (t *AlertRuleTemplate) Render(values map[string]string) (ProvisionedAlertRule, error) {
+	params := make(map[string]AlertRuleTemplateParameter, len(t.Parameters))
+	resolved := make(map[string]string, len(t.Parameters))
+	for _, p := range t.Parameters {
+		params[p.Name] = p
+		v, ok := values[p.Name]
+		if !ok {
+			if p.Default == nil {
+				return ProvisionedAlertRule{}, fmt.Errorf("missing value for parameter %q", p.Name)
+			}
+			v = *p.Default
+		}
+		if p.Type == AlertRuleTemplateParameterTypeNumber {
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return ProvisionedAlertRule{}, fmt.Errorf("value of parameter %q must be a number", p.Name)
+			}
+		}
+		resolved[p.Name] = v
+	}
+	for name := range values {
+		if _, ok := params[name]; !ok {
+			return ProvisionedAlertRule{}, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(t.Rule))
+	// Keep numbers as they are written in the template.
+	dec.UseNumber()
+	var rule map[string]interface{}
+	if err := dec.Decode(&rule); err != nil {
+		return ProvisionedAlertRule{}, fmt.Errorf("failed to decode rule: %w", err)
+	}
+	for k, v := range rule {
+		// Only the models of the queries can contain numbers, all other fields of the rule are strings.
+		rule[k] = renderAlertRuleTemplateValue(v, params, resolved, k == "data")
+	}
+
+	b, err := json.Marshal(rule)
+	if err != nil {
+		return ProvisionedAlertRule{}, fmt.Errorf("failed to encode rule: %w", err)
+	}
+	var result ProvisionedAlertRule
+	if err := json.Unmarshal(b, &result); err != nil {
+		return ProvisionedAlertRule{}, fmt.Errorf("invalid rule: %w", err)
+	}
+	return result, nil
+}
+
+func renderAlertRuleTemplateValue(v interface{}, params map[string]AlertRuleTemplateParameter, values map[string]string, numbers bool) interface{} {
+	switch v := v.(type) {
+	case string:
+		if match := alertRuleTemplateParamRef.FindStringSubmatch(v); numbers && match != nil && match[0] == v {
+			if params[match[1]].Type == AlertRuleTemplateParameterTypeNumber {
+				return json.Number(values[match[1]])
+			}
+		}
+		return alertRuleTemplateParamRef.ReplaceAllStringFunc(v, func(ref string) string {
+			if value, ok := values[ref[2:len(ref)-1]]; ok {
+				return value
+			}
+			return ref
+		})
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = renderAlertRuleTemplateValue(item, params, values, numbers)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = renderAlertRuleTemplateValue(item, params, values, numbers)
+		}
+		return v
+	}
+	return v
+}
+
+// UpstreamModel converts the alert rule template to the model that is stored in the database.
+func (t *AlertRuleTemplate) UpstreamModel(orgID int64) (models.AlertRuleTemplate, error) {
+	params := t.Parameters
+	if params == nil {
+		params = []AlertRuleTemplateParameter{}
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return models.AlertRuleTemplate{}, fmt.Errorf("failed to encode parameters: %w", err)
+	}
+	return models.AlertRuleTemplate{
+		OrgID:       orgID,
+		UID:         t.UID,
+		Title:       t.Title,
+		Description: t.Description,
+		Parameters:  string(b),
+		Rule:        string(t.Rule),
+		Version:     t.Version,
+	}, nil
+}
+
+func NewAlertRuleTemplate(template models.AlertRuleTemplate, provenance models.Provenance) (AlertRuleTemplate, error) {
+	result := AlertRuleTemplate{
+		UID:         template.UID,
+		Title:       template.Title,
+		Description: template.Description,
+		Rule:        json.RawMessage(template.Rule),
+		Version:     template.Version,
+		Updated:     template.Updated,
+		Provenance:  provenance,
+	}
+	if err := json.Unmarshal([]byte(template.Parameters), &result.Parameters); err != nil {
+		return AlertRuleTemplate{}, fmt.Errorf("failed to decode parameters of alert rule template %s: %w", template.UID, err)
+	}
+	return result, nil
+}
+
+func NewAlertRuleTemplateInstance(instance models.AlertRuleTemplateInstance) (AlertRuleTemplateInstance, error) {
+	result := AlertRuleTemplateInstance{
+		RuleUID:         instance.RuleUID,
+		TemplateVersion: instance.TemplateVersion,
+	}
+	if err := json.Unmarshal([]byte(instance.Values), &result.Values); err != nil {
+		return AlertRuleTemplateInstance{}, fmt.Errorf("failed to decode values of alert rule %s: %w", instance.RuleUID, err)
+	}
+	return result, nil
+}
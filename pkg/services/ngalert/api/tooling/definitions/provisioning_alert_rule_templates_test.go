@@ -0,0 +1,151 @@
+package definitions
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func createAlertRuleTemplate() AlertRuleTemplate {
+	threshold := "90"
+	return AlertRuleTemplate{
+		Title: "Disk full",
+		Parameters: []AlertRuleTemplateParameter{
+			{Name: "folder"},
+			{Name: "datasource"},
+			{Name: "threshold", Type: AlertRuleTemplateParameterTypeNumber, Default: &threshold},
+			{Name: "team"},
+		},
+		Rule: json.RawMessage(`{
+			"folderUID": "${folder}",
+			"ruleGroup": "disk",
+			"title": "Disk full on ${team} hosts",
+			"condition": "B",
+			"data": [
+				{"refId": "A", "datasourceUid": "${datasource}", "model": {"expr": "disk_used_percent"}},
+				{"refId": "B", "datasourceUid": "__expr__", "model": {"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": ["${threshold}"]}}]}}
+			],
+			"noDataState": "NoData",
+			"execErrState": "Error",
+			"for": "5m",
+			"labels": {"team": "${team}", "threshold": "${threshold}"}
+		}`),
+	}
+}
+
+func TestValidateAlertRuleTemplate(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(*AlertRuleTemplate)
+		err    string
+	}{
+		{
+			name:   "valid template",
+			mutate: func(*AlertRuleTemplate) {},
+		},
+		{
+			name:   "missing title",
+			mutate: func(tmpl *AlertRuleTemplate) { tmpl.Title = "" },
+			err:    "title must not be empty",
+		},
+		{
+			name:   "invalid parameter name",
+			mutate: func(tmpl *AlertRuleTemplate) { tmpl.Parameters[0].Name = "1folder" },
+			err:    `invalid parameter name "1folder": it must only contain letters, digits and underscores and not start with a digit`,
+		},
+		{
+			name:   "duplicate parameter",
+			mutate: func(tmpl *AlertRuleTemplate) { tmpl.Parameters[1].Name = "folder" },
+			err:    `parameter "folder" is defined more than once`,
+		},
+		{
+			name:   "unknown parameter type",
+			mutate: func(tmpl *AlertRuleTemplate) { tmpl.Parameters[0].Type = "bool" },
+			err:    `parameter "folder" has unknown type "bool"`,
+		},
+		{
+			name: "default of number parameter is not a number",
+			mutate: func(tmpl *AlertRuleTemplate) {
+				d := "high"
+				tmpl.Parameters[2].Default = &d
+			},
+			err: `default value of parameter "threshold" must be a number`,
+		},
+		{
+			name:   "rule is not an object",
+			mutate: func(tmpl *AlertRuleTemplate) { tmpl.Rule = json.RawMessage(`[]`) },
+			err:    "rule must be a JSON object",
+		},
+		{
+			name:   "rule references undefined parameter",
+			mutate: func(tmpl *AlertRuleTemplate) { tmpl.Parameters = tmpl.Parameters[1:] },
+			err:    `rule references undefined parameter "folder"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmpl := createAlertRuleTemplate()
+			c.mutate(&tmpl)
+			err := tmpl.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestRenderAlertRuleTemplate(t *testing.T) {
+	t.Run("replaces references with values", func(t *testing.T) {
+		tmpl := createAlertRuleTemplate()
+		rule, err := tmpl.Render(map[string]string{"folder": "team-a", "datasource": "prom", "threshold": "95.5", "team": "a"})
+		require.NoError(t, err)
+
+		require.Equal(t, "team-a", rule.FolderUID)
+		require.Equal(t, "Disk full on a hosts", rule.Title)
+		require.Equal(t, "prom", rule.Data[0].DatasourceUID)
+		require.JSONEq(t, `{"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [95.5]}}]}`, string(rule.Data[1].Model))
+		require.Equal(t, map[string]string{"team": "a", "threshold": "95.5"}, rule.Labels)
+		require.Equal(t, model.Duration(5*time.Minute), rule.For)
+		require.Equal(t, models.NoData, rule.NoDataState)
+	})
+
+	t.Run("uses default values", func(t *testing.T) {
+		tmpl := createAlertRuleTemplate()
+		rule, err := tmpl.Render(map[string]string{"folder": "team-a", "datasource": "prom", "team": "a"})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [90]}}]}`, string(rule.Data[1].Model))
+	})
+
+	t.Run("fails if a required value is missing", func(t *testing.T) {
+		tmpl := createAlertRuleTemplate()
+		_, err := tmpl.Render(map[string]string{"folder": "team-a", "datasource": "prom"})
+		require.EqualError(t, err, `missing value for parameter "team"`)
+	})
+
+	t.Run("fails if a number value is not a number", func(t *testing.T) {
+		tmpl := createAlertRuleTemplate()
+		_, err := tmpl.Render(map[string]string{"folder": "team-a", "datasource": "prom", "team": "a", "threshold": "high"})
+		require.EqualError(t, err, `value of parameter "threshold" must be a number`)
+	})
+
+	t.Run("fails for unknown parameters", func(t *testing.T) {
+		tmpl := createAlertRuleTemplate()
+		_, err := tmpl.Render(map[string]string{"folder": "team-a", "datasource": "prom", "team": "a", "severity": "critical"})
+		require.EqualError(t, err, `unknown parameter "severity"`)
+	})
+
+	t.Run("does not modify the template", func(t *testing.T) {
+		tmpl := createAlertRuleTemplate()
+		before := string(tmpl.Rule)
+		_, err := tmpl.Render(map[string]string{"folder": "team-a", "datasource": "prom", "team": "a"})
+		require.NoError(t, err)
+		require.Equal(t, before, string(tmpl.Rule))
+	})
+}
@@ -1,6 +1,7 @@
 package definitions
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -114,7 +115,7 @@ type ProvisionedAlertRule struct {
 	// maxLength: 190
 	// example: Always firing
 	Title string `json:"title"`
-	// required: true
+	// Condition is required for alerting rules, and must be empty for recording rules.
 	// example: A
 	Condition string `json:"condition"`
 	// required: true
@@ -128,6 +129,9 @@ type ProvisionedAlertRule struct {
 	ExecErrState models.ExecutionErrorState `json:"execErrState"`
 	// required: true
 	For model.Duration `json:"for"`
+	// KeepFiringFor is how long an alert keeps firing after its condition stopped being met.
+	// example: 5m
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
@@ -136,46 +140,96 @@ type ProvisionedAlertRule struct {
 	Provenance models.Provenance `json:"provenance,omitempty"`
 	// example: false
 	IsPaused bool `json:"isPaused"`
+	// Record is set if the rule is a recording rule.
+	Record *Record `json:"record,omitempty"`
+	// DependsOn is the list of rules of the same rule group this rule depends on.
+	DependsOn []RuleDependency `json:"dependsOn,omitempty"`
 }
 
 func (a *ProvisionedAlertRule) UpstreamModel() (models.AlertRule, error) {
+	var record models.Record
+	if a.Record != nil {
+		record = models.Record{
+			Metric: a.Record.Metric,
+			From:   a.Record.From,
+		}
+		if err := record.Validate(); err != nil {
+			return models.AlertRule{}, err
+		}
+		if a.Condition != "" {
+			return models.AlertRule{}, fmt.Errorf("%w: recording rule cannot have a condition", models.ErrAlertRuleFailedValidation)
+		}
+		if a.KeepFiringFor > 0 {
+			return models.AlertRule{}, fmt.Errorf("%w: recording rule cannot have field `keepFiringFor`", models.ErrAlertRuleFailedValidation)
+		}
+	}
+	var dependsOn []models.RuleDependency
+	for _, dep := range a.DependsOn {
+		dependsOn = append(dependsOn, models.RuleDependency{
+			RuleUID: dep.RuleUID,
+			State:   models.RuleDependencyState(dep.State),
+		})
+	}
+	if err := models.ValidateRuleDependencies(a.UID, dependsOn); err != nil {
+		return models.AlertRule{}, err
+	}
 	return models.AlertRule{
-		ID:           a.ID,
-		UID:          a.UID,
-		OrgID:        a.OrgID,
-		NamespaceUID: a.FolderUID,
-		RuleGroup:    a.RuleGroup,
-		Title:        a.Title,
-		Condition:    a.Condition,
-		Data:         a.Data,
-		Updated:      a.Updated,
-		NoDataState:  a.NoDataState,
-		ExecErrState: a.ExecErrState,
-		For:          time.Duration(a.For),
-		Annotations:  a.Annotations,
-		Labels:       a.Labels,
-		IsPaused:     a.IsPaused,
+		ID:            a.ID,
+		UID:           a.UID,
+		OrgID:         a.OrgID,
+		NamespaceUID:  a.FolderUID,
+		RuleGroup:     a.RuleGroup,
+		Title:         a.Title,
+		Condition:     a.Condition,
+		Data:          a.Data,
+		Updated:       a.Updated,
+		NoDataState:   a.NoDataState,
+		ExecErrState:  a.ExecErrState,
+		For:           time.Duration(a.For),
+		KeepFiringFor: time.Duration(a.KeepFiringFor),
+		Annotations:   a.Annotations,
+		Labels:        a.Labels,
+		IsPaused:      a.IsPaused,
+		Record:        record,
+		DependsOn:     dependsOn,
 	}, nil
 }
 
 func NewAlertRule(rule models.AlertRule, provenance models.Provenance) ProvisionedAlertRule {
+	var record *Record
+	if !rule.Record.IsEmpty() {
+		record = &Record{
+			Metric: rule.Record.Metric,
+			From:   rule.Record.From,
+		}
+	}
+	var dependsOn []RuleDependency
+	for _, dep := range rule.DependsOn {
+		dependsOn = append(dependsOn, RuleDependency{
+			RuleUID: dep.RuleUID,
+			State:   string(dep.State),
+		})
+	}
 	return ProvisionedAlertRule{
-		ID:           rule.ID,
-		UID:          rule.UID,
-		OrgID:        rule.OrgID,
-		FolderUID:    rule.NamespaceUID,
-		RuleGroup:    rule.RuleGroup,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         rule.Data,
-		Updated:      rule.Updated,
-		NoDataState:  rule.NoDataState,
-		ExecErrState: rule.ExecErrState,
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		Provenance:   provenance,
-		IsPaused:     rule.IsPaused,
+		ID:            rule.ID,
+		UID:           rule.UID,
+		OrgID:         rule.OrgID,
+		FolderUID:     rule.NamespaceUID,
+		RuleGroup:     rule.RuleGroup,
+		Title:         rule.Title,
+		For:           model.Duration(rule.For),
+		KeepFiringFor: model.Duration(rule.KeepFiringFor),
+		Condition:     rule.Condition,
+		Data:          rule.Data,
+		Updated:       rule.Updated,
+		NoDataState:   rule.NoDataState,
+		ExecErrState:  rule.ExecErrState,
+		Annotations:   rule.Annotations,
+		Labels:        rule.Labels,
+		Provenance:    provenance,
+		IsPaused:      rule.IsPaused,
+		Record:        record,
+		DependsOn:     dependsOn,
 	}
 }
 
@@ -1,9 +1,14 @@
 package definitions
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 func TestToModel(t *testing.T) {
@@ -33,3 +38,106 @@ func TestToModel(t *testing.T) {
 		require.Len(t, tm.Rules, 1)
 	})
 }
+
+func TestProvisionedAlertRuleRecord(t *testing.T) {
+	t.Run("record settings of a recording rule survive a round trip", func(t *testing.T) {
+		rule := models.AlertRule{
+			UID:   "rule",
+			Title: "requests",
+			Data:  []models.AlertQuery{{RefID: "A"}},
+			Record: models.Record{
+				Metric: "requests:rate5m",
+				From:   "A",
+			},
+		}
+
+		provisioned := NewAlertRule(rule, models.ProvenanceAPI)
+		require.Equal(t, &Record{Metric: "requests:rate5m", From: "A"}, provisioned.Record)
+
+		b, err := json.Marshal(provisioned)
+		require.NoError(t, err)
+		var decoded ProvisionedAlertRule
+		require.NoError(t, json.Unmarshal(b, &decoded))
+
+		upstream, err := decoded.UpstreamModel()
+		require.NoError(t, err)
+		require.Equal(t, rule.Record, upstream.Record)
+		require.Equal(t, models.RuleTypeRecording, upstream.Type())
+	})
+
+	t.Run("alerting rules have no record settings", func(t *testing.T) {
+		provisioned := NewAlertRule(models.AlertRule{UID: "rule", Condition: "A"}, models.ProvenanceAPI)
+		require.Nil(t, provisioned.Record)
+
+		upstream, err := provisioned.UpstreamModel()
+		require.NoError(t, err)
+		require.Equal(t, models.RuleTypeAlerting, upstream.Type())
+	})
+
+	t.Run("invalid record settings are rejected", func(t *testing.T) {
+		provisioned := ProvisionedAlertRule{Record: &Record{Metric: "requests-rate", From: "A"}}
+		_, err := provisioned.UpstreamModel()
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		provisioned = ProvisionedAlertRule{Condition: "A", Record: &Record{Metric: "requests:rate5m", From: "A"}}
+		_, err = provisioned.UpstreamModel()
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
+
+func TestProvisionedAlertRuleKeepFiringFor(t *testing.T) {
+	t.Run("keep firing for survives a round trip", func(t *testing.T) {
+		rule := models.AlertRule{UID: "rule", Condition: "A", KeepFiringFor: 5 * time.Minute}
+
+		provisioned := NewAlertRule(rule, models.ProvenanceAPI)
+		b, err := json.Marshal(provisioned)
+		require.NoError(t, err)
+		require.Contains(t, string(b), `"keepFiringFor":"5m"`)
+
+		var decoded ProvisionedAlertRule
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		upstream, err := decoded.UpstreamModel()
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, upstream.KeepFiringFor)
+	})
+
+	t.Run("recording rules cannot keep firing", func(t *testing.T) {
+		provisioned := ProvisionedAlertRule{Record: &Record{Metric: "requests:rate5m", From: "A"}, KeepFiringFor: model.Duration(5 * time.Minute)}
+		_, err := provisioned.UpstreamModel()
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
+
+func TestProvisionedAlertRuleDependsOn(t *testing.T) {
+	t.Run("dependencies survive a round trip", func(t *testing.T) {
+		rule := models.AlertRule{
+			UID:       "rule",
+			Condition: "A",
+			DependsOn: []models.RuleDependency{
+				{RuleUID: "upstream", State: models.RuleDependencyStateAlerting},
+			},
+		}
+
+		provisioned := NewAlertRule(rule, models.ProvenanceAPI)
+		require.Equal(t, []RuleDependency{{RuleUID: "upstream", State: "Alerting"}}, provisioned.DependsOn)
+
+		b, err := json.Marshal(provisioned)
+		require.NoError(t, err)
+		var decoded ProvisionedAlertRule
+		require.NoError(t, json.Unmarshal(b, &decoded))
+
+		upstream, err := decoded.UpstreamModel()
+		require.NoError(t, err)
+		require.Equal(t, rule.DependsOn, upstream.DependsOn)
+	})
+
+	t.Run("invalid dependencies are rejected", func(t *testing.T) {
+		provisioned := ProvisionedAlertRule{UID: "rule", Condition: "A", DependsOn: []RuleDependency{{RuleUID: "rule", State: "Normal"}}}
+		_, err := provisioned.UpstreamModel()
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		provisioned = ProvisionedAlertRule{UID: "rule", Condition: "A", DependsOn: []RuleDependency{{RuleUID: "upstream", State: "Firing"}}}
+		_, err = provisioned.UpstreamModel()
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
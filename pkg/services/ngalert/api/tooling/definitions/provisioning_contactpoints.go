@@ -87,7 +87,7 @@ type EmbeddedContactPoint struct {
 	Name string `json:"name" binding:"required"`
 	// required: true
 	// example: webhook
-	// enum: alertmanager, dingding, discord, email, googlechat, kafka, line, opsgenie, pagerduty, pushover, sensugo, slack, teams, telegram, threema, victorops, webhook, wecom
+	// enum: alertmanager, dingding, discord, email, googlechat, kafka, line, mqtt, opsgenie, pagerduty, pushover, sensugo, slack, sns, teams, telegram, threema, victorops, webhook, wecom
 	Type string `json:"type" binding:"required"`
 	// required: true
 	Settings *simplejson.Json `json:"settings" binding:"required"`
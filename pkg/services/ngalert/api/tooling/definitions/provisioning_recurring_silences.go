@@ -0,0 +1,153 @@
+package definitions
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/v1/provisioning/recurring-silences provisioning stable RouteGetRecurringSilences
+//
+// Get all the recurring silences.
+//
+//     Responses:
+//       200: RecurringSilences
+
+// swagger:route GET /api/v1/provisioning/recurring-silences/{UID} provisioning stable RouteGetRecurringSilence
+//
+// Get a recurring silence.
+//
+//     Responses:
+//       200: RecurringSilence
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/recurring-silences provisioning stable RoutePostRecurringSilence
+//
+// Create a new recurring silence.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: RecurringSilence
+//       400: ValidationError
+
+// swagger:route PUT /api/v1/provisioning/recurring-silences/{UID} provisioning stable RoutePutRecurringSilence
+//
+// Replace an existing recurring silence.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: RecurringSilence
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route DELETE /api/v1/provisioning/recurring-silences/{UID} provisioning stable RouteDeleteRecurringSilence
+//
+// Delete a recurring silence.
+//
+//     Responses:
+//       204: description: The recurring silence was deleted successfully.
+
+// swagger:parameters RouteGetRecurringSilence RoutePutRecurringSilence RouteDeleteRecurringSilence
+type RecurringSilenceUIDParam struct {
+	// Recurring silence UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostRecurringSilence RoutePutRecurringSilence
+type RecurringSilencePayload struct {
+	// in:body
+	Body RecurringSilence
+}
+
+// swagger:model
+type RecurringSilences []RecurringSilence
+
+// RecurringSilence is a silence that is created again every time its schedule becomes active and expired when
+// the schedule ends. The schedule is either a cron expression with a duration or a list of time intervals.
+//
+// swagger:model
+type RecurringSilence struct {
+	// UID of the recurring silence. A UID is generated if it is empty when the recurring silence is created.
+	UID       string `json:"uid" yaml:"uid"`
+	Comment   string `json:"comment" yaml:"comment"`
+	CreatedBy string `json:"createdBy" yaml:"createdBy"`
+	// Matchers of the silence that is created for every occurrence.
+	Matchers ObjectMatchers `json:"matchers" yaml:"matchers"`
+	// Cron expression that starts an occurrence. The expression can be prefixed with CRON_TZ=<location>,
+	// otherwise it is evaluated in UTC.
+	// example: 0 22 * * SAT
+	Cron string `json:"cron,omitempty" yaml:"cron,omitempty"`
+	// Duration of each occurrence started by the cron expression.
+	// example: 4h
+	Duration model.Duration `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// TimeIntervals during which the silence is active, in the same format as the time intervals of mute timings.
+	// They cannot be used together with a cron expression.
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals,omitempty" yaml:"time_intervals,omitempty"`
+	Provenance    models.Provenance           `json:"provenance,omitempty" yaml:"-"`
+}
+
+func (s *RecurringSilence) ResourceType() string {
+	return "recurringSilence"
+}
+
+func (s *RecurringSilence) ResourceID() string {
+	return s.UID
+}
+
+// UpstreamModel converts the recurring silence to the model that is stored in the database.
+func (s *RecurringSilence) UpstreamModel(orgID int64) (models.RecurringSilence, error) {
+	matchers, err := json.Marshal(s.Matchers)
+	if err != nil {
+		return models.RecurringSilence{}, fmt.Errorf("failed to encode matchers: %w", err)
+	}
+	intervals := []byte("[]")
+	if len(s.TimeIntervals) > 0 {
+		intervals, err = json.Marshal(s.TimeIntervals)
+		if err != nil {
+			return models.RecurringSilence{}, fmt.Errorf("failed to encode time intervals: %w", err)
+		}
+	}
+	return models.RecurringSilence{
+		OrgID:         orgID,
+		UID:           s.UID,
+		Comment:       s.Comment,
+		CreatedBy:     s.CreatedBy,
+		Matchers:      string(matchers),
+		Cron:          s.Cron,
+		Duration:      int64(time.Duration(s.Duration) / time.Second),
+		TimeIntervals: string(intervals),
+	}, nil
+}
+
+func NewRecurringSilence(silence models.RecurringSilence, provenance models.Provenance) (RecurringSilence, error) {
+	result := RecurringSilence{
+		UID:        silence.UID,
+		Comment:    silence.Comment,
+		CreatedBy:  silence.CreatedBy,
+		Cron:       silence.Cron,
+		Duration:   model.Duration(time.Duration(silence.Duration) * time.Second),
+		Provenance: provenance,
+	}
+	if err := json.Unmarshal([]byte(silence.Matchers), &result.Matchers); err != nil {
+		return RecurringSilence{}, fmt.Errorf("failed to decode matchers of recurring silence %s: %w", silence.UID, err)
+	}
+	if silence.TimeIntervals != "" {
+		if err := json.Unmarshal([]byte(silence.TimeIntervals), &result.TimeIntervals); err != nil {
+			return RecurringSilence{}, fmt.Errorf("failed to decode time intervals of recurring silence %s: %w", silence.UID, err)
+		}
+		if len(result.TimeIntervals) == 0 {
+			result.TimeIntervals = nil
+		}
+	}
+	return result, nil
+}
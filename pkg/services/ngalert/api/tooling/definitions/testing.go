@@ -67,6 +67,19 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /api/v1/rule/backtest/replay testing BacktestReplay
+//
+// Replay rule over historical data and return state transitions and notifications
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestReplayResult
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -175,7 +188,7 @@ type ResponseDetails struct {
 	Msg string `json:"msg"`
 }
 
-// swagger:parameters BacktestConfig
+// swagger:parameters BacktestConfig BacktestReplay
 type BacktestConfigRequest struct {
 	// in:body
 	Body BacktestConfig
@@ -195,8 +208,42 @@ type BacktestConfig struct {
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
 
-	NoDataState NoDataState `json:"no_data_state"`
+	NoDataState  NoDataState         `json:"no_data_state"`
+	ExecErrState ExecutionErrorState `json:"exec_err_state,omitempty"`
 }
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:model
+type BacktestReplayResult struct {
+	// Transitions are the state changes of all alert instances in the order they happened.
+	Transitions []BacktestStateTransition `json:"transitions"`
+	// Notifications are the alerts that would have been sent, grouped by the notification policy.
+	Notifications []BacktestNotificationGroup `json:"notifications"`
+}
+
+// swagger:model
+type BacktestStateTransition struct {
+	At            time.Time          `json:"at"`
+	Labels        map[string]string  `json:"labels"`
+	PreviousState string             `json:"previous_state"`
+	State         string             `json:"state"`
+	Values        map[string]float64 `json:"values,omitempty"`
+}
+
+// swagger:model
+type BacktestNotificationGroup struct {
+	Receiver      string                 `json:"receiver"`
+	GroupLabels   map[string]string      `json:"group_labels"`
+	Notifications []BacktestNotification `json:"notifications"`
+}
+
+// swagger:model
+type BacktestNotification struct {
+	At       time.Time         `json:"at"`
+	Labels   map[string]string `json:"labels"`
+	State    string            `json:"state"`
+	StartsAt time.Time         `json:"starts_at"`
+	Resolved bool              `json:"resolved"`
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 var (
@@ -37,13 +38,15 @@ type stateManager interface {
 }
 
 type Engine struct {
-	evalFactory        eval.EvaluatorFactory
-	createStateManager func() stateManager
+	evalFactory          eval.EvaluatorFactory
+	createStateManager   func() stateManager
+	disableGrafanaFolder bool
 }
 
-func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory) *Engine {
+func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, cfg *setting.UnifiedAlertingSettings) *Engine {
 	return &Engine{
-		evalFactory: evalFactory,
+		evalFactory:          evalFactory,
+		disableGrafanaFolder: cfg.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
 		createStateManager: func() stateManager {
 			cfg := state.ManagerCfg{
 				Metrics:       nil,
@@ -62,11 +65,8 @@ func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
-	if !from.Before(to) {
-		return nil, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
-	}
-	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
-		return nil, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+	if err := validateTestingRange(rule, from, to); err != nil {
+		return nil, err
 	}
 	length := int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds)
 
@@ -119,6 +119,16 @@ func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models
 	return result, nil
 }
 
+func validateTestingRange(rule *models.AlertRule, from, to time.Time) error {
+	if !from.Before(to) {
+		return fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+	}
+	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
+		return fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+	}
+	return nil
+}
+
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user *user.SignedInUser, condition models.Condition) (backtestingEvaluator, error) {
 	for _, q := range condition.Data {
 		if q.DatasourceUID == "__data__" || q.QueryType == "__data__" {
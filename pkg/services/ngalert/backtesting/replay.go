@@ -0,0 +1,206 @@
+package backtesting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/hashicorp/go-multierror"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// backtestingFolderTitle is the title of the folder that replayed alerts are labeled with.
+const backtestingFolderTitle = "Backtesting"
+
+// ReplayResult is the outcome of replaying an alert rule over a time range.
+type ReplayResult struct {
+	// Transitions contains all state changes of all alert instances in the order they happened.
+	Transitions []Transition
+	// Notifications contains the alerts that would have been sent to the Alertmanager,
+	// grouped the same way the notification policy of the organization groups them.
+	Notifications []NotificationGroup
+}
+
+// Transition is a change of the state of a single alert instance.
+type Transition struct {
+	At            time.Time
+	Labels        data.Labels
+	PreviousState string
+	State         string
+	Values        map[string]float64
+}
+
+// Notification is an alert that would have been sent to the Alertmanager at the given time.
+type Notification struct {
+	At       time.Time
+	Labels   data.Labels
+	State    string
+	StartsAt time.Time
+	Resolved bool
+}
+
+// NotificationGroup contains the notifications that were routed to the same receiver and aggregation group.
+type NotificationGroup struct {
+	Receiver      string
+	GroupLabels   data.Labels
+	Notifications []Notification
+
+	routeKey string
+}
+
+// Replay evaluates the rule over the time range and feeds every result through a fresh state manager,
+// exactly like the scheduler does. Unlike Test, it reports the state transitions of all alert instances and
+// the notifications that would have been sent. Notifications are grouped by the given routing tree. If the routing
+// tree is nil all notifications are returned in a single group without receiver.
+func (e *Engine) Replay(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time, routingTree *config.Route) (*ReplayResult, error) {
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	logger := logger.FromContext(ctx)
+
+	if err := validateTestingRange(rule, from, to); err != nil {
+		return nil, err
+	}
+
+	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition())
+	if err != nil {
+		return nil, multierror.Append(ErrInvalidInputData, err)
+	}
+
+	var route *dispatch.Route
+	if routingTree != nil {
+		route = dispatch.NewRoute(routingTree, nil)
+	}
+	router := newNotificationRouter(route)
+	stateManager := e.createStateManager()
+	// the rule is not stored, so its alerts are labeled with a placeholder folder
+	extraLabels := state.GetRuleExtraLabels(rule, backtestingFolderTitle, !e.disableGrafanaFolder)
+	result := &ReplayResult{}
+
+	logger.Info("Start replaying alert rule", "from", from, "to", to, "interval", rule.IntervalSeconds)
+	start := time.Now()
+
+	err = evaluator.Eval(ruleCtx, from, to, time.Duration(rule.IntervalSeconds)*time.Second, func(currentTime time.Time, results eval.Results) error {
+		transitions := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels)
+		for _, t := range transitions {
+			if t.Changed() {
+				result.Transitions = append(result.Transitions, Transition{
+					At:            currentTime,
+					Labels:        t.Labels.Copy(),
+					PreviousState: t.PreviousFormatted(),
+					State:         t.Formatted(),
+					Values:        copyValues(t.Values),
+				})
+			}
+			if !t.NeedsSending(state.ResendDelay) {
+				continue
+			}
+			router.push(Notification{
+				At:       currentTime,
+				Labels:   t.Labels.Copy(),
+				State:    t.Formatted(),
+				StartsAt: t.StartsAt,
+				Resolved: t.Resolved,
+			})
+			// the scheduler does not put stale states back to the state manager
+			if t.StateReason != models.StateReasonMissingSeries {
+				t.LastSentAt = currentTime
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Notifications = router.groups()
+	logger.Info("Rule replay finished successfully", "duration", time.Since(start), "transitions", len(result.Transitions))
+	return result, nil
+}
+
+// notificationRouter assigns notifications to aggregation groups the same way the Alertmanager dispatcher does.
+type notificationRouter struct {
+	route *dispatch.Route
+	byKey map[string]*NotificationGroup
+	all   []*NotificationGroup
+}
+
+func newNotificationRouter(route *dispatch.Route) *notificationRouter {
+	return &notificationRouter{
+		route: route,
+		byKey: make(map[string]*NotificationGroup),
+	}
+}
+
+func (r *notificationRouter) push(n Notification) {
+	lbls := make(model.LabelSet, len(n.Labels))
+	for k, v := range n.Labels {
+		if strings.HasPrefix(k, "__") && strings.HasSuffix(k, "__") {
+			continue
+		}
+		lbls[model.LabelName(k)] = model.LabelValue(v)
+	}
+
+	if r.route == nil {
+		r.add("", "", nil, n)
+		return
+	}
+	for _, route := range r.route.Match(lbls) {
+		groupLabels := make(data.Labels)
+		for name, value := range lbls {
+			if _, ok := route.RouteOpts.GroupBy[name]; ok || route.RouteOpts.GroupByAll {
+				groupLabels[string(name)] = string(value)
+			}
+		}
+		r.add(route.Key(), route.RouteOpts.Receiver, groupLabels, n)
+	}
+}
+
+func (r *notificationRouter) add(routeKey, receiver string, groupLabels data.Labels, n Notification) {
+	key := fmt.Sprintf("%s:%s", routeKey, groupLabels.String())
+	group, ok := r.byKey[key]
+	if !ok {
+		group = &NotificationGroup{
+			Receiver:    receiver,
+			GroupLabels: groupLabels,
+			routeKey:    routeKey,
+		}
+		r.byKey[key] = group
+		r.all = append(r.all, group)
+	}
+	group.Notifications = append(group.Notifications, n)
+}
+
+// groups returns the aggregation groups sorted by route and group labels.
+func (r *notificationRouter) groups() []NotificationGroup {
+	sort.SliceStable(r.all, func(i, j int) bool {
+		if r.all[i].routeKey != r.all[j].routeKey {
+			return r.all[i].routeKey < r.all[j].routeKey
+		}
+		return r.all[i].GroupLabels.String() < r.all[j].GroupLabels.String()
+	})
+	result := make([]NotificationGroup, 0, len(r.all))
+	for _, g := range r.all {
+		result = append(result, *g)
+	}
+	return result
+}
+
+func copyValues(values map[string]float64) map[string]float64 {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]float64, len(values))
+	for k, v := range values {
+		result[k] = v
+	}
+	return result
+}
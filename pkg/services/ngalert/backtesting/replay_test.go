@@ -0,0 +1,132 @@
+package backtesting
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestEngineReplay(t *testing.T) {
+	from := time.Unix(0, 0)
+	interval := 10 * time.Second
+	// Normal -> Pending -> Alerting -> Alerting -> Normal
+	sequence := []eval.State{eval.Normal, eval.Alerting, eval.Alerting, eval.Alerting, eval.Normal}
+	to := from.Add(time.Duration(len(sequence)) * interval)
+
+	evaluator := &fakeBacktestingEvaluator{
+		evalCallback: func(now time.Time) (eval.Results, error) {
+			idx := int(now.Sub(from) / interval)
+			return eval.Results{
+				{
+					Instance:    data.Labels{"instance": "server-1"},
+					State:       sequence[idx],
+					EvaluatedAt: now,
+				},
+			}, nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user *user.SignedInUser, condition models.Condition) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	appURL, err := url.Parse("http://localhost:3000")
+	require.NoError(t, err)
+	engine := NewEngine(appURL, nil, &setting.UnifiedAlertingSettings{})
+
+	rule := models.AlertRuleGen(models.WithInterval(interval), models.WithFor(2*interval), models.WithTitle("test-rule"))()
+	rule.Labels = map[string]string{"team": "a"}
+	rule.Annotations = nil
+	rule.NoDataState = models.NoData
+	rule.ExecErrState = models.AlertingErrState
+
+	t.Run("should return state transitions", func(t *testing.T) {
+		result, err := engine.Replay(context.Background(), nil, rule, from, to, nil)
+		require.NoError(t, err)
+
+		require.Len(t, result.Transitions, 3)
+		require.Equal(t, from.Add(1*interval), result.Transitions[0].At)
+		require.Equal(t, "Normal", result.Transitions[0].PreviousState)
+		require.Equal(t, "Pending", result.Transitions[0].State)
+		require.Equal(t, from.Add(3*interval), result.Transitions[1].At)
+		require.Equal(t, "Alerting", result.Transitions[1].State)
+		require.Equal(t, from.Add(4*interval), result.Transitions[2].At)
+		require.Equal(t, "Normal", result.Transitions[2].State)
+		require.Equal(t, "server-1", result.Transitions[2].Labels["instance"])
+	})
+
+	t.Run("should add the labels the scheduler adds to alert instances", func(t *testing.T) {
+		result, err := engine.Replay(context.Background(), nil, rule, from, to, nil)
+		require.NoError(t, err)
+
+		labels := result.Transitions[0].Labels
+		require.Equal(t, "test-rule", labels[model.AlertNameLabel])
+		require.Equal(t, rule.UID, labels[alertingModels.RuleUIDLabel])
+		require.Equal(t, rule.NamespaceUID, labels[alertingModels.NamespaceUIDLabel])
+		require.Equal(t, backtestingFolderTitle, labels[models.FolderTitleLabel])
+
+		engine := NewEngine(appURL, nil, &setting.UnifiedAlertingSettings{
+			ReservedLabels: setting.UnifiedAlertingReservedLabelSettings{
+				DisabledLabels: map[string]struct{}{models.FolderTitleLabel: {}},
+			},
+		})
+		result, err = engine.Replay(context.Background(), nil, rule, from, to, nil)
+		require.NoError(t, err)
+		require.NotContains(t, result.Transitions[0].Labels, models.FolderTitleLabel)
+	})
+
+	t.Run("should return notifications in a single group without routing tree", func(t *testing.T) {
+		result, err := engine.Replay(context.Background(), nil, rule, from, to, nil)
+		require.NoError(t, err)
+
+		require.Len(t, result.Notifications, 1)
+		notifications := result.Notifications[0].Notifications
+		require.Len(t, notifications, 2)
+		require.Equal(t, from.Add(3*interval), notifications[0].At)
+		require.False(t, notifications[0].Resolved)
+		require.Equal(t, from.Add(4*interval), notifications[1].At)
+		require.True(t, notifications[1].Resolved)
+	})
+
+	t.Run("should group notifications by notification policy", func(t *testing.T) {
+		routingTree := &config.Route{
+			Receiver: "default",
+			GroupBy:  []model.LabelName{"alertname"},
+			Routes: []*config.Route{
+				{
+					Receiver: "team-a",
+					Match:    map[string]string{"team": "a"},
+					GroupBy:  []model.LabelName{"alertname", "instance"},
+				},
+			},
+		}
+
+		result, err := engine.Replay(context.Background(), nil, rule, from, to, routingTree)
+		require.NoError(t, err)
+
+		require.Len(t, result.Notifications, 1)
+		group := result.Notifications[0]
+		require.Equal(t, "team-a", group.Receiver)
+		require.Equal(t, data.Labels{"alertname": "test-rule", "instance": "server-1"}, group.GroupLabels)
+		require.Len(t, group.Notifications, 2)
+	})
+
+	t.Run("should fail when interval is not correct", func(t *testing.T) {
+		_, err := engine.Replay(context.Background(), nil, rule, to, from, nil)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+}
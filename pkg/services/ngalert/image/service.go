@@ -26,15 +26,25 @@ const (
 
 // DeleteExpiredService is a service to delete expired images.
 type DeleteExpiredService struct {
-	store store.ImageAdminStore
+	store   store.ImageAdminStore
+	storage *StorageService
 }
 
 func (s *DeleteExpiredService) DeleteExpired(ctx context.Context) (int64, error) {
+	if s.storage != nil {
+		if _, err := s.storage.DeleteExpired(ctx); err != nil {
+			return -1, err
+		}
+	}
 	return s.store.DeleteExpiredImages(ctx)
 }
 
-func ProvideDeleteExpiredService(store *store.DBstore) *DeleteExpiredService {
-	return &DeleteExpiredService{store: store}
+func ProvideDeleteExpiredService(cfg *setting.Cfg, store *store.DBstore) (*DeleteExpiredService, error) {
+	storage, err := NewStorageServiceFromCfg(cfg, store.SQLStore)
+	if err != nil {
+		return nil, err
+	}
+	return &DeleteExpiredService{store: store, storage: storage}, nil
 }
 
 type ImageService interface {
@@ -55,6 +65,7 @@ type ScreenshotImageService struct {
 	singleflight      singleflight.Group
 	store             store.ImageStore
 	uploads           *UploadingService
+	storage           *StorageService
 }
 
 // NewScreenshotImageService returns a new ScreenshotImageService.
@@ -65,7 +76,8 @@ func NewScreenshotImageService(
 	screenshots screenshot.ScreenshotService,
 	screenshotTimeout time.Duration,
 	store store.ImageStore,
-	uploads *UploadingService) ImageService {
+	uploads *UploadingService,
+	storage *StorageService) ImageService {
 	return &ScreenshotImageService{
 		cache:             cache,
 		limiter:           limiter,
@@ -74,13 +86,14 @@ func NewScreenshotImageService(
 		screenshotTimeout: screenshotTimeout,
 		store:             store,
 		uploads:           uploads,
+		storage:           storage,
 	}
 }
 
 // NewScreenshotImageServiceFromCfg returns a new ScreenshotImageService
-// from the configuration.
+// from the configuration. The storage is optional.
 func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds dashboards.DashboardService,
-	rs rendering.Service, r prometheus.Registerer) (ImageService, error) {
+	rs rendering.Service, r prometheus.Registerer, storage *StorageService) (ImageService, error) {
 	var (
 		cache             CacheService                 = &NoOpCacheService{}
 		limiter           screenshot.RateLimiter       = &screenshot.NoOpRateLimiter{}
@@ -107,7 +120,7 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 	}
 
 	return NewScreenshotImageService(cache, limiter, log.New("ngalert.image"),
-		screenshots, screenshotTimeout, db, uploads), nil
+		screenshots, screenshotTimeout, db, uploads, storage), nil
 }
 
 // NewImage returns a screenshot of the alert rule or an error.
@@ -187,6 +200,20 @@ func (s *ScreenshotImageService) NewImage(ctx context.Context, r *models.AlertRu
 		}
 		logger.Debug("Saved image", "token", image.Token)
 
+		// Images that were uploaded already have a URL. Others are stored so that Grafana can serve them,
+		// which needs the token and the expiration time of the saved image.
+		if s.storage != nil && !image.HasURL() {
+			stored, err := s.storage.Store(ctx, image)
+			if err != nil {
+				logger.Warn("Failed to store image", "error", err)
+			} else if err := s.store.SaveImage(ctx, &stored); err != nil {
+				logger.Warn("Failed to save URL of stored image", "error", err)
+			} else {
+				logger.Debug("Stored image", "token", stored.Token)
+				image = stored
+			}
+		}
+
 		return image, nil
 	})
 	if err != nil {
@@ -32,7 +32,7 @@ func TestScreenshotImageService(t *testing.T) {
 	)
 
 	s := NewScreenshotImageService(cache, &limiter, log.NewNopLogger(), screenshots, 5*time.Second, images,
-		NewUploadingService(uploads, prometheus.NewRegistry()))
+		NewUploadingService(uploads, prometheus.NewRegistry()), nil)
 
 	ctx := context.Background()
 
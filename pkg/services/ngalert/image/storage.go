@@ -0,0 +1,171 @@
+package image
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// storageRootFolder is the folder in the file storage that contains the images.
+	storageRootFolder = "/alerting/images/"
+
+	// expiresAtProperty is the property of a stored image that contains its expiration time.
+	expiresAtProperty = "expires_at"
+
+	// ImagesPath is the path of the endpoint that serves stored images. It is followed by the token of the image.
+	ImagesPath = "/api/alerting/images/"
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of an image URL is invalid or has expired.
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+
+	timeNow = time.Now
+)
+
+// StorageService stores the contents of images in a file storage, such as the Grafana database, so that
+// any Grafana server can serve them. Stored images get a signed URL to Grafana that expires together with
+// the image, which means contact points can link to them without an external image storage.
+type StorageService struct {
+	storage filestorage.FileStorage
+	appURL  *url.URL
+	secret  []byte
+}
+
+// NewStorageService returns a new StorageService.
+func NewStorageService(storage filestorage.FileStorage, appURL *url.URL, secret string) *StorageService {
+	return &StorageService{
+		storage: storage,
+		appURL:  appURL,
+		secret:  []byte(secret),
+	}
+}
+
+// NewStorageServiceFromCfg returns a new StorageService from the configuration. It returns nil if images
+// are kept on disk.
+func NewStorageServiceFromCfg(cfg *setting.Cfg, sqlStore db.DB) (*StorageService, error) {
+	if cfg.UnifiedAlerting.Screenshots.Storage != setting.ScreenshotStorageDatabase {
+		return nil, nil
+	}
+	appURL, err := url.Parse(cfg.AppURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the application URL: %w", err)
+	}
+	storage := filestorage.NewDbStorage(log.New("ngalert.image.storage"), sqlStore, nil, storageRootFolder)
+	return NewStorageService(storage, appURL, cfg.SecretKey), nil
+}
+
+// Store stores the contents of the image and returns a new image with a signed URL. The image must have
+// been saved so that it has a token and an expiration time. It returns the unmodified image on error.
+func (s *StorageService) Store(ctx context.Context, image models.Image) (models.Image, error) {
+	if image.Token == "" {
+		return image, errors.New("image has no token")
+	}
+	contents, err := os.ReadFile(image.Path)
+	if err != nil {
+		return image, fmt.Errorf("failed to read image: %w", err)
+	}
+	if err := s.storage.Upsert(ctx, &filestorage.UpsertFileCommand{
+		Path:     imageFilePath(image.Token),
+		MimeType: "image/png",
+		Contents: contents,
+		Properties: map[string]string{
+			expiresAtProperty: strconv.FormatInt(image.ExpiresAt.Unix(), 10),
+		},
+	}); err != nil {
+		return image, fmt.Errorf("failed to store image: %w", err)
+	}
+	image.URL = s.signedURL(image.Token, image.ExpiresAt)
+	return image, nil
+}
+
+// Get returns the contents of the image with the token if the signature is valid and has not expired.
+func (s *StorageService) Get(ctx context.Context, token, expires, signature string) ([]byte, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if !timeNow().Before(time.Unix(expiresAt, 0)) {
+		return nil, ErrInvalidSignature
+	}
+	expected := s.sign(token, expiresAt)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	file, ok, err := s.storage.Get(ctx, imageFilePath(token), &filestorage.GetFileOptions{WithContents: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image: %w", err)
+	}
+	if !ok {
+		return nil, models.ErrImageNotFound
+	}
+	return file.Contents, nil
+}
+
+// DeleteExpired deletes the contents of expired images. It returns the number of deleted images.
+func (s *StorageService) DeleteExpired(ctx context.Context) (int64, error) {
+	var expired []string
+	now := timeNow()
+	paging := &filestorage.Paging{First: 100}
+	for {
+		resp, err := s.storage.List(ctx, filestorage.Delimiter, paging, &filestorage.ListOptions{WithFiles: true})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list images: %w", err)
+		}
+		for _, f := range resp.Files {
+			expiresAt, err := strconv.ParseInt(f.Properties[expiresAtProperty], 10, 64)
+			if err != nil || !now.Before(time.Unix(expiresAt, 0)) {
+				expired = append(expired, f.FullPath)
+			}
+		}
+		if !resp.HasMore {
+			break
+		}
+		paging.After = resp.LastPath
+	}
+
+	var n int64
+	for _, p := range expired {
+		if err := s.storage.Delete(ctx, p); err != nil {
+			return n, fmt.Errorf("failed to delete image: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (s *StorageService) signedURL(token string, expiresAt time.Time) string {
+	u := *s.appURL
+	u.Path = path.Join(u.Path, ImagesPath, token)
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	q.Set("signature", s.sign(token, expiresAt.Unix()))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (s *StorageService) sign(token string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	_, _ = mac.Write([]byte(token + ":" + strconv.FormatInt(expiresAt, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func imageFilePath(token string) string {
+	return filestorage.Join(token + ".png")
+}
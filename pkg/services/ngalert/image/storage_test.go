@@ -0,0 +1,96 @@
+package image
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
+
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func newTestStorageService(t *testing.T) *StorageService {
+	t.Helper()
+	bucket, err := blob.OpenBucket(context.Background(), "mem://")
+	require.NoError(t, err)
+	storage := filestorage.NewCdkBlobStorage(log.NewNopLogger(), bucket, storageRootFolder, nil)
+	appURL, err := url.Parse("http://localhost:3000/grafana/")
+	require.NoError(t, err)
+	return NewStorageService(storage, appURL, "secret")
+}
+
+func TestStorageService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	oldTimeNow := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = oldTimeNow })
+
+	path := filepath.Join(t.TempDir(), "screenshot.png")
+	require.NoError(t, os.WriteFile(path, []byte("png"), 0600))
+
+	s := newTestStorageService(t)
+	image := models.Image{Token: "abc", Path: path, ExpiresAt: now.Add(time.Hour)}
+
+	stored, err := s.Store(ctx, image)
+	require.NoError(t, err)
+	u, err := url.Parse(stored.URL)
+	require.NoError(t, err)
+	require.Equal(t, "/grafana/api/alerting/images/abc", u.Path)
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	t.Run("returns the contents with a valid signature", func(t *testing.T) {
+		contents, err := s.Get(ctx, "abc", expires, signature)
+		require.NoError(t, err)
+		require.Equal(t, []byte("png"), contents)
+	})
+
+	t.Run("rejects an invalid signature", func(t *testing.T) {
+		_, err := s.Get(ctx, "abc", expires, "invalid")
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("rejects a signature of another image", func(t *testing.T) {
+		_, err := s.Get(ctx, "def", expires, signature)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("rejects an extended expiration time", func(t *testing.T) {
+		_, err := s.Get(ctx, "abc", "99999999999", signature)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("returns an error for images that are not stored", func(t *testing.T) {
+		signed := s.signedURL("def", image.ExpiresAt)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		_, err = s.Get(ctx, "def", u.Query().Get("expires"), u.Query().Get("signature"))
+		require.ErrorIs(t, err, models.ErrImageNotFound)
+	})
+
+	t.Run("deletes expired images", func(t *testing.T) {
+		expired := models.Image{Token: "expired", Path: path, ExpiresAt: now.Add(-time.Minute)}
+		_, err := s.Store(ctx, expired)
+		require.NoError(t, err)
+
+		n, err := s.DeleteExpired(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		_, err = s.Get(ctx, "abc", expires, signature)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects an expired signature", func(t *testing.T) {
+		timeNow = func() time.Time { return now.Add(time.Hour) }
+		_, err := s.Get(ctx, "abc", expires, signature)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
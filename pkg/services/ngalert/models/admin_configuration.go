@@ -26,6 +26,10 @@ type AdminConfiguration struct {
 	// SendAlertsTo indicates which set of alertmanagers will handle the alert.
 	SendAlertsTo AlertmanagersChoice `xorm:"send_alerts_to"`
 
+	// ParentOrgID is the organization whose Alertmanager also receives the alerts of this organization.
+	// Zero means that alerts are not forwarded.
+	ParentOrgID int64 `xorm:"parent_org_id"`
+
 	CreatedAt int64 `xorm:"created"`
 	UpdatedAt int64 `xorm:"updated"`
 }
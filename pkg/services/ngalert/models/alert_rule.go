@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	alertingModels "github.com/grafana/alerting/alerting/models"
+	prommodel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -94,6 +96,10 @@ const (
 	DashboardUIDAnnotation = "__dashboardUid__"
 	PanelIDAnnotation      = "__panelId__"
 
+	// RecurringSilenceUIDLabel is the name of the reserved matcher that links a silence to the recurring silence
+	// it was created for.
+	RecurringSilenceUIDLabel = "__grafana_recurring_silence_uid__"
+
 	// GrafanaReservedLabelPrefix contains the prefix for Grafana reserved labels. These differ from "__<label>__" labels
 	// in that they are not meant for internal-use only and will be passed-through to AMs and available to users in the same
 	// way as manually configured labels.
@@ -102,6 +108,10 @@ const (
 	// FolderTitleLabel is the label that will contain the title of an alert's folder/namespace.
 	FolderTitleLabel = GrafanaReservedLabelPrefix + "folder"
 
+	// OrgIDLabel is the label that will contain the ID of the organization of an alert that is forwarded to the
+	// Alertmanager of its parent organization.
+	OrgIDLabel = GrafanaReservedLabelPrefix + "org_id"
+
 	// StateReasonAnnotation is the name of the annotation that explains the difference between evaluation state and alert state (i.e. changing state when NoData or Error).
 	StateReasonAnnotation = GrafanaReservedLabelPrefix + "state_reason"
 )
@@ -136,6 +146,163 @@ type AlertRuleGroup struct {
 	Rules      []AlertRule
 }
 
+// RuleType is the type of a rule. It defines what the scheduler does with the result of the rule's evaluation.
+type RuleType string
+
+const (
+	// RuleTypeAlerting is a rule whose condition is used to compute the state of alert instances.
+	RuleTypeAlerting RuleType = "alerting"
+	// RuleTypeRecording is a rule whose result is written as a new metric series.
+	RuleTypeRecording RuleType = "recording"
+)
+
+// Record contains the settings of a recording rule.
+// It is empty for alerting rules.
+type Record struct {
+	// Metric is the name of the metric the result of the rule is written to.
+	Metric string `json:"metric"`
+	// From is the RefID of the query or expression whose result is written.
+	From string `json:"from"`
+}
+
+// IsEmpty returns true if the Record is not configured.
+func (r Record) IsEmpty() bool {
+	return r.Metric == "" && r.From == ""
+}
+
+// Validate returns an error if the settings of a recording rule are not valid.
+func (r Record) Validate() error {
+	if !prommodel.IsValidMetricName(prommodel.LabelValue(r.Metric)) {
+		return fmt.Errorf("%w: metric name %q of recording rule is not valid", ErrAlertRuleFailedValidation, r.Metric)
+	}
+	if r.From == "" {
+		return fmt.Errorf("%w: recording rule must specify the query or expression to record from", ErrAlertRuleFailedValidation)
+	}
+	return nil
+}
+
+// FromDB loads the record settings stored in the database as JSON.
+// FromDB is part of the xorm Conversion interface.
+func (r *Record) FromDB(b []byte) error {
+	if len(b) == 0 {
+		*r = Record{}
+		return nil
+	}
+	return json.Unmarshal(b, r)
+}
+
+// ToDB serializes the record settings to JSON. An empty Record is stored as an empty string.
+// ToDB is part of the xorm Conversion interface.
+func (r *Record) ToDB() ([]byte, error) {
+	if r.IsEmpty() {
+		return []byte{}, nil
+	}
+	return json.Marshal(r)
+}
+
+// RuleDependencyState is the state of an upstream rule that a rule depends on.
+// It summarizes the states of all alert instances of the upstream rule.
+type RuleDependencyState string
+
+const (
+	// RuleDependencyStateNormal means that none of the alert instances of the rule is firing or pending.
+	RuleDependencyStateNormal RuleDependencyState = "Normal"
+	// RuleDependencyStatePending means that at least one alert instance of the rule is pending and none is firing.
+	RuleDependencyStatePending RuleDependencyState = "Pending"
+	// RuleDependencyStateAlerting means that at least one alert instance of the rule is firing.
+	RuleDependencyStateAlerting RuleDependencyState = "Alerting"
+)
+
+// IsValid checks that the value of RuleDependencyState is a known state.
+func (s RuleDependencyState) IsValid() bool {
+	switch s {
+	case RuleDependencyStateNormal, RuleDependencyStatePending, RuleDependencyStateAlerting:
+		return true
+	}
+	return false
+}
+
+// RuleDependency is a dependency of a rule on another rule of the same rule group.
+// The dependent rule is evaluated after the upstream rule, and it can fire only if the upstream rule is in the expected state.
+type RuleDependency struct {
+	// RuleUID is the UID of the upstream rule.
+	RuleUID string `json:"ruleUid"`
+	// State is the state the upstream rule must be in for the dependent rule to fire.
+	State RuleDependencyState `json:"state"`
+}
+
+// ValidateRuleDependencies checks that the dependencies of the rule with the given UID are well-formed.
+// It does not check that the upstream rules belong to the same rule group as the rule.
+func ValidateRuleDependencies(uid string, dependsOn []RuleDependency) error {
+	seen := make(map[string]struct{}, len(dependsOn))
+	for _, dep := range dependsOn {
+		if dep.RuleUID == "" {
+			return fmt.Errorf("%w: dependency must specify the UID of the upstream rule", ErrAlertRuleFailedValidation)
+		}
+		if dep.RuleUID == uid {
+			return fmt.Errorf("%w: rule cannot depend on itself", ErrAlertRuleFailedValidation)
+		}
+		if _, ok := seen[dep.RuleUID]; ok {
+			return fmt.Errorf("%w: rule depends on rule %s more than once", ErrAlertRuleFailedValidation, dep.RuleUID)
+		}
+		seen[dep.RuleUID] = struct{}{}
+		if !dep.State.IsValid() {
+			return fmt.Errorf("%w: unknown state %q of dependency on rule %s", ErrAlertRuleFailedValidation, dep.State, dep.RuleUID)
+		}
+	}
+	return nil
+}
+
+// ValidateRuleGroupDependencies checks that rules depend only on rules of the same group, and that there are no cycles in the dependencies.
+func ValidateRuleGroupDependencies(rules []*AlertRule) error {
+	byUID := make(map[string]*AlertRule, len(rules))
+	for _, rule := range rules {
+		if rule.UID != "" {
+			byUID[rule.UID] = rule
+		}
+	}
+	for idx, rule := range rules {
+		for _, dep := range rule.DependsOn {
+			if _, ok := byUID[dep.RuleUID]; !ok {
+				return fmt.Errorf("%w: rule [%d] depends on rule %s that does not belong to the group", ErrAlertRuleFailedValidation, idx, dep.RuleUID)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(byUID))
+	var visit func(uid string, path []string) error
+	visit = func(uid string, path []string) error {
+		switch marks[uid] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: dependency cycle detected: %s", ErrAlertRuleFailedValidation, strings.Join(append(path, uid), " -> "))
+		}
+		marks[uid] = visiting
+		for _, dep := range byUID[uid].DependsOn {
+			if err := visit(dep.RuleUID, append(path, uid)); err != nil {
+				return err
+			}
+		}
+		marks[uid] = visited
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.UID == "" {
+			continue
+		}
+		if err := visit(rule.UID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AlertRule is the model for alert rules in unified alerting.
 type AlertRule struct {
 	ID              int64 `xorm:"pk autoincr 'id'"`
@@ -156,10 +323,24 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For time.Duration
+	// KeepFiringFor is how long an alert instance keeps firing after the condition stopped being met.
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	// Record is set if the rule is a recording rule.
+	Record Record `xorm:"record"`
+	// DependsOn is the list of rules of the same group this rule depends on.
+	DependsOn []RuleDependency
+}
+
+// Type returns the type of the rule.
+func (alertRule *AlertRule) Type() RuleType {
+	if !alertRule.Record.IsEmpty() {
+		return RuleTypeRecording
+	}
+	return RuleTypeAlerting
 }
 
 // GetDashboardUID returns the DashboardUID or "".
@@ -201,7 +382,15 @@ func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	return labels
 }
 
+// GetEvalCondition returns the condition to evaluate. For recording rules,
+// this is the query or expression whose result is written.
 func (alertRule *AlertRule) GetEvalCondition() Condition {
+	if alertRule.Type() == RuleTypeRecording {
+		return Condition{
+			Condition: alertRule.Record.From,
+			Data:      alertRule.Data,
+		}
+	}
 	return Condition{
 		Condition: alertRule.Condition,
 		Data:      alertRule.Data,
@@ -341,10 +530,13 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For           time.Duration
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	Record        Record `xorm:"record"`
+	DependsOn     []RuleDependency
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -440,15 +632,16 @@ func (c Condition) IsValid() bool {
 // There are several exceptions:
 // 1. Following fields are not patched and therefore will be ignored: AlertRule.ID, AlertRule.OrgID, AlertRule.Updated, AlertRule.Version, AlertRule.UID, AlertRule.DashboardUID, AlertRule.PanelID, AlertRule.Annotations and AlertRule.Labels
 // 2. There are fields that are patched together:
-//   - AlertRule.Condition and AlertRule.Data
+//   - AlertRule.Condition, AlertRule.Record and AlertRule.Data
 //
-// If either of the pair is specified, neither is patched.
+// If the evaluated condition (AlertRule.Condition or AlertRule.Record) and AlertRule.Data are not both specified, all of them are patched.
 func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
 	if ruleToPatch.Title == "" {
 		ruleToPatch.Title = existingRule.Title
 	}
-	if ruleToPatch.Condition == "" || len(ruleToPatch.Data) == 0 {
+	if ruleToPatch.GetEvalCondition().Condition == "" || len(ruleToPatch.Data) == 0 {
 		ruleToPatch.Condition = existingRule.Condition
+		ruleToPatch.Record = existingRule.Record
 		ruleToPatch.Data = existingRule.Data
 	}
 	if ruleToPatch.IntervalSeconds == 0 {
@@ -469,6 +662,9 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrAlertRuleTemplateNotFound = errors.New("could not find alert rule template")
+
+// AlertRuleTemplate is a parameterized alert rule. Alert rules are created from a template by providing values
+// for its parameters, and they are updated every time a new version of the template is rolled out.
+type AlertRuleTemplate struct {
+	ID          int64  `xorm:"pk autoincr 'id'"`
+	OrgID       int64  `xorm:"org_id"`
+	UID         string `xorm:"uid"`
+	Title       string `xorm:"title"`
+	Description string `xorm:"description"`
+	// Parameters is the JSON encoded list of parameters of the template.
+	Parameters string `xorm:"parameters"`
+	// Rule is the JSON encoded alert rule that contains references to the parameters.
+	Rule string `xorm:"rule"`
+	// Version is incremented every time the template is updated. The column name is quoted so that xorm does
+	// not use it for optimistic locking.
+	Version int64     `xorm:"'version'"`
+	Updated time.Time `xorm:"updated"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (t *AlertRuleTemplate) TableName() string {
+	return "alert_rule_template"
+}
+
+// AlertRuleTemplateInstance links an alert rule to the template it was created from.
+type AlertRuleTemplateInstance struct {
+	ID          int64  `xorm:"pk autoincr 'id'"`
+	OrgID       int64  `xorm:"org_id"`
+	TemplateUID string `xorm:"template_uid"`
+	RuleUID     string `xorm:"rule_uid"`
+	// TemplateVersion is the version of the template the alert rule was last rendered from.
+	TemplateVersion int64 `xorm:"template_version"`
+	// Values is the JSON encoded map of values of the parameters of the template.
+	Values  string    `xorm:"parameter_values"`
+	Updated time.Time `xorm:"updated"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (i *AlertRuleTemplateInstance) TableName() string {
+	return "alert_rule_template_instance"
+}
@@ -199,6 +199,12 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.For = -1
 				},
 			},
+			{
+				name: "KeepFiringFor is -1",
+				mutator: func(r *AlertRule) {
+					r.KeepFiringFor = -1
+				},
+			},
 		}
 
 		for _, testCase := range testCases {
@@ -310,6 +316,55 @@ func TestPatchPartialAlertRule(t *testing.T) {
 	})
 }
 
+func TestPatchPartialRecordingRule(t *testing.T) {
+	t.Run("patches record and data if they are empty", func(t *testing.T) {
+		existing := AlertRuleGen(WithRecord("test_metric"))()
+		patch := *existing
+		patch.Record = Record{}
+		patch.Data = nil
+
+		PatchPartialAlertRule(existing, &patch)
+
+		require.Equal(t, existing.Record, patch.Record)
+		require.Equal(t, existing.Data, patch.Data)
+		require.Equal(t, RuleTypeRecording, patch.Type())
+	})
+
+	t.Run("does not patch record if condition and data are specified", func(t *testing.T) {
+		existing := AlertRuleGen(WithRecord("test_metric"))()
+		patch := *existing
+		patch.Record = Record{}
+		patch.Condition = existing.Data[0].RefID
+
+		PatchPartialAlertRule(existing, &patch)
+
+		require.True(t, patch.Record.IsEmpty())
+		require.Equal(t, RuleTypeAlerting, patch.Type())
+	})
+}
+
+func TestRecordToDB(t *testing.T) {
+	t.Run("empty record is stored as empty string", func(t *testing.T) {
+		b, err := (&Record{}).ToDB()
+		require.NoError(t, err)
+		require.Empty(t, b)
+
+		r := Record{Metric: "test"}
+		require.NoError(t, r.FromDB(b))
+		require.True(t, r.IsEmpty())
+	})
+
+	t.Run("record is stored as JSON", func(t *testing.T) {
+		r := Record{Metric: "test_metric", From: "A"}
+		b, err := r.ToDB()
+		require.NoError(t, err)
+
+		var result Record
+		require.NoError(t, result.FromDB(b))
+		require.Equal(t, r, result)
+	})
+}
+
 func TestDiff(t *testing.T) {
 	t.Run("should return nil if there is no diff", func(t *testing.T) {
 		rule1 := AlertRuleGen()()
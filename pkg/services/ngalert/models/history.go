@@ -9,4 +9,28 @@ type HistoryQuery struct {
 	Labels  map[string]string
 	From    time.Time
 	To      time.Time
+	// Limit is the maximum number of state transitions returned, the most recent ones are returned.
+	Limit int
+}
+
+// StateHistoryEntry is a single alert state transition persisted by the SQL state history backend.
+type StateHistoryEntry struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	RuleUID       string `xorm:"rule_uid"`
+	RuleGroup     string `xorm:"rule_group"`
+	NamespaceUID  string `xorm:"namespace_uid"`
+	Labels        string `xorm:"labels"`
+	LabelsHash    string `xorm:"labels_hash"`
+	PreviousState string `xorm:"previous_state"`
+	CurrentState  string `xorm:"current_state"`
+	// Data is a JSON blob containing the values, error or no-data marker of the evaluation that caused the transition.
+	Data string `xorm:"data"`
+	// At is the evaluation time of the transition, in epoch milliseconds.
+	At int64 `xorm:"at"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (e *StateHistoryEntry) TableName() string {
+	return "alert_state_history"
 }
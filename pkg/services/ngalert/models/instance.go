@@ -14,6 +14,8 @@ type AlertInstance struct {
 	CurrentStateSince time.Time
 	CurrentStateEnd   time.Time
 	LastEvalTime      time.Time
+	// KeepFiringSince is the time the instance stopped firing while it is kept firing, it is zero otherwise.
+	KeepFiringSince time.Time
 }
 
 type AlertInstanceKey struct {
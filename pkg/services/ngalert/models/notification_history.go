@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+const (
+	NotificationStatusSuccess = "success"
+	NotificationStatusFailed  = "failed"
+)
+
+// NotificationHistoryEntry is a single attempt of an integration of a receiver to deliver a notification.
+type NotificationHistoryEntry struct {
+	ID               int64  `xorm:"pk autoincr 'id'"`
+	OrgID            int64  `xorm:"org_id"`
+	Receiver         string `xorm:"receiver"`
+	Integration      string `xorm:"integration"`
+	IntegrationIndex int    `xorm:"integration_index"`
+	IntegrationUID   string `xorm:"integration_uid"`
+	GroupKey         string `xorm:"group_key"`
+	// Fingerprints is a comma separated list of the fingerprints of the alerts in the notification.
+	Fingerprints string `xorm:"fingerprints"`
+	Status       string `xorm:"status"`
+	Error        string `xorm:"error"`
+	// Duration is how long the attempt took, in milliseconds.
+	Duration int64 `xorm:"duration"`
+	// At is the time the attempt started, in epoch milliseconds.
+	At int64 `xorm:"at"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (e *NotificationHistoryEntry) TableName() string {
+	return "alert_notification_history"
+}
+
+// NotificationHistoryQuery represents a query for notification attempts of a single organization.
+// Empty fields do not filter.
+type NotificationHistoryQuery struct {
+	OrgID       int64
+	Receiver    string
+	Integration string
+	Status      string
+	GroupKey    string
+	From        time.Time
+	To          time.Time
+	Limit       int
+}
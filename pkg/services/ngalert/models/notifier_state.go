@@ -0,0 +1,16 @@
+package models
+
+// NotifierStateEntry is a change of the silences or of the notification log of the Alertmanager of an organization.
+// The data of an entry is in the binary format of the snapshots of the Alertmanager, so the concatenation of all
+// entries of a kind, ordered by ID, is a snapshot of that kind of state.
+type NotifierStateEntry struct {
+	ID    int64  `xorm:"pk autoincr 'id'"`
+	OrgID int64  `xorm:"org_id"`
+	Kind  string `xorm:"kind"`
+	Data  []byte `xorm:"data"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (e *NotifierStateEntry) TableName() string {
+	return "alert_notifier_state"
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrRecurringSilenceNotFound = errors.New("could not find recurring silence")
+
+// RecurringSilence is a silence that is recreated by the Alertmanager of the organization every time its
+// schedule becomes active. The schedule is either a cron expression with a duration or a list of time intervals
+// in the same format as the ones of mute timings.
+type RecurringSilence struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"org_id"`
+	UID       string `xorm:"uid"`
+	Comment   string `xorm:"comment"`
+	CreatedBy string `xorm:"created_by"`
+	// Matchers is the JSON encoded list of matchers of the silence.
+	Matchers string `xorm:"matchers"`
+	Cron     string `xorm:"cron"`
+	// Duration is how long each occurrence of a cron schedule lasts, in seconds.
+	Duration int64 `xorm:"duration"`
+	// TimeIntervals is the JSON encoded list of time intervals of the schedule.
+	TimeIntervals string    `xorm:"time_intervals"`
+	Updated       time.Time `xorm:"updated"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (s *RecurringSilence) TableName() string {
+	return "alert_recurring_silence"
+}
@@ -164,6 +164,14 @@ func WithFor(duration time.Duration) AlertRuleMutator {
 	}
 }
 
+// WithRecord makes the rule a recording rule that writes the result of its first query to the given metric.
+func WithRecord(metric string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Condition = ""
+		rule.Record = Record{Metric: metric, From: rule.Data[0].RefID}
+	}
+}
+
 func GenerateAlertLabels(count int, prefix string) data.Labels {
 	labels := make(data.Labels, count)
 	for i := 0; i < count; i++ {
@@ -256,6 +264,8 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
+		Record:          r.Record,
 	}
 
 	if r.DashboardUID != nil {
@@ -293,6 +303,11 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	if r.DependsOn != nil {
+		result.DependsOn = make([]RuleDependency, len(r.DependsOn))
+		copy(result.DependsOn, r.DependsOn)
+	}
+
 	return &result
 }
 
@@ -37,7 +37,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
@@ -67,6 +69,7 @@ func ProvideService(
 	annotationsRepo annotations.Repository,
 	pluginsStore plugins.Store,
 	tracer tracing.Tracer,
+	orgService org.Service,
 ) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                  cfg,
@@ -92,6 +95,7 @@ func ProvideService(
 		annotationsRepo:      annotationsRepo,
 		pluginsStore:         pluginsStore,
 		tracer:               tracer,
+		orgService:           orgService,
 	}
 
 	if ng.IsDisabled() {
@@ -139,6 +143,7 @@ type AlertNG struct {
 	bus          bus.Bus
 	pluginsStore plugins.Store
 	tracer       tracing.Tracer
+	orgService   org.Service
 }
 
 func (ng *AlertNG) init() error {
@@ -166,7 +171,11 @@ func (ng *AlertNG) init() error {
 		return err
 	}
 
-	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, store, ng.dashboardService, ng.renderService, ng.Metrics.Registerer)
+	imageStorage, err := image.NewStorageServiceFromCfg(ng.Cfg, ng.SQLStore)
+	if err != nil {
+		return err
+	}
+	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, store, ng.dashboardService, ng.renderService, ng.Metrics.Registerer, imageStorage)
 	if err != nil {
 		return err
 	}
@@ -208,9 +217,18 @@ func (ng *AlertNG) init() error {
 		Metrics:              ng.Metrics.GetSchedulerMetrics(),
 		AlertSender:          alertsRouter,
 		Tracer:               ng.tracer,
+		RecordingWriter:      configureRecordingWriter(ng.Cfg.UnifiedAlerting.RecordingRules, ng.Log),
+	}
+
+	if ng.Cfg.UnifiedAlerting.HAShardRuleEvaluation {
+		if len(ng.Cfg.UnifiedAlerting.HAPeers) > 0 {
+			schedCfg.ClusterMembership = ng.MultiOrgAlertmanager
+		} else {
+			ng.Log.Warn("Sharding of rule evaluation is enabled but no HA peers are configured. All rules will be evaluated by this instance")
+		}
 	}
 
-	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store)
+	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.store)
 	if err != nil {
 		return err
 	}
@@ -239,9 +257,11 @@ func (ng *AlertNG) init() error {
 	contactPointService := provisioning.NewContactPointService(store, ng.SecretsService, store, store, ng.Log)
 	templateService := provisioning.NewTemplateService(store, store, store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(store, store, store, ng.Log)
+	recurringSilenceService := provisioning.NewRecurringSilenceService(store, store, store, ng.Log)
 	alertRuleService := provisioning.NewAlertRuleService(store, store, ng.dashboardService, ng.QuotaService, store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
+	alertRuleTemplateService := provisioning.NewAlertRuleTemplateService(store, alertRuleService, store, store, ng.Log)
 
 	api := api.API{
 		Cfg:                  ng.Cfg,
@@ -263,11 +283,15 @@ func (ng *AlertNG) init() error {
 		ContactPointService:  contactPointService,
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
+		RecurringSilences:    recurringSilenceService,
 		AlertRules:           alertRuleService,
+		AlertRuleTemplates:   alertRuleTemplateService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
 		FeatureManager:       ng.FeatureToggles,
 		AppUrl:               appUrl,
+		ImageStorage:         imageStorage,
+		OrgService:           ng.orgService,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -383,7 +407,7 @@ func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
 	return limits, nil
 }
 
-func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore) (state.Historian, error) {
+func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore, hs historian.StateHistoryStore) (state.Historian, error) {
 	if !cfg.Enabled {
 		return historian.NewNopHistorian(), nil
 	}
@@ -406,8 +430,22 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		return backend, nil
 	}
 	if cfg.Backend == "sql" {
-		return historian.NewSqlBackend(), nil
+		return historian.NewSqlBackend(hs), nil
 	}
 
 	return nil, fmt.Errorf("unrecognized state history backend: %s", cfg.Backend)
 }
+
+// configureRecordingWriter returns the writer for the results of recording rules.
+// It returns nil if recording rules are disabled, in which case they are not evaluated.
+func configureRecordingWriter(cfg setting.RecordingRuleSettings, l log.Logger) schedule.RecordingWriter {
+	if !cfg.Enabled {
+		return nil
+	}
+	logger := l.New("component", "recording-writer")
+	if cfg.URL == "" {
+		logger.Warn("Recording rules are enabled but no remote write URL is configured, results will be discarded")
+		return writer.NewNoopWriter(logger)
+	}
+	return writer.NewPrometheusWriter(cfg, logger)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/history"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
@@ -42,6 +43,9 @@ var silenceMaintenanceInterval = 15 * time.Minute
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	store.NotificationHistoryStore
+	store.RecurringSilenceStore
+	store.NotifierStateStore
 }
 
 type Alertmanager struct {
@@ -55,6 +59,9 @@ type Alertmanager struct {
 
 	decryptFn channels.GetDecryptedValueFn
 	orgID     int64
+
+	// notificationHistory records the notification attempts of the integrations. It is nil if the history is disabled.
+	notificationHistory *history.Recorder
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -88,21 +95,23 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 	workingPath := filepath.Join(cfg.DataPath, workingDir, strconv.Itoa(int(orgID)))
 	fileStore := NewFileStore(orgID, kvStore, workingPath)
 
-	nflogFilepath, err := fileStore.FilepathFor(ctx, notificationLogFilename)
+	nflogFilepath, err := loadNotifierState(ctx, orgID, notificationLogFilename, store, fileStore)
 	if err != nil {
 		return nil, err
 	}
-	silencesFilePath, err := fileStore.FilepathFor(ctx, silencesFilename)
+	silencesFilePath, err := loadNotifierState(ctx, orgID, silencesFilename, store, fileStore)
 	if err != nil {
 		return nil, err
 	}
 
+	// Every change of the silences and the notification log is saved by the peer. The maintenance
+	// compacts the saved changes into a snapshot.
 	silencesOptions := maintenanceOptions{
 		filepath:             silencesFilePath,
 		retention:            retentionNotificationsAndSilences,
 		maintenanceFrequency: silenceMaintenanceInterval,
 		maintenanceFunc: func(state alerting.State) (int64, error) {
-			return fileStore.Persist(ctx, silencesFilename, state)
+			return compactNotifierState(ctx, orgID, silencesFilename, store, state)
 		},
 	}
 
@@ -111,7 +120,7 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		retention:            retentionNotificationsAndSilences,
 		maintenanceFrequency: notificationLogMaintenanceInterval,
 		maintenanceFunc: func(state alerting.State) (int64, error) {
-			return fileStore.Persist(ctx, notificationLogFilename, state)
+			return compactNotifierState(ctx, orgID, notificationLogFilename, store, state)
 		},
 	}
 
@@ -124,7 +133,7 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 	}
 
 	l := log.New("alertmanager", "org", orgID)
-	gam, err := alerting.NewGrafanaAlertmanager("orgID", orgID, amcfg, peer, l, alerting.NewGrafanaAlertmanagerMetrics(m.Registerer))
+	gam, err := alerting.NewGrafanaAlertmanager("orgID", orgID, amcfg, newPersistingPeer(peer, orgID, store, l), l, alerting.NewGrafanaAlertmanagerMetrics(m.Registerer))
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +148,9 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		fileStore:           fileStore,
 		logger:              l,
 	}
+	if cfg.UnifiedAlerting.NotificationHistory.Enabled {
+		am.notificationHistory = history.NewRecorder(orgID, store, l)
+	}
 
 	return am, nil
 }
@@ -318,6 +330,14 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableAp
 		if err != nil {
 			return nil, err
 		}
+		if am.notificationHistory != nil {
+			n = am.notificationHistory.Wrap(n, history.Integration{
+				Receiver: receiver.Name,
+				Type:     r.Type,
+				Index:    i,
+				UID:      r.UID,
+			})
+		}
 		integrations = append(integrations, alerting.NewIntegration(n, n, r.Type, i))
 	}
 	return integrations, nil
@@ -1236,5 +1236,203 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "mqtt",
+			Name:        "MQTT",
+			Description: "Sends notifications to an MQTT broker",
+			Heading:     "MQTT settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Broker URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The URL of the MQTT broker. Supported schemes are tcp, ssl, tls, ws, wss, mqtt and mqtts.",
+					Placeholder:  "tcp://localhost:1883",
+					PropertyName: "brokerUrl",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The topic to which the message will be published.",
+					Placeholder:  "grafana/alerts",
+					PropertyName: "topic",
+					Required:     true,
+				},
+				{
+					Label:        "Client ID",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The client ID to use when connecting to the broker. By default a unique client ID is generated for every connection.",
+					PropertyName: "clientId",
+				},
+				{
+					Label:        "Message format",
+					Element:      ElementTypeSelect,
+					InputType:    InputTypeText,
+					Description:  "The format of the published message. JSON contains the message and the alerts, text contains only the message.",
+					PropertyName: "messageFormat",
+					SelectOptions: []SelectOption{
+						{
+							Value: "json",
+							Label: "JSON",
+						},
+						{
+							Value: "text",
+							Label: "Text",
+						},
+					},
+				},
+				{
+					Label:        "Message",
+					Element:      ElementTypeTextArea,
+					Description:  "Templated message of the notification.",
+					Placeholder:  channels.DefaultMessageEmbed,
+					PropertyName: "message",
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "QoS",
+					Element:      ElementTypeSelect,
+					InputType:    InputTypeText,
+					Description:  "The quality of service level of the published message. By default 0 will be used.",
+					PropertyName: "qos",
+					SelectOptions: []SelectOption{
+						{
+							Value: "0",
+							Label: "At most once (0)",
+						},
+						{
+							Value: "1",
+							Label: "At least once (1)",
+						},
+						{
+							Value: "2",
+							Label: "Exactly once (2)",
+						},
+					},
+				},
+				{
+					Label:        "Retain",
+					Description:  "Ask the broker to retain the last message of the topic",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "retain",
+				},
+				{
+					Label:        "Disable certificate verification",
+					Description:  "Do not verify the TLS certificate of the broker. This is insecure and should only be used for testing.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "insecureSkipVerify",
+				},
+			},
+		},
+		{
+			Type:        "sns",
+			Name:        "Amazon SNS",
+			Description: "Sends notifications to an Amazon SNS topic",
+			Heading:     "Amazon SNS settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Topic ARN",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The ARN of the SNS topic to which the message will be published.",
+					Placeholder:  "arn:aws:sns:us-east-1:123456789012:grafana-alerts",
+					PropertyName: "topicArn",
+					Required:     true,
+				},
+				{
+					Label:        "Region",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The AWS region of the topic. By default the region of the topic ARN will be used.",
+					Placeholder:  "us-east-1",
+					PropertyName: "region",
+				},
+				{
+					Label:        "Endpoint",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Optional custom endpoint of the SNS API, for example a VPC endpoint or a local emulator.",
+					Placeholder:  "https://sns.us-east-1.amazonaws.com",
+					PropertyName: "endpoint",
+				},
+				{
+					Label:        "Access Key",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Leave empty to use the default AWS credential chain of the Grafana server, if the default auth provider is allowed in the [aws] section of the configuration.",
+					PropertyName: "accessKey",
+					Secure:       true,
+				},
+				{
+					Label:        "Secret Key",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "secretKey",
+					Secure:       true,
+				},
+				{
+					Label:        "Assume Role ARN",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Optional ARN of a role to assume before publishing. Requires assume_role_enabled in the [aws] section of the configuration.",
+					Placeholder:  "arn:aws:iam::123456789012:role/grafana",
+					PropertyName: "assumeRoleArn",
+				},
+				{
+					Label:        "External ID",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The external ID to use when assuming the role.",
+					PropertyName: "externalId",
+				},
+				{
+					Label:        "Message format",
+					Element:      ElementTypeSelect,
+					InputType:    InputTypeText,
+					Description:  "The format of the published message. JSON contains the message and the alerts, text contains only the message.",
+					PropertyName: "messageFormat",
+					SelectOptions: []SelectOption{
+						{
+							Value: "text",
+							Label: "Text",
+						},
+						{
+							Value: "json",
+							Label: "JSON",
+						},
+					},
+				},
+				{
+					Label:        "Subject",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Templated subject of the message. Used by email subscriptions of the topic.",
+					Placeholder:  channels.DefaultMessageTitleEmbed,
+					PropertyName: "subject",
+				},
+				{
+					Label:        "Message",
+					Element:      ElementTypeTextArea,
+					Description:  "Templated message of the notification.",
+					Placeholder:  channels.DefaultMessageEmbed,
+					PropertyName: "message",
+				},
+			},
+		},
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"strings"
 
 	"github.com/grafana/alerting/alerting/notifier/channels"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/receivers"
 )
 
 var receiverFactories = map[string]func(channels.FactoryConfig) (channels.NotificationChannel, error){
@@ -14,11 +16,13 @@ var receiverFactories = map[string]func(channels.FactoryConfig) (channels.Notifi
 	"googlechat":              channels.GoogleChatFactory,
 	"kafka":                   channels.KafkaFactory,
 	"line":                    channels.LineFactory,
+	"mqtt":                    receivers.MQTTFactory,
 	"opsgenie":                channels.OpsgenieFactory,
 	"pagerduty":               channels.PagerdutyFactory,
 	"pushover":                channels.PushoverFactory,
 	"sensugo":                 channels.SensuGoFactory,
 	"slack":                   channels.SlackFactory,
+	"sns":                     receivers.SNSFactory,
 	"teams":                   channels.TeamsFactory,
 	"telegram":                channels.TelegramFactory,
 	"threema":                 channels.ThreemaFactory,
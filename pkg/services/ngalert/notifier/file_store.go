@@ -17,6 +17,8 @@ const KVNamespace = "alertmanager"
 
 // FileStore is in charge of persisting the alertmanager files to the database.
 // It uses the KVstore table and encodes the files as a base64 string.
+// The silences and the notification log are saved in the notifier state store instead, the files in the KVstore
+// are only read to start from the state that was saved by previous versions.
 type FileStore struct {
 	kv             *kvstore.NamespacedKVStore
 	orgID          int64
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/alerting/alerting/notifier/channels"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// recordTimeout is how long writing a single notification attempt may take.
+// The attempt is written with its own context so a notification that timed out is still recorded.
+const recordTimeout = 5 * time.Second
+
+var timeNow = time.Now
+
+// NotificationHistoryWriter is the database interface used to record notification attempts.
+type NotificationHistoryWriter interface {
+	SaveNotificationHistory(ctx context.Context, entry *models.NotificationHistoryEntry) error
+}
+
+// Integration identifies the integration of a receiver whose notification attempts are recorded.
+type Integration struct {
+	Receiver string
+	Type     string
+	Index    int
+	UID      string
+}
+
+// Recorder records the notification attempts of the integrations of a single organization.
+type Recorder struct {
+	orgID int64
+	store NotificationHistoryWriter
+	log   log.Logger
+}
+
+func NewRecorder(orgID int64, store NotificationHistoryWriter, logger log.Logger) *Recorder {
+	return &Recorder{
+		orgID: orgID,
+		store: store,
+		log:   logger,
+	}
+}
+
+// Wrap returns a notifier that records every call of Notify of the given notifier as a notification attempt.
+func (r *Recorder) Wrap(n channels.NotificationChannel, integration Integration) channels.NotificationChannel {
+	return &recordingNotifier{
+		NotificationChannel: n,
+		integration:         integration,
+		recorder:            r,
+	}
+}
+
+func (r *Recorder) record(ctx context.Context, integration Integration, start time.Time, alerts []*types.Alert, notifyErr error) {
+	groupKey, _ := notify.ExtractGroupKey(ctx)
+	fingerprints := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		fingerprints = append(fingerprints, a.Fingerprint().String())
+	}
+
+	entry := &models.NotificationHistoryEntry{
+		OrgID:            r.orgID,
+		Receiver:         integration.Receiver,
+		Integration:      integration.Type,
+		IntegrationIndex: integration.Index,
+		IntegrationUID:   integration.UID,
+		GroupKey:         groupKey.String(),
+		Fingerprints:     strings.Join(fingerprints, ","),
+		Status:           models.NotificationStatusSuccess,
+		Duration:         timeNow().Sub(start).Milliseconds(),
+		At:               start.UnixMilli(),
+	}
+	if notifyErr != nil {
+		entry.Status = models.NotificationStatusFailed
+		entry.Error = notifyErr.Error()
+	}
+
+	writeCtx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+	if err := r.store.SaveNotificationHistory(writeCtx, entry); err != nil {
+		r.log.Error("Failed to record notification attempt", "receiver", integration.Receiver, "integration", integration.Type, "error", err)
+	}
+}
+
+type recordingNotifier struct {
+	channels.NotificationChannel
+	integration Integration
+	recorder    *Recorder
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := timeNow()
+	retry, err := n.NotificationChannel.Notify(ctx, alerts...)
+	n.recorder.record(ctx, n.integration, start, alerts, err)
+	return retry, err
+}
+
+// DeleteExpiredService is a service to delete notification attempts that are older than the configured retention.
+type DeleteExpiredService struct {
+	retention time.Duration
+	store     store.NotificationHistoryStore
+}
+
+func ProvideDeleteExpiredService(cfg *setting.Cfg, store *store.DBstore) *DeleteExpiredService {
+	return &DeleteExpiredService{
+		retention: cfg.UnifiedAlerting.NotificationHistory.Retention,
+		store:     store,
+	}
+}
+
+// DeleteExpired deletes notification attempts older than the retention. It returns the number of deleted attempts.
+// A retention of zero or less keeps notification attempts forever.
+func (s *DeleteExpiredService) DeleteExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	return s.store.DeleteNotificationHistoryOlderThan(ctx, store.TimeNow().Add(-s.retention))
+}
@@ -0,0 +1,105 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/alerting/alerting/notifier/channels"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeWriter struct {
+	entries []*models.NotificationHistoryEntry
+	err     error
+}
+
+func (w *fakeWriter) SaveNotificationHistory(_ context.Context, entry *models.NotificationHistoryEntry) error {
+	w.entries = append(w.entries, entry)
+	return w.err
+}
+
+type fakeNotifier struct {
+	retry bool
+	err   error
+}
+
+func (n *fakeNotifier) Notify(context.Context, ...*types.Alert) (bool, error) {
+	return n.retry, n.err
+}
+
+func (n *fakeNotifier) SendResolved() bool {
+	return true
+}
+
+func TestRecordingNotifier(t *testing.T) {
+	now := time.Unix(1000, 0)
+	timeNow = func() time.Time {
+		now = now.Add(250 * time.Millisecond)
+		return now
+	}
+	t.Cleanup(func() {
+		timeNow = time.Now
+	})
+
+	alerts := []*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b"}}},
+	}
+	integration := Integration{Receiver: "on-call", Type: "pagerduty", Index: 1, UID: "uid"}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"a\"}")
+
+	t.Run("should record successful attempt", func(t *testing.T) {
+		writer := &fakeWriter{}
+		n := NewRecorder(1, writer, log.NewNopLogger()).Wrap(&fakeNotifier{}, integration)
+
+		retry, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.False(t, retry)
+		require.True(t, n.SendResolved())
+
+		require.Len(t, writer.entries, 1)
+		entry := writer.entries[0]
+		require.Equal(t, int64(1), entry.OrgID)
+		require.Equal(t, "on-call", entry.Receiver)
+		require.Equal(t, "pagerduty", entry.Integration)
+		require.Equal(t, 1, entry.IntegrationIndex)
+		require.Equal(t, "uid", entry.IntegrationUID)
+		require.Equal(t, "{}:{alertname=\"a\"}", entry.GroupKey)
+		require.Equal(t, alerts[0].Fingerprint().String()+","+alerts[1].Fingerprint().String(), entry.Fingerprints)
+		require.Equal(t, models.NotificationStatusSuccess, entry.Status)
+		require.Empty(t, entry.Error)
+		require.Equal(t, int64(250), entry.Duration)
+	})
+
+	t.Run("should record failed attempt and return the error of the notifier", func(t *testing.T) {
+		writer := &fakeWriter{}
+		n := NewRecorder(1, writer, log.NewNopLogger()).Wrap(&fakeNotifier{retry: true, err: errors.New("service unavailable")}, integration)
+
+		retry, err := n.Notify(ctx, alerts...)
+		require.EqualError(t, err, "service unavailable")
+		require.True(t, retry)
+
+		require.Len(t, writer.entries, 1)
+		require.Equal(t, models.NotificationStatusFailed, writer.entries[0].Status)
+		require.Equal(t, "service unavailable", writer.entries[0].Error)
+	})
+
+	t.Run("should not fail the notification if the attempt cannot be recorded", func(t *testing.T) {
+		writer := &fakeWriter{err: errors.New("database is locked")}
+		n := NewRecorder(1, writer, log.NewNopLogger()).Wrap(&fakeNotifier{}, integration)
+
+		_, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.Len(t, writer.entries, 1)
+	})
+}
+
+var _ channels.NotificationChannel = &fakeNotifier{}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -114,15 +115,59 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 func (moa *MultiOrgAlertmanager) Run(ctx context.Context) error {
 	moa.logger.Info("starting MultiOrg Alertmanager")
 
+	configTicker := time.NewTicker(moa.settings.UnifiedAlerting.AlertmanagerConfigPollInterval)
+	defer configTicker.Stop()
+	silencesTicker := time.NewTicker(recurringSilencesSyncInterval)
+	defer silencesTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			moa.StopAndWait()
 			return nil
-		case <-time.After(moa.settings.UnifiedAlerting.AlertmanagerConfigPollInterval):
+		case <-configTicker.C:
 			if err := moa.LoadAndSyncAlertmanagersForOrgs(ctx); err != nil {
 				moa.logger.Error("error while synchronizing Alertmanager orgs", "error", err)
 			}
+		case <-silencesTicker.C:
+			moa.SyncRecurringSilences(ctx, time.Now())
+		}
+	}
+}
+
+// SyncRecurringSilences creates and expires the silences of the recurring silences of all organizations.
+// In a cluster only the first member does it, the silences are replicated to the other members.
+func (moa *MultiOrgAlertmanager) SyncRecurringSilences(ctx context.Context, now time.Time) {
+	if moa.peer.Position() != 0 {
+		return
+	}
+	stored, err := moa.configStore.GetAllRecurringSilences(ctx)
+	if err != nil {
+		moa.logger.Error("failed to load recurring silences", "error", err)
+		return
+	}
+	byOrg := make(map[int64][]apimodels.RecurringSilence)
+	for _, s := range stored {
+		silence, err := apimodels.NewRecurringSilence(s, models.ProvenanceNone)
+		if err != nil {
+			moa.logger.Error("failed to load recurring silence", "org", s.OrgID, "uid", s.UID, "error", err)
+			continue
+		}
+		byOrg[s.OrgID] = append(byOrg[s.OrgID], silence)
+	}
+
+	moa.alertmanagersMtx.RLock()
+	alertmanagers := make(map[int64]*Alertmanager, len(moa.alertmanagers))
+	for orgID, am := range moa.alertmanagers {
+		alertmanagers[orgID] = am
+	}
+	moa.alertmanagersMtx.RUnlock()
+
+	// Alertmanagers of organizations without recurring silences are synced as well,
+	// so that silences of deleted recurring silences are expired.
+	for orgID, am := range alertmanagers {
+		if err := am.SyncRecurringSilences(byOrg[orgID], now); err != nil {
+			moa.logger.Error("failed to sync recurring silences", "org", orgID, "error", err)
 		}
 	}
 }
@@ -231,6 +276,9 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 		moa.logger.Info("stopped Alertmanager", "org", orgID)
 		// Cleanup all the remaining resources from this alertmanager.
 		am.fileStore.CleanUp()
+		if err := moa.configStore.DeleteNotifierState(ctx, orgID); err != nil {
+			moa.logger.Error("failed to delete notifier state", "org", orgID, "error", err)
+		}
 	}
 
 	// We look for orphan directories and remove them. Orphan directories can
@@ -290,6 +338,19 @@ func (moa *MultiOrgAlertmanager) cleanupOrphanLocalOrgState(ctx context.Context,
 			}
 		}
 	}
+	// Remove the notifier state of organizations that no longer exist.
+	orgIDs, err := moa.configStore.GetNotifierStateOrgIDs(ctx)
+	if err != nil {
+		moa.logger.Error("failed to fetch organizations with notifier state", "error", err)
+	}
+	for _, orgID := range orgIDs {
+		if _, exists := activeOrganizations[orgID]; exists {
+			continue
+		}
+		if err := moa.configStore.DeleteNotifierState(ctx, orgID); err != nil {
+			moa.logger.Error("failed to delete notifier state", "orgID", orgID, "error", err)
+		}
+	}
 }
 
 func (moa *MultiOrgAlertmanager) StopAndWait() {
@@ -309,6 +370,21 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// ClusterMembers returns the name of this instance and the names of all alive members of the HA cluster, including this instance.
+// It returns an empty name and no members if Grafana does not run in HA mode.
+func (moa *MultiOrgAlertmanager) ClusterMembers() (string, []string) {
+	p, ok := moa.peer.(*cluster.Peer)
+	if !ok {
+		return "", nil
+	}
+	peers := p.Peers()
+	members := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		members = append(members, peer.Name())
+	}
+	return p.Name(), members
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
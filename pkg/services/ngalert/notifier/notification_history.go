@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// NotificationHistoryEnabled returns true if the notification attempts of the Alertmanager are recorded,
+// which is configured in the [unified_alerting.notification_history] section.
+func (am *Alertmanager) NotificationHistoryEnabled() bool {
+	return am.notificationHistory != nil
+}
+
+// GetNotificationHistory returns the notification attempts of the organization of the Alertmanager that match the query.
+func (am *Alertmanager) GetNotificationHistory(ctx context.Context, query ngmodels.NotificationHistoryQuery) (apimodels.NotificationHistory, error) {
+	query.OrgID = am.orgID
+	entries, err := am.Store.QueryNotificationHistory(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(apimodels.NotificationHistory, 0, len(entries))
+	for _, e := range entries {
+		var fingerprints []string
+		if e.Fingerprints != "" {
+			fingerprints = strings.Split(e.Fingerprints, ",")
+		}
+		result = append(result, apimodels.NotificationAttempt{
+			Receiver:         e.Receiver,
+			Integration:      e.Integration,
+			IntegrationIndex: e.IntegrationIndex,
+			IntegrationUID:   e.IntegrationUID,
+			GroupKey:         e.GroupKey,
+			Fingerprints:     fingerprints,
+			Status:           e.Status,
+			Error:            e.Error,
+			Duration:         e.Duration,
+			At:               time.UnixMilli(e.At).UTC(),
+		})
+	}
+	return result, nil
+}
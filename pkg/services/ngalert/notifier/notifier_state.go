@@ -0,0 +1,163 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/alerting/alerting"
+	"github.com/prometheus/alertmanager/cluster"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// stateKinds maps the prefixes of the keys that the Alertmanager registers its state with at the cluster peer
+// to the kinds of state in the notifier state store.
+var stateKinds = map[string]string{
+	"notificationlog": notificationLogFilename,
+	"silences":        silencesFilename,
+}
+
+// notifierStateWriteTimeout is the maximum time that saving a change can take.
+const notifierStateWriteTimeout = 10 * time.Second
+
+// persistingPeer writes every change of the silences and the notification log of an Alertmanager to the
+// notifier state store before it is broadcast to the other members of the cluster. Only the changes that are
+// made by this Alertmanager are saved: the changes that are received from the other members of the cluster
+// are saved by the member that made them.
+type persistingPeer struct {
+	alerting.ClusterPeer
+	orgID  int64
+	store  store.NotifierStateStore
+	logger log.Logger
+
+	mtx sync.Mutex
+	// merging has the first byte of the messages that are being merged into the state, see persistingState.
+	merging map[*byte]int
+}
+
+func newPersistingPeer(peer alerting.ClusterPeer, orgID int64, store store.NotifierStateStore, logger log.Logger) *persistingPeer {
+	return &persistingPeer{
+		ClusterPeer: peer,
+		orgID:       orgID,
+		store:       store,
+		logger:      logger,
+		merging:     make(map[*byte]int),
+	}
+}
+
+func (p *persistingPeer) AddState(key string, s cluster.State, reg prometheus.Registerer) cluster.ClusterChannel {
+	prefix, _, _ := strings.Cut(key, ":")
+	kind, ok := stateKinds[prefix]
+	if !ok {
+		return p.ClusterPeer.AddState(key, s, reg)
+	}
+	channel := p.ClusterPeer.AddState(key, &persistingState{State: s, peer: p}, reg)
+	return &persistingChannel{
+		ClusterChannel: channel,
+		kind:           kind,
+		peer:           p,
+	}
+}
+
+// save writes a change of the kind of state to the notifier state store.
+func (p *persistingPeer) save(kind string, b []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifierStateWriteTimeout)
+	defer cancel()
+	if err := p.store.AppendNotifierState(ctx, p.orgID, kind, b); err != nil {
+		p.logger.Error("Failed to save notifier state", "kind", kind, "error", err)
+	}
+}
+
+func (p *persistingPeer) setMerging(b []byte, merging bool) {
+	if len(b) == 0 {
+		return
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if merging {
+		p.merging[&b[0]]++
+		return
+	}
+	if p.merging[&b[0]]--; p.merging[&b[0]] <= 0 {
+		delete(p.merging, &b[0])
+	}
+}
+
+func (p *persistingPeer) isMerging(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.merging[&b[0]] > 0
+}
+
+// persistingState tracks the messages that are merged into the state. The silences and the notification log
+// broadcast the message they merged again if it changed their state, so that it reaches all the members of
+// the cluster. These messages are the same slice that was merged, which is how persistingChannel tells them
+// apart from the changes made by this Alertmanager.
+type persistingState struct {
+	cluster.State
+	peer *persistingPeer
+}
+
+func (s *persistingState) Merge(b []byte) error {
+	s.peer.setMerging(b, true)
+	defer s.peer.setMerging(b, false)
+	return s.State.Merge(b)
+}
+
+// persistingChannel saves the changes it broadcasts. Changes are broadcast while the state holds its lock,
+// so the changes of a kind of state are saved in the order they were made.
+type persistingChannel struct {
+	cluster.ClusterChannel
+	kind string
+	peer *persistingPeer
+}
+
+func (c *persistingChannel) Broadcast(b []byte) {
+	if !c.peer.isMerging(b) {
+		c.peer.save(c.kind, b)
+	}
+	c.ClusterChannel.Broadcast(b)
+}
+
+// loadNotifierState returns the path of the snapshot file of the kind of state that the Alertmanager loads
+// on start. The file is written from the notifier state store. If the store does not have state of the kind
+// yet, the snapshot that was synced to the kvstore by previous versions is used.
+func loadNotifierState(ctx context.Context, orgID int64, kind string, st store.NotifierStateStore, fileStore *FileStore) (string, error) {
+	state, err := st.GetNotifierState(ctx, orgID, kind)
+	if err != nil {
+		return "", err
+	}
+	if state == nil {
+		return fileStore.FilepathFor(ctx, kind)
+	}
+	if err := fileStore.WriteFileToDisk(kind, state); err != nil {
+		return "", fmt.Errorf("error writing file %s: %w", kind, err)
+	}
+	return fileStore.pathFor(kind), nil
+}
+
+// compactNotifierState replaces the changes of the kind of state that were saved so far by a snapshot of the state.
+func compactNotifierState(ctx context.Context, orgID int64, kind string, st store.NotifierStateStore, state alerting.State) (int64, error) {
+	// Every change with an ID up to this one is already part of the state, because changes are saved
+	// after they are applied.
+	lastID, err := st.GetLastNotifierStateID(ctx, orgID, kind)
+	if err != nil {
+		return 0, err
+	}
+	b, err := state.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	if err := st.CompactNotifierState(ctx, orgID, kind, lastID, b); err != nil {
+		return 0, err
+	}
+	return int64(len(b)), nil
+}
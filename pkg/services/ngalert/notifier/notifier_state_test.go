@@ -0,0 +1,206 @@
+package notifier
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/alerting/alerting"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestPersistingPeer(t *testing.T) {
+	ctx := context.Background()
+	st := &fakeNotifierStateStore{}
+
+	newSilences := func(t *testing.T, peer *persistingPeer, key string) *silence.Silences {
+		t.Helper()
+		silences, err := silence.New(silence.Options{})
+		require.NoError(t, err)
+		silences.SetBroadcast(peer.AddState(key, silences, nil).Broadcast)
+		return silences
+	}
+	newSilence := func(comment string) *silencepb.Silence {
+		now := time.Now()
+		return &silencepb.Silence{
+			Matchers:  []*silencepb.Matcher{{Type: silencepb.Matcher_EQUAL, Name: "cluster", Pattern: "eu-1"}},
+			StartsAt:  now,
+			EndsAt:    now.Add(time.Hour),
+			UpdatedAt: now,
+			Comment:   comment,
+			CreatedBy: "ops",
+		}
+	}
+	restore := func(t *testing.T, orgID int64) *silence.Silences {
+		t.Helper()
+		fileStore := NewFileStore(orgID, NewFakeKVStore(t), t.TempDir())
+		path, err := loadNotifierState(ctx, orgID, silencesFilename, st, fileStore)
+		require.NoError(t, err)
+		restored, err := silence.New(silence.Options{SnapshotFile: path})
+		require.NoError(t, err)
+		return restored
+	}
+
+	peer := newPersistingPeer(&alerting.NilPeer{}, 1, st, log.NewNopLogger())
+	silences := newSilences(t, peer, "silences:1")
+
+	t.Run("every change is saved when it is made", func(t *testing.T) {
+		id, err := silences.Set(newSilence("first"))
+		require.NoError(t, err)
+		require.Len(t, st.entries, 1)
+		_, err = silences.Set(newSilence("second"))
+		require.NoError(t, err)
+		require.NoError(t, silences.Expire(id))
+
+		require.Len(t, st.entries, 3)
+		for _, e := range st.entries {
+			require.Equal(t, int64(1), e.OrgID)
+			require.Equal(t, silencesFilename, e.Kind)
+		}
+
+		restored := restore(t, 1)
+		result, _, err := restored.Query()
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		active, _, err := restored.Query(silence.QState(types.SilenceStateActive))
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		require.Equal(t, "second", active[0].Comment)
+	})
+
+	t.Run("compaction replaces the changes with a snapshot", func(t *testing.T) {
+		_, err := compactNotifierState(ctx, 1, silencesFilename, st, silences)
+		require.NoError(t, err)
+		require.Len(t, st.entries, 1)
+
+		restored := restore(t, 1)
+		result, _, err := restored.Query()
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+	})
+
+	t.Run("changes received from the cluster are not saved", func(t *testing.T) {
+		// The changes of the other member of the cluster are saved by that member.
+		var messages [][]byte
+		origin, err := silence.New(silence.Options{})
+		require.NoError(t, err)
+		origin.SetBroadcast(func(b []byte) { messages = append(messages, b) })
+		_, err = origin.Set(newSilence("remote"))
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+
+		peer := newPersistingPeer(&alerting.NilPeer{}, 2, st, log.NewNopLogger())
+		silences, err := silence.New(silence.Options{})
+		require.NoError(t, err)
+		var rebroadcast [][]byte
+		channel := peer.AddState("silences:2", silences, nil)
+		silences.SetBroadcast(func(b []byte) {
+			rebroadcast = append(rebroadcast, b)
+			channel.Broadcast(b)
+		})
+		state := &persistingState{State: silences, peer: peer}
+		require.NoError(t, state.Merge(messages[0]))
+		// The merged change is broadcast again, but it is not saved.
+		require.Len(t, rebroadcast, 1)
+
+		_, err = silences.Set(newSilence("local"))
+		require.NoError(t, err)
+
+		restored := restore(t, 2)
+		result, _, err := restored.Query()
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, "local", result[0].Comment)
+	})
+
+	t.Run("other state is not saved", func(t *testing.T) {
+		peer := newPersistingPeer(&alerting.NilPeer{}, 3, st, log.NewNopLogger())
+		other := newSilences(t, peer, "other:3")
+		_, err := other.Set(newSilence("other"))
+		require.NoError(t, err)
+
+		state, err := st.GetNotifierState(ctx, 3, silencesFilename)
+		require.NoError(t, err)
+		require.Nil(t, state)
+	})
+
+	t.Run("the kvstore is used if there is no saved state", func(t *testing.T) {
+		fileStore := NewFileStore(4, NewFakeKVStore(t), t.TempDir())
+		path, err := loadNotifierState(ctx, 4, silencesFilename, st, fileStore)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(fileStore.workingDirPath, silencesFilename), path)
+		require.NoFileExists(t, path)
+	})
+}
+
+type fakeNotifierStateStore struct {
+	mtx     sync.Mutex
+	entries []models.NotifierStateEntry
+	lastID  int64
+}
+
+func (f *fakeNotifierStateStore) GetNotifierState(_ context.Context, orgID int64, kind string) ([]byte, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var result []byte
+	for _, e := range f.entries {
+		if e.OrgID == orgID && e.Kind == kind {
+			result = append(result, e.Data...)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeNotifierStateStore) AppendNotifierState(_ context.Context, orgID int64, kind string, data []byte) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.lastID++
+	f.entries = append(f.entries, models.NotifierStateEntry{ID: f.lastID, OrgID: orgID, Kind: kind, Data: data})
+	return nil
+}
+
+func (f *fakeNotifierStateStore) GetLastNotifierStateID(_ context.Context, orgID int64, kind string) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var lastID int64
+	for _, e := range f.entries {
+		if e.OrgID == orgID && e.Kind == kind {
+			lastID = e.ID
+		}
+	}
+	return lastID, nil
+}
+
+func (f *fakeNotifierStateStore) CompactNotifierState(_ context.Context, orgID int64, kind string, lastID int64, snapshot []byte) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var entries []models.NotifierStateEntry
+	for _, e := range f.entries {
+		if e.OrgID == orgID && e.Kind == kind && e.ID <= lastID {
+			if e.ID == lastID {
+				e.Data = snapshot
+				entries = append(entries, e)
+			}
+			continue
+		}
+		entries = append(entries, e)
+	}
+	f.entries = entries
+	return nil
+}
+
+func (f *fakeNotifierStateStore) GetNotifierStateOrgIDs(context.Context) ([]int64, error) {
+	return nil, nil
+}
+
+func (f *fakeNotifierStateStore) DeleteNotifierState(context.Context, int64) error {
+	return nil
+}
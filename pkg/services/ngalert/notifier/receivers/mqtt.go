@@ -0,0 +1,226 @@
+package receivers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/grafana/alerting/alerting/notifier/channels"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const (
+	// MessageFormatJSON sends the alerts, the group key and the templated message as a JSON document.
+	MessageFormatJSON = "json"
+	// MessageFormatText sends only the templated message.
+	MessageFormatText = "text"
+
+	// mqttClientIDPrefix is the prefix of the client IDs that are generated when no client ID is configured.
+	mqttClientIDPrefix = "grafana-"
+	mqttTimeout        = 10 * time.Second
+)
+
+// MQTTNotifier is responsible for sending alert notifications to an MQTT broker.
+type MQTTNotifier struct {
+	*channels.Base
+	log      channels.Logger
+	tmpl     *template.Template
+	settings mqttSettings
+	client   mqttClient
+}
+
+type mqttSettings struct {
+	BrokerURL          string `json:"brokerUrl,omitempty" yaml:"brokerUrl,omitempty"`
+	ClientID           string `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	Topic              string `json:"topic,omitempty" yaml:"topic,omitempty"`
+	MessageFormat      string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+	Message            string `json:"message,omitempty" yaml:"message,omitempty"`
+	Username           string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password           string `json:"password,omitempty" yaml:"password,omitempty"`
+	QoS                string `json:"qos,omitempty" yaml:"qos,omitempty"`
+	Retain             bool   `json:"retain,omitempty" yaml:"retain,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+
+	qos byte
+}
+
+// mqttClient publishes a single message to a broker.
+type mqttClient interface {
+	Publish(ctx context.Context, settings mqttSettings, payload []byte) error
+}
+
+func buildMQTTSettings(fc channels.FactoryConfig) (mqttSettings, error) {
+	var settings mqttSettings
+	if err := json.Unmarshal(fc.Config.Settings, &settings); err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if settings.BrokerURL == "" {
+		return settings, errors.New("required field 'brokerUrl' is not specified")
+	}
+	u, err := url.Parse(settings.BrokerURL)
+	if err != nil {
+		return settings, fmt.Errorf("invalid broker URL: %w", err)
+	}
+	switch u.Scheme {
+	case "tcp", "ssl", "tls", "ws", "wss", "mqtt", "mqtts":
+	default:
+		return settings, fmt.Errorf("invalid broker URL scheme %q, expected one of tcp, ssl, tls, ws, wss, mqtt, mqtts", u.Scheme)
+	}
+	if settings.Topic == "" {
+		return settings, errors.New("required field 'topic' is not specified")
+	}
+	switch settings.MessageFormat {
+	case "":
+		settings.MessageFormat = MessageFormatJSON
+	case MessageFormatJSON, MessageFormatText:
+	default:
+		return settings, fmt.Errorf("invalid message format %q, expected %s or %s", settings.MessageFormat, MessageFormatJSON, MessageFormatText)
+	}
+	if settings.Message == "" {
+		settings.Message = channels.DefaultMessageEmbed
+	}
+	if settings.QoS != "" {
+		qos, err := strconv.Atoi(settings.QoS)
+		if err != nil || qos < 0 || qos > 2 {
+			return settings, fmt.Errorf("invalid QoS %q, expected 0, 1 or 2", settings.QoS)
+		}
+		settings.qos = byte(qos)
+	}
+	settings.Password = fc.DecryptFunc(context.Background(), fc.Config.SecureSettings, "password", settings.Password)
+	return settings, nil
+}
+
+// MQTTFactory creates a new MQTT notifier from the configuration of a contact point.
+func MQTTFactory(fc channels.FactoryConfig) (channels.NotificationChannel, error) {
+	settings, err := buildMQTTSettings(fc)
+	if err != nil {
+		return nil, receiverInitError{
+			Reason: err.Error(),
+			Cfg:    *fc.Config,
+		}
+	}
+	return &MQTTNotifier{
+		Base:     channels.NewBase(fc.Config),
+		log:      fc.Logger,
+		tmpl:     fc.Template,
+		settings: settings,
+		client:   pahoClient{},
+	}, nil
+}
+
+// jsonMessage defines the JSON object that is published in the json message format.
+type jsonMessage struct {
+	*channels.ExtendedData
+
+	GroupKey string `json:"groupKey"`
+	Message  string `json:"message"`
+}
+
+// Notify publishes the alerts to the topic of the broker.
+func (n *MQTTNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, data := channels.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+	message := tmpl(n.settings.Message)
+	if tmplErr != nil {
+		n.log.Warn("failed to template MQTT message", "error", tmplErr.Error())
+	}
+
+	var payload []byte
+	if n.settings.MessageFormat == MessageFormatText {
+		payload = []byte(message)
+	} else {
+		payload, err = json.Marshal(jsonMessage{
+			ExtendedData: data,
+			GroupKey:     groupKey.String(),
+			Message:      message,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := n.client.Publish(ctx, n.settings, payload); err != nil {
+		n.log.Error("failed to publish MQTT message", "error", err, "broker", n.settings.BrokerURL, "topic", n.settings.Topic)
+		return true, err
+	}
+	return true, nil
+}
+
+// SendResolved returns true if resolved notifications should be sent.
+func (n *MQTTNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
+
+// pahoClient connects to the broker for every message, so no connections are kept open between notifications.
+type pahoClient struct{}
+
+func (pahoClient) Publish(ctx context.Context, settings mqttSettings, payload []byte) error {
+	clientID := settings.ClientID
+	if clientID == "" {
+		// Brokers disconnect the client that is connected with the same ID, so that concurrent notifications
+		// would disconnect each other.
+		id, err := newMQTTClientID()
+		if err != nil {
+			return err
+		}
+		clientID = id
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(settings.BrokerURL).
+		SetClientID(clientID).
+		SetUsername(settings.Username).
+		SetPassword(settings.Password).
+		SetConnectTimeout(mqttTimeout).
+		SetAutoReconnect(false).
+		SetTLSConfig(&tls.Config{
+			// nolint:gosec
+			InsecureSkipVerify: settings.InsecureSkipVerify,
+		})
+
+	client := mqtt.NewClient(opts)
+	if err := wait(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer client.Disconnect(250)
+
+	if err := wait(ctx, client.Publish(settings.Topic, settings.qos, settings.Retain, payload)); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// newMQTTClientID returns a random client ID. It is shorter than 23 characters, the maximum length that all brokers accept.
+func newMQTTClientID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate client ID: %w", err)
+	}
+	return mqttClientIDPrefix + hex.EncodeToString(b), nil
+}
+
+func wait(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(mqttTimeout):
+		return errors.New("timeout")
+	}
+}
@@ -0,0 +1,171 @@
+package receivers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/grafana/alerting/alerting/notifier/channels"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMQTTClient struct {
+	settings mqttSettings
+	payload  []byte
+	err      error
+}
+
+func (c *fakeMQTTClient) Publish(_ context.Context, settings mqttSettings, payload []byte) error {
+	c.settings = settings
+	c.payload = payload
+	return c.err
+}
+
+func TestMQTTNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		settings       string
+		secureSettings map[string][]byte
+		publishErr     error
+		expInitError   string
+		expMsgError    error
+		expPayload     string
+		expQoS         byte
+		expPassword    string
+	}{
+		{
+			name: "text message with custom template",
+			settings: `{
+				"brokerUrl": "tcp://localhost:1883",
+				"topic": "grafana/alerts",
+				"messageFormat": "text",
+				"message": "{{ len .Alerts.Firing }} alerts are firing",
+				"qos": "1",
+				"username": "grafana"
+			}`,
+			secureSettings: map[string][]byte{"password": []byte("secret")},
+			expPayload:     "1 alerts are firing",
+			expQoS:         1,
+			expPassword:    "secret",
+		},
+		{
+			name: "JSON message by default",
+			settings: `{
+				"brokerUrl": "tcp://localhost:1883",
+				"topic": "grafana/alerts",
+				"message": "custom message"
+			}`,
+		},
+		{
+			name:         "missing broker URL",
+			settings:     `{"topic": "grafana/alerts"}`,
+			expInitError: "failed to validate receiver \"mqtt_testing\" of type \"mqtt\": required field 'brokerUrl' is not specified",
+		},
+		{
+			name:         "invalid broker URL scheme",
+			settings:     `{"brokerUrl": "http://localhost:1883", "topic": "grafana/alerts"}`,
+			expInitError: "failed to validate receiver \"mqtt_testing\" of type \"mqtt\": invalid broker URL scheme \"http\", expected one of tcp, ssl, tls, ws, wss, mqtt, mqtts",
+		},
+		{
+			name:         "missing topic",
+			settings:     `{"brokerUrl": "tcp://localhost:1883"}`,
+			expInitError: "failed to validate receiver \"mqtt_testing\" of type \"mqtt\": required field 'topic' is not specified",
+		},
+		{
+			name:         "invalid QoS",
+			settings:     `{"brokerUrl": "tcp://localhost:1883", "topic": "grafana/alerts", "qos": "3"}`,
+			expInitError: "failed to validate receiver \"mqtt_testing\" of type \"mqtt\": invalid QoS \"3\", expected 0, 1 or 2",
+		},
+		{
+			name:         "invalid message format",
+			settings:     `{"brokerUrl": "tcp://localhost:1883", "topic": "grafana/alerts", "messageFormat": "xml"}`,
+			expInitError: "failed to validate receiver \"mqtt_testing\" of type \"mqtt\": invalid message format \"xml\", expected json or text",
+		},
+		{
+			name:        "publish error",
+			settings:    `{"brokerUrl": "tcp://localhost:1883", "topic": "grafana/alerts"}`,
+			publishErr:  errors.New("connection refused"),
+			expMsgError: errors.New("connection refused"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fc := channels.FactoryConfig{
+				Config: &channels.NotificationChannelConfig{
+					Name:           "mqtt_testing",
+					Type:           "mqtt",
+					Settings:       json.RawMessage(c.settings),
+					SecureSettings: c.secureSettings,
+				},
+				DecryptFunc: decryptForTests,
+				Template:    tmpl,
+				Logger:      &channels.FakeLogger{},
+			}
+
+			n, err := MQTTFactory(fc)
+			if c.expInitError != "" {
+				require.EqualError(t, err, c.expInitError)
+				return
+			}
+			require.NoError(t, err)
+
+			client := &fakeMQTTClient{err: c.publishErr}
+			notifier := n.(*MQTTNotifier)
+			notifier.client = client
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ok, err := notifier.Notify(ctx, alerts...)
+			require.True(t, ok)
+			if c.expMsgError != nil {
+				require.EqualError(t, err, c.expMsgError.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expQoS, client.settings.qos)
+			require.Equal(t, c.expPassword, client.settings.Password)
+
+			if c.expPayload != "" {
+				require.Equal(t, c.expPayload, string(client.payload))
+				return
+			}
+			var msg map[string]interface{}
+			require.NoError(t, json.Unmarshal(client.payload, &msg))
+			require.Equal(t, "custom message", msg["message"])
+			require.Equal(t, "firing", msg["status"])
+			require.NotEmpty(t, msg["groupKey"])
+			require.Len(t, msg["alerts"], 1)
+		})
+	}
+}
+
+func TestNewMQTTClientID(t *testing.T) {
+	first, err := newMQTTClientID()
+	require.NoError(t, err)
+	second, err := newMQTTClientID()
+	require.NoError(t, err)
+
+	require.NotEqual(t, first, second)
+	require.True(t, strings.HasPrefix(first, mqttClientIDPrefix))
+	require.LessOrEqual(t, len(first), 23)
+}
@@ -0,0 +1,198 @@
+package receivers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/grafana/alerting/alerting/notifier/channels"
+	"github.com/grafana/grafana-aws-sdk/pkg/awsds"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+const (
+	// snsMaxSubjectLenRunes is the maximum length of the subject of an SNS message.
+	snsMaxSubjectLenRunes = 100
+	// snsMaxMessageLenBytes is the maximum size of an SNS message.
+	snsMaxMessageLenBytes = 256 * 1024
+)
+
+// SNSNotifier is responsible for sending alert notifications to an Amazon SNS topic.
+type SNSNotifier struct {
+	*channels.Base
+	log      channels.Logger
+	tmpl     *template.Template
+	settings snsSettings
+	client   snsClient
+}
+
+type snsSettings struct {
+	TopicARN      string `json:"topicArn,omitempty" yaml:"topicArn,omitempty"`
+	Region        string `json:"region,omitempty" yaml:"region,omitempty"`
+	Endpoint      string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	AccessKey     string `json:"accessKey,omitempty" yaml:"accessKey,omitempty"`
+	SecretKey     string `json:"secretKey,omitempty" yaml:"secretKey,omitempty"`
+	AssumeRoleARN string `json:"assumeRoleArn,omitempty" yaml:"assumeRoleArn,omitempty"`
+	ExternalID    string `json:"externalId,omitempty" yaml:"externalId,omitempty"`
+	MessageFormat string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+	Subject       string `json:"subject,omitempty" yaml:"subject,omitempty"`
+	Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// snsClient publishes a single message to a topic.
+type snsClient interface {
+	Publish(ctx context.Context, settings snsSettings, input *sns.PublishInput) error
+}
+
+func buildSNSSettings(fc channels.FactoryConfig) (snsSettings, error) {
+	var settings snsSettings
+	if err := json.Unmarshal(fc.Config.Settings, &settings); err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if settings.TopicARN == "" {
+		return settings, errors.New("required field 'topicArn' is not specified")
+	}
+	topic, err := arn.Parse(settings.TopicARN)
+	if err != nil {
+		return settings, fmt.Errorf("invalid topic ARN: %w", err)
+	}
+	if settings.Region == "" {
+		settings.Region = topic.Region
+	}
+	if settings.Endpoint != "" {
+		endpoint, err := url.Parse(settings.Endpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return settings, fmt.Errorf("invalid endpoint %q, expected an http or https URL", settings.Endpoint)
+		}
+	}
+	switch settings.MessageFormat {
+	case "":
+		settings.MessageFormat = MessageFormatText
+	case MessageFormatJSON, MessageFormatText:
+	default:
+		return settings, fmt.Errorf("invalid message format %q, expected %s or %s", settings.MessageFormat, MessageFormatJSON, MessageFormatText)
+	}
+	if settings.Subject == "" {
+		settings.Subject = channels.DefaultMessageTitleEmbed
+	}
+	if settings.Message == "" {
+		settings.Message = channels.DefaultMessageEmbed
+	}
+	settings.AccessKey = fc.DecryptFunc(context.Background(), fc.Config.SecureSettings, "accessKey", settings.AccessKey)
+	settings.SecretKey = fc.DecryptFunc(context.Background(), fc.Config.SecureSettings, "secretKey", settings.SecretKey)
+	if (settings.AccessKey == "") != (settings.SecretKey == "") {
+		return settings, errors.New("both access key and secret key must be specified")
+	}
+	return settings, nil
+}
+
+// SNSFactory creates a new SNS notifier from the configuration of a contact point.
+func SNSFactory(fc channels.FactoryConfig) (channels.NotificationChannel, error) {
+	settings, err := buildSNSSettings(fc)
+	if err != nil {
+		return nil, receiverInitError{
+			Reason: err.Error(),
+			Cfg:    *fc.Config,
+		}
+	}
+	return &SNSNotifier{
+		Base:     channels.NewBase(fc.Config),
+		log:      fc.Logger,
+		tmpl:     fc.Template,
+		settings: settings,
+		client:   awsSNSClient{sessions: awsds.NewSessionCache()},
+	}, nil
+}
+
+// Notify publishes the alerts to the SNS topic.
+func (n *SNSNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, data := channels.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+	subject, truncated := channels.TruncateInRunes(tmpl(n.settings.Subject), snsMaxSubjectLenRunes)
+	if truncated {
+		n.log.Warn("Truncated subject", "max_runes", snsMaxSubjectLenRunes)
+	}
+	message := tmpl(n.settings.Message)
+	if tmplErr != nil {
+		n.log.Warn("failed to template SNS message", "error", tmplErr.Error())
+	}
+
+	if n.settings.MessageFormat == MessageFormatJSON {
+		body, err := json.Marshal(jsonMessage{
+			ExtendedData: data,
+			GroupKey:     groupKey.String(),
+			Message:      message,
+		})
+		if err != nil {
+			return false, err
+		}
+		message = string(body)
+	}
+	message, truncated = channels.TruncateInBytes(message, snsMaxMessageLenBytes)
+	if truncated {
+		n.log.Warn("Truncated message", "max_bytes", snsMaxMessageLenBytes)
+	}
+
+	input := &sns.PublishInput{
+		TopicArn: aws.String(n.settings.TopicARN),
+		Subject:  aws.String(subject),
+		Message:  aws.String(message),
+	}
+	if err := n.client.Publish(ctx, n.settings, input); err != nil {
+		n.log.Error("failed to publish SNS message", "error", err, "topic", n.settings.TopicARN)
+		return true, err
+	}
+	return true, nil
+}
+
+// SendResolved returns true if resolved notifications should be sent.
+func (n *SNSNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
+
+// awsSNSClient publishes messages with the AWS sessions of the AWS data sources. Without an access key, the default
+// credential chain of the AWS SDK is used, e.g. environment variables or the instance role. Like for the AWS data
+// sources, the allowed_auth_providers and assume_role_enabled settings of the [aws] section restrict
+// the credentials and the roles that can be used.
+type awsSNSClient struct {
+	sessions *awsds.SessionCache
+}
+
+func (c awsSNSClient) Publish(ctx context.Context, settings snsSettings, input *sns.PublishInput) error {
+	authType := awsds.AuthTypeDefault
+	if settings.AccessKey != "" {
+		authType = awsds.AuthTypeKeys
+	}
+	sess, err := c.sessions.GetSession(awsds.SessionConfig{
+		Settings: awsds.AWSDatasourceSettings{
+			Region:        settings.Region,
+			Endpoint:      settings.Endpoint,
+			AuthType:      authType,
+			AccessKey:     settings.AccessKey,
+			SecretKey:     settings.SecretKey,
+			AssumeRoleARN: settings.AssumeRoleARN,
+			ExternalID:    settings.ExternalID,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	if _, err := sns.New(sess).PublishWithContext(ctx, input); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package receivers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/grafana/alerting/alerting/notifier/channels"
+	"github.com/grafana/grafana-aws-sdk/pkg/awsds"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSNSClient struct {
+	settings snsSettings
+	input    *sns.PublishInput
+	err      error
+}
+
+func (c *fakeSNSClient) Publish(_ context.Context, settings snsSettings, input *sns.PublishInput) error {
+	c.settings = settings
+	c.input = input
+	return c.err
+}
+
+func TestSNSNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		settings       string
+		secureSettings map[string][]byte
+		publishErr     error
+		expInitError   string
+		expMsgError    error
+		expSubject     string
+		expMessage     string
+		expRegion      string
+		expAccessKey   string
+	}{
+		{
+			name: "custom subject and message",
+			settings: `{
+				"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts",
+				"subject": "{{ .CommonLabels.alertname }}",
+				"message": "{{ len .Alerts.Firing }} alerts are firing"
+			}`,
+			secureSettings: map[string][]byte{"accessKey": []byte("key"), "secretKey": []byte("secret")},
+			expSubject:     "alert1",
+			expMessage:     "1 alerts are firing",
+			expRegion:      "eu-west-1",
+			expAccessKey:   "key",
+		},
+		{
+			name: "region overrides the region of the topic",
+			settings: `{
+				"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts",
+				"region": "us-east-1",
+				"endpoint": "http://localhost:4566",
+				"subject": "subject",
+				"message": "message"
+			}`,
+			expSubject: "subject",
+			expMessage: "message",
+			expRegion:  "us-east-1",
+		},
+		{
+			name: "subject is truncated",
+			settings: `{
+				"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts",
+				"subject": "` + strings.Repeat("a", 120) + `",
+				"message": "message"
+			}`,
+			expSubject: strings.Repeat("a", 99) + "…",
+			expMessage: "message",
+			expRegion:  "eu-west-1",
+		},
+		{
+			name:         "missing topic",
+			settings:     `{}`,
+			expInitError: "failed to validate receiver \"sns_testing\" of type \"sns\": required field 'topicArn' is not specified",
+		},
+		{
+			name:         "invalid topic",
+			settings:     `{"topicArn": "alerts"}`,
+			expInitError: "failed to validate receiver \"sns_testing\" of type \"sns\": invalid topic ARN: arn: invalid prefix",
+		},
+		{
+			name:         "invalid endpoint",
+			settings:     `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts", "endpoint": "localhost:4566"}`,
+			expInitError: "failed to validate receiver \"sns_testing\" of type \"sns\": invalid endpoint \"localhost:4566\", expected an http or https URL",
+		},
+		{
+			name:           "access key without secret key",
+			settings:       `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts"}`,
+			secureSettings: map[string][]byte{"accessKey": []byte("key")},
+			expInitError:   "failed to validate receiver \"sns_testing\" of type \"sns\": both access key and secret key must be specified",
+		},
+		{
+			name:        "publish error",
+			settings:    `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts"}`,
+			publishErr:  errors.New("access denied"),
+			expMsgError: errors.New("access denied"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fc := channels.FactoryConfig{
+				Config: &channels.NotificationChannelConfig{
+					Name:           "sns_testing",
+					Type:           "sns",
+					Settings:       json.RawMessage(c.settings),
+					SecureSettings: c.secureSettings,
+				},
+				DecryptFunc: decryptForTests,
+				Template:    tmpl,
+				Logger:      &channels.FakeLogger{},
+			}
+
+			n, err := SNSFactory(fc)
+			if c.expInitError != "" {
+				require.EqualError(t, err, c.expInitError)
+				return
+			}
+			require.NoError(t, err)
+
+			client := &fakeSNSClient{err: c.publishErr}
+			notifier := n.(*SNSNotifier)
+			notifier.client = client
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ok, err := notifier.Notify(ctx, alerts...)
+			require.True(t, ok)
+			if c.expMsgError != nil {
+				require.EqualError(t, err, c.expMsgError.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "arn:aws:sns:eu-west-1:123456789012:alerts", aws.StringValue(client.input.TopicArn))
+			require.Equal(t, c.expSubject, aws.StringValue(client.input.Subject))
+			require.Equal(t, c.expMessage, aws.StringValue(client.input.Message))
+			require.Equal(t, c.expRegion, client.settings.Region)
+			require.Equal(t, c.expAccessKey, client.settings.AccessKey)
+		})
+	}
+}
+
+func TestAWSSNSClient(t *testing.T) {
+	input := &sns.PublishInput{
+		TopicArn: aws.String("arn:aws:sns:eu-west-1:123456789012:alerts"),
+		Message:  aws.String("message"),
+	}
+
+	t.Run("default credential chain is rejected if it is not an allowed auth provider", func(t *testing.T) {
+		t.Setenv(awsds.AllowedAuthProvidersEnvVarKeyName, "keys")
+		client := awsSNSClient{sessions: awsds.NewSessionCache()}
+
+		err := client.Publish(context.Background(), snsSettings{Region: "eu-west-1"}, input)
+		require.EqualError(t, err, `failed to create AWS session: attempting to use an auth type that is not allowed: "default"`)
+	})
+
+	t.Run("access keys are rejected if they are not an allowed auth provider", func(t *testing.T) {
+		t.Setenv(awsds.AllowedAuthProvidersEnvVarKeyName, "default")
+		client := awsSNSClient{sessions: awsds.NewSessionCache()}
+
+		err := client.Publish(context.Background(), snsSettings{Region: "eu-west-1", AccessKey: "key", SecretKey: "secret"}, input)
+		require.EqualError(t, err, `failed to create AWS session: attempting to use an auth type that is not allowed: "keys"`)
+	})
+
+	t.Run("assuming a role is rejected if it is disabled", func(t *testing.T) {
+		t.Setenv(awsds.AllowedAuthProvidersEnvVarKeyName, "default,keys")
+		t.Setenv(awsds.AssumeRoleEnabledEnvVarKeyName, "false")
+		client := awsSNSClient{sessions: awsds.NewSessionCache()}
+
+		settings := snsSettings{Region: "eu-west-1", AccessKey: "key", SecretKey: "secret", AssumeRoleARN: "arn:aws:iam::123456789012:role/grafana"}
+		err := client.Publish(context.Background(), settings, input)
+		require.EqualError(t, err, "failed to create AWS session: attempting to use assume role (ARN) which is disabled in grafana.ini")
+	})
+}
@@ -0,0 +1,39 @@
+package receivers
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/grafana/alerting/alerting/notifier/channels"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/stretchr/testify/require"
+)
+
+func templateForTests(t *testing.T) *template.Template {
+	f, err := os.CreateTemp("/tmp", "template")
+	require.NoError(t, err)
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(f.Name()))
+	})
+
+	_, err = f.WriteString(channels.TemplateForTestsString)
+	require.NoError(t, err)
+
+	tmpl, err := template.FromGlobs([]string{f.Name()})
+	require.NoError(t, err)
+
+	return tmpl
+}
+
+// decryptForTests returns the secure setting as is, or the fallback if it does not exist.
+func decryptForTests(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+	if v, ok := sjd[key]; ok {
+		return string(v)
+	}
+	return fallback
+}
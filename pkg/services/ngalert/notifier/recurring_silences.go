@@ -0,0 +1,236 @@
+package notifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/grafana/alerting/alerting"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/robfig/cron/v3"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// recurringSilencesSyncInterval is how often the silences of recurring silences are created and expired.
+const recurringSilencesSyncInterval = time.Minute
+
+// maxRecurringSilenceOccurrence is how far ahead the end of an occurrence that is defined by time intervals
+// is searched. Occurrences that last longer are silenced in chunks of this length.
+const maxRecurringSilenceOccurrence = 31 * 24 * time.Hour
+
+// recurringSilenceUID returns the UID of the recurring silence the silence was created for, which is the value of
+// its reserved matcher. The matcher is part of the silence, so the link survives restarts and changes of the comment,
+// and is gossiped to the other members of the cluster together with the silence. It returns false if the silence
+// was not created for a recurring silence.
+func recurringSilenceUID(s *alerting.GettableSilence) (string, bool) {
+	for _, m := range s.Matchers {
+		if m.Name != nil && *m.Name == ngmodels.RecurringSilenceUIDLabel && m.Value != nil {
+			return *m.Value, true
+		}
+	}
+	return "", false
+}
+
+// SyncRecurringSilences makes sure that there is a silence for every recurring silence with an occurrence
+// that is active at the given time. Silences of occurrences that ended and of recurring silences that no
+// longer exist are expired.
+func (am *Alertmanager) SyncRecurringSilences(silences []apimodels.RecurringSilence, now time.Time) error {
+	existing, err := am.ListSilences(nil)
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+
+	current := make(map[string]*alerting.GettableSilence)
+	for _, s := range existing {
+		if s.Status == nil || s.Status.State == nil || *s.Status.State == amv2.SilenceStatusStateExpired {
+			continue
+		}
+		uid, ok := recurringSilenceUID(s)
+		if !ok {
+			continue
+		}
+		if _, ok := current[uid]; ok {
+			am.expireRecurringSilence(*s.ID, uid)
+			continue
+		}
+		current[uid] = s
+	}
+
+	for _, rs := range silences {
+		existing, ok := current[rs.UID]
+		delete(current, rs.UID)
+
+		end, active, err := activeOccurrence(rs, now)
+		if err != nil {
+			am.logger.Error("failed to determine the occurrence of a recurring silence", "uid", rs.UID, "error", err)
+			continue
+		}
+		if !active {
+			if ok {
+				am.expireRecurringSilence(*existing.ID, rs.UID)
+			}
+			continue
+		}
+
+		silence := newRecurringSilenceOccurrence(rs, now, end)
+		if ok {
+			if isSameOccurrence(existing, silence) {
+				continue
+			}
+			// Silences that only change their end are updated in place, otherwise the Alertmanager
+			// expires the existing silence and creates a new one.
+			silence.ID = *existing.ID
+			startsAt := *existing.StartsAt
+			silence.StartsAt = &startsAt
+		}
+		id, err := am.CreateSilence(silence)
+		if err != nil {
+			am.logger.Error("failed to create silence for recurring silence", "uid", rs.UID, "error", err)
+			continue
+		}
+		am.logger.Debug("silence for recurring silence created", "uid", rs.UID, "silence_id", id, "ends_at", end)
+	}
+
+	// The remaining silences belong to recurring silences that were deleted.
+	for uid, s := range current {
+		am.expireRecurringSilence(*s.ID, uid)
+	}
+	return nil
+}
+
+func (am *Alertmanager) expireRecurringSilence(id, uid string) {
+	if err := am.DeleteSilence(id); err != nil {
+		am.logger.Error("failed to expire silence of recurring silence", "uid", uid, "silence_id", id, "error", err)
+	}
+}
+
+func newRecurringSilenceOccurrence(rs apimodels.RecurringSilence, start, end time.Time) *alerting.PostableSilence {
+	comment := rs.Comment
+	createdBy := rs.CreatedBy
+	startsAt := strfmt.DateTime(start)
+	endsAt := strfmt.DateTime(end)
+	matchers := make(amv2.Matchers, 0, len(rs.Matchers)+1)
+	for _, m := range rs.Matchers {
+		name, value := m.Name, m.Value
+		isEqual := m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp
+		isRegex := m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    &name,
+			Value:   &value,
+			IsEqual: &isEqual,
+			IsRegex: &isRegex,
+		})
+	}
+	// The reserved matcher is a not-equal matcher on a label that alerts do not have, so it does not change
+	// which alerts are silenced.
+	name, uid, isEqual, isRegex := ngmodels.RecurringSilenceUIDLabel, rs.UID, false, false
+	matchers = append(matchers, &amv2.Matcher{
+		Name:    &name,
+		Value:   &uid,
+		IsEqual: &isEqual,
+		IsRegex: &isRegex,
+	})
+	return &alerting.PostableSilence{
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			Matchers:  matchers,
+			StartsAt:  &startsAt,
+			EndsAt:    &endsAt,
+		},
+	}
+}
+
+func isSameOccurrence(existing *alerting.GettableSilence, s *alerting.PostableSilence) bool {
+	if *existing.Comment != *s.Comment || *existing.CreatedBy != *s.CreatedBy {
+		return false
+	}
+	if !time.Time(*existing.EndsAt).Equal(time.Time(*s.EndsAt)) {
+		return false
+	}
+	if len(existing.Matchers) != len(s.Matchers) {
+		return false
+	}
+	for i, m := range existing.Matchers {
+		other := s.Matchers[i]
+		if *m.Name != *other.Name || *m.Value != *other.Value || *m.IsRegex != *other.IsRegex {
+			return false
+		}
+		if (m.IsEqual == nil || *m.IsEqual) != *other.IsEqual {
+			return false
+		}
+	}
+	return true
+}
+
+// activeOccurrence returns the end of the occurrence of the recurring silence that is active at the given time.
+// If no occurrence is active it returns false.
+func activeOccurrence(s apimodels.RecurringSilence, now time.Time) (time.Time, bool, error) {
+	if s.Cron != "" {
+		schedule, err := cron.ParseStandard(s.Cron)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		duration := time.Duration(s.Duration)
+		// The latest start before now wins if occurrences overlap. Expressions without CRON_TZ are
+		// evaluated in the location of the time that is passed to the schedule.
+		var last time.Time
+		for next := schedule.Next(now.UTC().Add(-duration)); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+			last = next
+		}
+		if last.IsZero() {
+			return time.Time{}, false, nil
+		}
+		return last.Add(duration), true, nil
+	}
+
+	contains := func(t time.Time) bool {
+		for _, interval := range s.TimeIntervals {
+			if interval.ContainsTime(t) {
+				return true
+			}
+		}
+		return false
+	}
+	if !contains(now) {
+		return time.Time{}, false, nil
+	}
+	// Whether a time interval contains a time can only change at the end of one of its time ranges or at midnight,
+	// so the end of the occurrence is the first of these boundaries that none of the time intervals contain.
+	limit := now.Add(maxRecurringSilenceOccurrence)
+	for t := now; t.Before(limit); {
+		next := limit
+		for _, interval := range s.TimeIntervals {
+			if b := nextIntervalBoundary(interval, t); b.Before(next) {
+				next = b
+			}
+		}
+		// Time intervals without a location are evaluated in the location of the given time.
+		t = next.In(now.Location())
+		if !contains(t) {
+			return t, true, nil
+		}
+	}
+	return limit, true, nil
+}
+
+// nextIntervalBoundary returns the first time after t at which the time interval can stop containing a time,
+// that is the next end of one of its time ranges or the next midnight in the location of the time interval.
+func nextIntervalBoundary(interval timeinterval.TimeInterval, t time.Time) time.Time {
+	if interval.Location != nil {
+		t = t.In(interval.Location.Location)
+	}
+	year, month, day := t.Date()
+	next := time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+	for _, r := range interval.Times {
+		end := time.Date(year, month, day, 0, r.EndMinute, 0, 0, t.Location())
+		if end.After(t) && end.Before(next) {
+			next = end
+		}
+	}
+	return next
+}
@@ -0,0 +1,288 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestActiveOccurrence(t *testing.T) {
+	// 2023-01-28 is a Saturday.
+	saturday := time.Date(2023, 1, 28, 0, 0, 0, 0, time.UTC)
+	cronSilence := apimodels.RecurringSilence{
+		Cron:     "0 22 * * SAT",
+		Duration: model.Duration(4 * time.Hour),
+	}
+	weekend := apimodels.RecurringSilence{
+		TimeIntervals: []timeinterval.TimeInterval{
+			{
+				Weekdays: []timeinterval.WeekdayRange{
+					{InclusiveRange: timeinterval.InclusiveRange{Begin: 6, End: 6}},
+					{InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}},
+				},
+			},
+		},
+	}
+
+	// From 22:00 to 06:00 on every day.
+	nights := apimodels.RecurringSilence{
+		TimeIntervals: []timeinterval.TimeInterval{
+			{
+				Times: []timeinterval.TimeRange{
+					{StartMinute: 0, EndMinute: 6 * 60},
+					{StartMinute: 22 * 60, EndMinute: 24 * 60},
+				},
+			},
+		},
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc      string
+		silence   apimodels.RecurringSilence
+		now       time.Time
+		expActive bool
+		expEnd    time.Time
+	}{
+		{
+			desc:    "cron before the occurrence",
+			silence: cronSilence,
+			now:     saturday.Add(21 * time.Hour),
+		},
+		{
+			desc:      "cron at the start of the occurrence",
+			silence:   cronSilence,
+			now:       saturday.Add(22 * time.Hour),
+			expActive: true,
+			expEnd:    saturday.Add(26 * time.Hour),
+		},
+		{
+			desc:      "cron during the occurrence",
+			silence:   cronSilence,
+			now:       saturday.Add(25 * time.Hour),
+			expActive: true,
+			expEnd:    saturday.Add(26 * time.Hour),
+		},
+		{
+			desc:    "cron at the end of the occurrence",
+			silence: cronSilence,
+			now:     saturday.Add(26 * time.Hour),
+		},
+		{
+			desc: "cron with location",
+			silence: apimodels.RecurringSilence{
+				Cron:     "CRON_TZ=Europe/Berlin 0 22 * * SAT",
+				Duration: model.Duration(4 * time.Hour),
+			},
+			now:       saturday.Add(21*time.Hour + 30*time.Minute),
+			expActive: true,
+			expEnd:    saturday.Add(25 * time.Hour),
+		},
+		{
+			desc: "overlapping cron occurrences",
+			silence: apimodels.RecurringSilence{
+				Cron:     "0 * * * *",
+				Duration: model.Duration(90 * time.Minute),
+			},
+			now:       saturday.Add(10*time.Hour + 15*time.Minute),
+			expActive: true,
+			expEnd:    saturday.Add(11*time.Hour + 30*time.Minute),
+		},
+		{
+			desc:    "time intervals not active",
+			silence: weekend,
+			now:     saturday.Add(-time.Minute),
+		},
+		{
+			desc:      "time intervals active",
+			silence:   weekend,
+			now:       saturday.Add(10*time.Hour + 30*time.Second),
+			expActive: true,
+			expEnd:    saturday.Add(48 * time.Hour),
+		},
+		{
+			desc:      "time intervals with times",
+			silence:   nights,
+			now:       saturday.Add(23 * time.Hour),
+			expActive: true,
+			expEnd:    saturday.Add(30 * time.Hour),
+		},
+		{
+			desc:    "time intervals with times not active",
+			silence: nights,
+			now:     saturday.Add(12 * time.Hour),
+		},
+		{
+			desc: "time intervals with location",
+			silence: apimodels.RecurringSilence{
+				TimeIntervals: []timeinterval.TimeInterval{
+					{
+						Times:    []timeinterval.TimeRange{{StartMinute: 9 * 60, EndMinute: 17 * 60}},
+						Location: &timeinterval.Location{Location: berlin},
+					},
+				},
+			},
+			now:       saturday.Add(12 * time.Hour),
+			expActive: true,
+			expEnd:    saturday.Add(16 * time.Hour),
+		},
+		{
+			desc: "overlapping time intervals",
+			silence: apimodels.RecurringSilence{
+				TimeIntervals: []timeinterval.TimeInterval{
+					{Times: []timeinterval.TimeRange{{StartMinute: 8 * 60, EndMinute: 12 * 60}}},
+					{Times: []timeinterval.TimeRange{{StartMinute: 11 * 60, EndMinute: 14*60 + 30}}},
+				},
+			},
+			now:       saturday.Add(9 * time.Hour),
+			expActive: true,
+			expEnd:    saturday.Add(14*time.Hour + 30*time.Minute),
+		},
+		{
+			desc: "time intervals longer than the longest occurrence",
+			silence: apimodels.RecurringSilence{
+				TimeIntervals: []timeinterval.TimeInterval{
+					{Years: []timeinterval.YearRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 2023, End: 2023}}}},
+				},
+			},
+			now:       saturday,
+			expActive: true,
+			expEnd:    saturday.Add(maxRecurringSilenceOccurrence),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			end, active, err := activeOccurrence(tc.silence, tc.now)
+			require.NoError(t, err)
+			require.Equal(t, tc.expActive, active)
+			if tc.expActive {
+				require.True(t, tc.expEnd.Equal(end), "expected end %s, got %s", tc.expEnd, end)
+			}
+		})
+	}
+}
+
+func TestSyncRecurringSilences(t *testing.T) {
+	am := setupAMTest(t)
+
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "cluster", "eu-1")
+	require.NoError(t, err)
+	rs := apimodels.RecurringSilence{
+		UID:       "maintenance",
+		Comment:   "maintenance",
+		CreatedBy: "ops",
+		Matchers:  apimodels.ObjectMatchers{matcher},
+		Cron:      "* * * * *",
+		Duration:  model.Duration(time.Hour),
+	}
+	// The Alertmanager uses the wall clock to determine the state of silences.
+	now := time.Now()
+	start := now.Truncate(time.Minute)
+
+	activeSilences := func(t *testing.T) []*amv2.GettableSilence {
+		t.Helper()
+		silences, err := am.ListSilences(nil)
+		require.NoError(t, err)
+		var result []*amv2.GettableSilence
+		for _, s := range silences {
+			if *s.Status.State != amv2.SilenceStatusStateExpired {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+
+	t.Run("creates a silence for an active occurrence", func(t *testing.T) {
+		require.NoError(t, am.SyncRecurringSilences([]apimodels.RecurringSilence{rs}, now))
+
+		silences := activeSilences(t)
+		require.Len(t, silences, 1)
+		require.Equal(t, "maintenance", *silences[0].Comment)
+		require.Equal(t, "ops", *silences[0].CreatedBy)
+		require.Len(t, silences[0].Matchers, 2)
+		require.Equal(t, "cluster", *silences[0].Matchers[0].Name)
+		require.Equal(t, models.RecurringSilenceUIDLabel, *silences[0].Matchers[1].Name)
+		require.Equal(t, "maintenance", *silences[0].Matchers[1].Value)
+		require.False(t, *silences[0].Matchers[1].IsEqual)
+		require.True(t, start.Add(time.Hour).Equal(time.Time(*silences[0].EndsAt)))
+	})
+
+	t.Run("keeps the silence of the same occurrence", func(t *testing.T) {
+		before := activeSilences(t)
+		require.NoError(t, am.SyncRecurringSilences([]apimodels.RecurringSilence{rs}, now))
+
+		silences := activeSilences(t)
+		require.Len(t, silences, 1)
+		require.Equal(t, *before[0].ID, *silences[0].ID)
+	})
+
+	t.Run("keeps the silence when its comment is edited", func(t *testing.T) {
+		before := activeSilences(t)
+		edited := &amv2.PostableSilence{ID: *before[0].ID, Silence: before[0].Silence}
+		comment := "edited"
+		edited.Comment = &comment
+		_, err := am.CreateSilence(edited)
+		require.NoError(t, err)
+
+		require.NoError(t, am.SyncRecurringSilences([]apimodels.RecurringSilence{rs}, now))
+
+		silences := activeSilences(t)
+		require.Len(t, silences, 1)
+		require.Equal(t, "maintenance", *silences[0].Comment)
+	})
+
+	t.Run("updates the silence when the recurring silence changes", func(t *testing.T) {
+		before := activeSilences(t)
+		changed := rs
+		changed.Duration = model.Duration(2 * time.Hour)
+		require.NoError(t, am.SyncRecurringSilences([]apimodels.RecurringSilence{changed}, now))
+
+		silences := activeSilences(t)
+		require.Len(t, silences, 1)
+		require.Equal(t, *before[0].ID, *silences[0].ID)
+		require.True(t, start.Add(2*time.Hour).Equal(time.Time(*silences[0].EndsAt)))
+	})
+
+	t.Run("expires the silence when the occurrence ends", func(t *testing.T) {
+		changed := rs
+		changed.Cron = "0 0 1 1 *"
+		require.NoError(t, am.SyncRecurringSilences([]apimodels.RecurringSilence{changed}, now))
+		require.Empty(t, activeSilences(t))
+	})
+
+	t.Run("expires the silence when the recurring silence is deleted", func(t *testing.T) {
+		require.NoError(t, am.SyncRecurringSilences([]apimodels.RecurringSilence{rs}, now))
+		require.Len(t, activeSilences(t), 1)
+
+		require.NoError(t, am.SyncRecurringSilences(nil, now))
+		require.Empty(t, activeSilences(t))
+	})
+
+	t.Run("does not touch other silences", func(t *testing.T) {
+		comment, createdBy := "manual", "ops"
+		startsAt, endsAt := strfmt.DateTime(now), strfmt.DateTime(now.Add(time.Hour))
+		name, value, isRegex := "cluster", "us-1", false
+		_, err := am.CreateSilence(&amv2.PostableSilence{Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			StartsAt:  &startsAt,
+			EndsAt:    &endsAt,
+			Matchers:  amv2.Matchers{{Name: &name, Value: &value, IsRegex: &isRegex}},
+		}})
+		require.NoError(t, err)
+
+		require.NoError(t, am.SyncRecurringSilences(nil, now))
+		require.Len(t, activeSilences(t), 1)
+	})
+}
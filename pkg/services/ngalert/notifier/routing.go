@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// TestRouting routes an alert with the given labels through the notification policies of the configuration
+// and returns the policies it is sent to. The mute timings of the matched policies are checked against now.
+func TestRouting(cfg apimodels.Config, labels map[string]string, now time.Time) apimodels.RoutingTestResult {
+	result := apimodels.RoutingTestResult{Matches: []apimodels.RoutingTestMatch{}}
+	if cfg.Route == nil {
+		return result
+	}
+
+	lset := make(model.LabelSet, len(labels))
+	for k, v := range labels {
+		lset[model.LabelName(k)] = model.LabelValue(v)
+	}
+
+	muteTimings := make(map[string]bool, len(cfg.MuteTimeIntervals))
+	for _, mt := range cfg.MuteTimeIntervals {
+		for _, interval := range mt.TimeIntervals {
+			if interval.ContainsTime(now.UTC()) {
+				muteTimings[mt.Name] = true
+				break
+			}
+		}
+	}
+
+	root := dispatch.NewRoute(cfg.Route.AsAMRoute(), nil)
+	for _, path := range matchRoutePaths(root, lset, nil) {
+		result.Matches = append(result.Matches, newRoutingTestMatch(path, muteTimings))
+	}
+	return result
+}
+
+// matchRoutePaths does the same depth-first search as dispatch.Route.Match but returns the path from the root
+// to every matching route instead of the matching routes only.
+func matchRoutePaths(r *dispatch.Route, lset model.LabelSet, parents []*dispatch.Route) [][]*dispatch.Route {
+	if !r.Matchers.Matches(lset) {
+		return nil
+	}
+	path := make([]*dispatch.Route, 0, len(parents)+1)
+	path = append(path, parents...)
+	path = append(path, r)
+
+	var all [][]*dispatch.Route
+	for _, cr := range r.Routes {
+		matches := matchRoutePaths(cr, lset, path)
+		all = append(all, matches...)
+		if matches != nil && !cr.Continue {
+			break
+		}
+	}
+	// If no child routes match, the route itself is a match.
+	if len(all) == 0 {
+		all = append(all, path)
+	}
+	return all
+}
+
+func newRoutingTestMatch(path []*dispatch.Route, activeMuteTimings map[string]bool) apimodels.RoutingTestMatch {
+	route := path[len(path)-1]
+	opts := route.RouteOpts
+
+	groupBy := make([]string, 0, len(opts.GroupBy))
+	if opts.GroupByAll {
+		// The same special value that is used in the configuration to group by all labels.
+		groupBy = append(groupBy, "...")
+	} else {
+		for name := range opts.GroupBy {
+			groupBy = append(groupBy, string(name))
+		}
+		sort.Strings(groupBy)
+	}
+
+	match := apimodels.RoutingTestMatch{
+		Path:                    make([]apimodels.RoutingTestPolicy, 0, len(path)),
+		Receiver:                opts.Receiver,
+		GroupBy:                 groupBy,
+		GroupWait:               model.Duration(opts.GroupWait),
+		GroupInterval:           model.Duration(opts.GroupInterval),
+		RepeatInterval:          model.Duration(opts.RepeatInterval),
+		MuteTimeIntervals:       []string{},
+		ActiveMuteTimeIntervals: []string{},
+	}
+	for _, r := range path {
+		matchers := make([]string, 0, len(r.Matchers))
+		for _, m := range r.Matchers {
+			matchers = append(matchers, m.String())
+		}
+		match.Path = append(match.Path, apimodels.RoutingTestPolicy{
+			Receiver: r.RouteOpts.Receiver,
+			Matchers: matchers,
+			Continue: r.Continue,
+		})
+	}
+	for _, name := range opts.MuteTimeIntervals {
+		match.MuteTimeIntervals = append(match.MuteTimeIntervals, name)
+		if activeMuteTimings[name] {
+			match.ActiveMuteTimeIntervals = append(match.ActiveMuteTimeIntervals, name)
+		}
+	}
+	return match
+}
@@ -0,0 +1,78 @@
+package notifier
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestTestRouting(t *testing.T) {
+	cfg := apimodels.PostableApiAlertingConfig{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"route": {
+			"receiver": "default",
+			"group_by": ["alertname"],
+			"routes": [
+				{
+					"receiver": "team-a",
+					"object_matchers": [["team", "=", "a"]],
+					"group_wait": "1m",
+					"continue": true,
+					"routes": [
+						{"receiver": "team-a-critical", "object_matchers": [["severity", "=", "critical"]], "mute_time_intervals": ["weekends", "nights"]}
+					]
+				},
+				{"receiver": "all", "group_by": ["..."], "object_matchers": [["team", "=~", "a|b"]]}
+			]
+		},
+		"mute_time_intervals": [
+			{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]},
+			{"name": "nights", "time_intervals": [{"times": [{"start_time": "00:00", "end_time": "06:00"}]}]}
+		],
+		"receivers": [{"name": "default"}, {"name": "team-a"}, {"name": "team-a-critical"}, {"name": "all"}]
+	}`), &cfg))
+	// 2023-01-28 is a Saturday.
+	now := time.Date(2023, 1, 28, 12, 0, 0, 0, time.UTC)
+
+	t.Run("unmatched labels are routed to the root", func(t *testing.T) {
+		result := TestRouting(cfg.Config, map[string]string{"team": "c"}, now)
+		require.Len(t, result.Matches, 1)
+		match := result.Matches[0]
+		require.Equal(t, "default", match.Receiver)
+		require.Equal(t, []string{"alertname"}, match.GroupBy)
+		require.Equal(t, model.Duration(30*time.Second), match.GroupWait)
+		require.Len(t, match.Path, 1)
+	})
+
+	t.Run("returns the path with inherited settings and active mute timings", func(t *testing.T) {
+		result := TestRouting(cfg.Config, map[string]string{"team": "a", "severity": "critical"}, now)
+		require.Len(t, result.Matches, 2)
+
+		match := result.Matches[0]
+		require.Equal(t, "team-a-critical", match.Receiver)
+		require.Equal(t, []string{"alertname"}, match.GroupBy)
+		require.Equal(t, model.Duration(time.Minute), match.GroupWait)
+		require.Equal(t, []string{"weekends", "nights"}, match.MuteTimeIntervals)
+		require.Equal(t, []string{"weekends"}, match.ActiveMuteTimeIntervals)
+		require.Equal(t, []apimodels.RoutingTestPolicy{
+			{Receiver: "default", Matchers: []string{}},
+			{Receiver: "team-a", Matchers: []string{`team="a"`}, Continue: true},
+			{Receiver: "team-a-critical", Matchers: []string{`severity="critical"`}},
+		}, match.Path)
+
+		// The sibling is matched as well because the first policy continues.
+		require.Equal(t, "all", result.Matches[1].Receiver)
+		require.Equal(t, []string{"..."}, result.Matches[1].GroupBy)
+		require.Empty(t, result.Matches[1].MuteTimeIntervals)
+	})
+
+	t.Run("returns no matches without a route", func(t *testing.T) {
+		result := TestRouting(apimodels.Config{}, map[string]string{"team": "a"}, now)
+		require.Empty(t, result.Matches)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -15,7 +16,8 @@ import (
 )
 
 type FakeConfigStore struct {
-	configs map[int64]*models.AlertConfiguration
+	configs           map[int64]*models.AlertConfiguration
+	recurringSilences []models.RecurringSilence
 }
 
 // Saves the image or returns an error.
@@ -31,6 +33,90 @@ func (f *FakeConfigStore) GetImages(ctx context.Context, tokens []string) ([]mod
 	return nil, nil, models.ErrImageNotFound
 }
 
+func (f *FakeConfigStore) SaveNotificationHistory(ctx context.Context, entry *models.NotificationHistoryEntry) error {
+	return nil
+}
+
+func (f *FakeConfigStore) QueryNotificationHistory(ctx context.Context, query models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	return nil, nil
+}
+
+func (f *FakeConfigStore) DeleteNotificationHistoryOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *FakeConfigStore) GetNotifierState(ctx context.Context, orgID int64, kind string) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *FakeConfigStore) AppendNotifierState(ctx context.Context, orgID int64, kind string, data []byte) error {
+	return nil
+}
+
+func (f *FakeConfigStore) GetLastNotifierStateID(ctx context.Context, orgID int64, kind string) (int64, error) {
+	return 0, nil
+}
+
+func (f *FakeConfigStore) CompactNotifierState(ctx context.Context, orgID int64, kind string, lastID int64, snapshot []byte) error {
+	return nil
+}
+
+func (f *FakeConfigStore) GetNotifierStateOrgIDs(ctx context.Context) ([]int64, error) {
+	return nil, nil
+}
+
+func (f *FakeConfigStore) DeleteNotifierState(ctx context.Context, orgID int64) error {
+	return nil
+}
+
+func (f *FakeConfigStore) GetRecurringSilences(ctx context.Context, orgID int64) ([]models.RecurringSilence, error) {
+	var result []models.RecurringSilence
+	for _, s := range f.recurringSilences {
+		if s.OrgID == orgID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeConfigStore) GetAllRecurringSilences(ctx context.Context) ([]models.RecurringSilence, error) {
+	return f.recurringSilences, nil
+}
+
+func (f *FakeConfigStore) GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	for _, s := range f.recurringSilences {
+		if s.OrgID == orgID && s.UID == uid {
+			return &s, nil
+		}
+	}
+	return nil, models.ErrRecurringSilenceNotFound
+}
+
+func (f *FakeConfigStore) InsertRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error {
+	f.recurringSilences = append(f.recurringSilences, *silence)
+	return nil
+}
+
+func (f *FakeConfigStore) UpdateRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error {
+	for i, s := range f.recurringSilences {
+		if s.OrgID == silence.OrgID && s.UID == silence.UID {
+			f.recurringSilences[i] = *silence
+			return nil
+		}
+	}
+	return models.ErrRecurringSilenceNotFound
+}
+
+func (f *FakeConfigStore) DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error {
+	for i, s := range f.recurringSilences {
+		if s.OrgID == orgID && s.UID == uid {
+			f.recurringSilences = append(f.recurringSilences[:i], f.recurringSilences[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func NewFakeConfigStore(t *testing.T, configs map[int64]*models.AlertConfiguration) FakeConfigStore {
 	t.Helper()
 
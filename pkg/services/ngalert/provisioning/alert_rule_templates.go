@@ -0,0 +1,331 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// AlertRuleTemplateService manages alert rule templates and the alert rules created from them. Every update of a
+// template increments its version and is rolled out to the alert rules created from it.
+type AlertRuleTemplateService struct {
+	store AlertRuleTemplateStore
+	rules *AlertRuleService
+	prov  ProvisioningStore
+	xact  TransactionManager
+	log   log.Logger
+}
+
+func NewAlertRuleTemplateService(store AlertRuleTemplateStore, rules *AlertRuleService, prov ProvisioningStore, xact TransactionManager, log log.Logger) *AlertRuleTemplateService {
+	return &AlertRuleTemplateService{
+		store: store,
+		rules: rules,
+		prov:  prov,
+		xact:  xact,
+		log:   log,
+	}
+}
+
+// GetAlertRuleTemplates returns a slice of all alert rule templates within the specified org.
+func (svc *AlertRuleTemplateService) GetAlertRuleTemplates(ctx context.Context, orgID int64) ([]definitions.AlertRuleTemplate, error) {
+	templates, err := svc.store.GetAlertRuleTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	provenances, err := svc.prov.GetProvenances(ctx, orgID, (&definitions.AlertRuleTemplate{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]definitions.AlertRuleTemplate, 0, len(templates))
+	for _, template := range templates {
+		t, err := definitions.NewAlertRuleTemplate(template, provenances[template.UID])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// GetAlertRuleTemplate returns the alert rule template with the UID. It returns ErrNotFound if it does not exist.
+func (svc *AlertRuleTemplateService) GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (definitions.AlertRuleTemplate, error) {
+	template, err := svc.getTemplate(ctx, orgID, uid)
+	if err != nil {
+		return definitions.AlertRuleTemplate{}, err
+	}
+	template.Provenance, err = svc.prov.GetProvenance(ctx, &template, orgID)
+	if err != nil {
+		return definitions.AlertRuleTemplate{}, err
+	}
+	return template, nil
+}
+
+// CreateAlertRuleTemplate adds a new alert rule template within the specified org. The created alert rule template is returned.
+func (svc *AlertRuleTemplateService) CreateAlertRuleTemplate(ctx context.Context, template definitions.AlertRuleTemplate, orgID int64) (definitions.AlertRuleTemplate, error) {
+	if err := template.Validate(); err != nil {
+		return definitions.AlertRuleTemplate{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if template.UID == "" {
+		template.UID = util.GenerateShortUID()
+	} else if !util.IsValidShortUID(template.UID) || util.IsShortUIDTooLong(template.UID) {
+		return definitions.AlertRuleTemplate{}, fmt.Errorf("%w: %s", ErrValidation, "UID must be a valid short UID")
+	}
+	template.Version = 1
+	stored, err := template.UpstreamModel(orgID)
+	if err != nil {
+		return definitions.AlertRuleTemplate{}, err
+	}
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		_, err := svc.store.GetAlertRuleTemplate(ctx, orgID, template.UID)
+		if err == nil {
+			return fmt.Errorf("%w: %s", ErrValidation, "an alert rule template with this UID already exists")
+		}
+		if !errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+			return err
+		}
+		if err := svc.store.InsertAlertRuleTemplate(ctx, &stored); err != nil {
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, &template, orgID, template.Provenance)
+	})
+	if err != nil {
+		return definitions.AlertRuleTemplate{}, err
+	}
+	template.Updated = stored.Updated
+	return template, nil
+}
+
+// UpdateAlertRuleTemplate replaces an existing alert rule template within the specified org and rolls out the new
+// version to the alert rules created from it. Alert rules that cannot be updated are logged and updated by the next
+// rollout. The replaced alert rule template is returned. If the alert rule template does not exist, nil is returned
+// and no action is taken.
+func (svc *AlertRuleTemplateService) UpdateAlertRuleTemplate(ctx context.Context, template definitions.AlertRuleTemplate, orgID int64) (*definitions.AlertRuleTemplate, error) {
+	if err := template.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	var stored models.AlertRuleTemplate
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		existing, err := svc.store.GetAlertRuleTemplate(ctx, orgID, template.UID)
+		if err != nil {
+			return err
+		}
+		// Reject templates that cannot be rendered with the values of the existing alert rules, for example
+		// because a required parameter was added, so that a rollout cannot fail for all of them.
+		instances, err := svc.store.GetAlertRuleTemplateInstances(ctx, orgID, template.UID)
+		if err != nil {
+			return err
+		}
+		for _, instance := range instances {
+			i, err := definitions.NewAlertRuleTemplateInstance(instance)
+			if err != nil {
+				return err
+			}
+			if _, err := template.Render(i.Values); err != nil {
+				return fmt.Errorf("%w: alert rule %s: %s", ErrValidation, instance.RuleUID, err.Error())
+			}
+		}
+
+		template.Version = existing.Version + 1
+		stored, err = template.UpstreamModel(orgID)
+		if err != nil {
+			return err
+		}
+		if err := svc.store.UpdateAlertRuleTemplate(ctx, &stored); err != nil {
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, &template, orgID, template.Provenance)
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	template.Updated = stored.Updated
+
+	rollout, err := svc.RolloutAlertRuleTemplate(ctx, orgID, template.UID)
+	if err != nil {
+		svc.log.Error("Failed to roll out alert rule template", "org_id", orgID, "uid", template.UID, "version", template.Version, "error", err)
+	} else if len(rollout.Failed) > 0 {
+		svc.log.Warn("Failed to update some alert rules created from alert rule template", "org_id", orgID, "uid", template.UID, "version", template.Version, "updated", len(rollout.Updated), "failed", len(rollout.Failed))
+	}
+	return &template, nil
+}
+
+// DeleteAlertRuleTemplate deletes the alert rule template with the given UID in the given org. The alert rules
+// created from it are kept but no longer updated. If the alert rule template does not exist, no error is returned.
+func (svc *AlertRuleTemplateService) DeleteAlertRuleTemplate(ctx context.Context, uid string, orgID int64) error {
+	target := definitions.AlertRuleTemplate{UID: uid}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteAlertRuleTemplate(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.prov.DeleteProvenance(ctx, &target, orgID)
+	})
+}
+
+// GetAlertRuleTemplateInstances returns the alert rules created from the alert rule template with the UID. It
+// returns ErrNotFound if the template does not exist.
+func (svc *AlertRuleTemplateService) GetAlertRuleTemplateInstances(ctx context.Context, orgID int64, uid string) ([]definitions.AlertRuleTemplateInstance, error) {
+	if _, err := svc.getTemplate(ctx, orgID, uid); err != nil {
+		return nil, err
+	}
+	instances, err := svc.store.GetAlertRuleTemplateInstances(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.AlertRuleTemplateInstance, 0, len(instances))
+	for _, instance := range instances {
+		i, err := definitions.NewAlertRuleTemplateInstance(instance)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, i)
+	}
+	return result, nil
+}
+
+// CreateAlertRuleFromTemplate creates a new alert rule from the alert rule template with the UID and links it to
+// the template. It returns ErrNotFound if the template does not exist and ErrValidation if the template cannot be
+// rendered with the values.
+func (svc *AlertRuleTemplateService) CreateAlertRuleFromTemplate(ctx context.Context, orgID int64, uid string, instance definitions.AlertRuleTemplateInstance, provenance models.Provenance, userID int64) (models.AlertRule, error) {
+	template, err := svc.getTemplate(ctx, orgID, uid)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	if instance.Values == nil {
+		instance.Values = map[string]string{}
+	}
+	rule, err := svc.renderRule(template, orgID, instance)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	values, err := json.Marshal(instance.Values)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("failed to encode values: %w", err)
+	}
+
+	var created models.AlertRule
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		created, err = svc.rules.CreateAlertRule(ctx, rule, provenance, userID)
+		if err != nil {
+			return err
+		}
+		return svc.store.InsertAlertRuleTemplateInstance(ctx, &models.AlertRuleTemplateInstance{
+			OrgID:           orgID,
+			TemplateUID:     template.UID,
+			RuleUID:         created.UID,
+			TemplateVersion: template.Version,
+			Values:          string(values),
+		})
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return created, nil
+}
+
+// RolloutAlertRuleTemplate updates the alert rules created from the alert rule template with the UID that are not
+// at its current version. Links to alert rules that were deleted are removed. It returns ErrNotFound if the template
+// does not exist.
+func (svc *AlertRuleTemplateService) RolloutAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (definitions.AlertRuleTemplateRollout, error) {
+	template, err := svc.getTemplate(ctx, orgID, uid)
+	if err != nil {
+		return definitions.AlertRuleTemplateRollout{}, err
+	}
+	instances, err := svc.store.GetAlertRuleTemplateInstances(ctx, orgID, uid)
+	if err != nil {
+		return definitions.AlertRuleTemplateRollout{}, err
+	}
+
+	result := definitions.AlertRuleTemplateRollout{
+		Version: template.Version,
+		Updated: []string{},
+		Failed:  []definitions.AlertRuleTemplateRolloutFailure{},
+	}
+	for _, instance := range instances {
+		if instance.TemplateVersion >= template.Version {
+			continue
+		}
+		updated, err := svc.updateInstance(ctx, template, instance)
+		if err != nil {
+			result.Failed = append(result.Failed, definitions.AlertRuleTemplateRolloutFailure{
+				RuleUID: instance.RuleUID,
+				Error:   err.Error(),
+			})
+			continue
+		}
+		if updated {
+			result.Updated = append(result.Updated, instance.RuleUID)
+		}
+	}
+	return result, nil
+}
+
+// updateInstance renders the alert rule of the instance from the template and updates it with its current
+// provenance. It returns false if the alert rule was deleted, in which case the instance is deleted as well.
+func (svc *AlertRuleTemplateService) updateInstance(ctx context.Context, template definitions.AlertRuleTemplate, instance models.AlertRuleTemplateInstance) (bool, error) {
+	i, err := definitions.NewAlertRuleTemplateInstance(instance)
+	if err != nil {
+		return false, err
+	}
+	rule, err := svc.renderRule(template, instance.OrgID, i)
+	if err != nil {
+		return false, err
+	}
+
+	_, provenance, err := svc.rules.GetAlertRule(ctx, instance.OrgID, instance.RuleUID)
+	if errors.Is(err, models.ErrAlertRuleNotFound) {
+		return false, svc.store.DeleteAlertRuleTemplateInstance(ctx, instance.OrgID, instance.RuleUID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if _, err := svc.rules.UpdateAlertRule(ctx, rule, provenance); err != nil {
+			return err
+		}
+		instance.TemplateVersion = template.Version
+		return svc.store.UpdateAlertRuleTemplateInstance(ctx, &instance)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (svc *AlertRuleTemplateService) renderRule(template definitions.AlertRuleTemplate, orgID int64, instance definitions.AlertRuleTemplateInstance) (models.AlertRule, error) {
+	rendered, err := template.Render(instance.Values)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	rule, err := rendered.UpstreamModel()
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	rule.ID = 0
+	rule.OrgID = orgID
+	rule.UID = instance.RuleUID
+	return rule, nil
+}
+
+func (svc *AlertRuleTemplateService) getTemplate(ctx context.Context, orgID int64, uid string) (definitions.AlertRuleTemplate, error) {
+	template, err := svc.store.GetAlertRuleTemplate(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+			return definitions.AlertRuleTemplate{}, ErrNotFound
+		}
+		return definitions.AlertRuleTemplate{}, err
+	}
+	return definitions.NewAlertRuleTemplate(*template, models.ProvenanceNone)
+}
@@ -0,0 +1,252 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestAlertRuleTemplateService(t *testing.T) {
+	ctx := context.Background()
+	var orgID int64 = 1
+
+	t.Run("create generates a UID and sets the first version", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl := createAlertRuleTemplate()
+		tmpl.Provenance = models.ProvenanceAPI
+
+		created, err := sut.CreateAlertRuleTemplate(ctx, tmpl, orgID)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+		require.Equal(t, int64(1), created.Version)
+
+		stored, err := sut.GetAlertRuleTemplate(ctx, orgID, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, stored.Provenance)
+		require.Equal(t, created.Parameters, stored.Parameters)
+		require.JSONEq(t, string(created.Rule), string(stored.Rule))
+	})
+
+	t.Run("create rejects invalid templates", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl := createAlertRuleTemplate()
+		tmpl.Parameters = nil
+
+		_, err := sut.CreateAlertRuleTemplate(ctx, tmpl, orgID)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("alert rules created from a template are linked to it", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl, err := sut.CreateAlertRuleTemplate(ctx, createAlertRuleTemplate(), orgID)
+		require.NoError(t, err)
+
+		rule, err := sut.CreateAlertRuleFromTemplate(ctx, orgID, tmpl.UID, definitions.AlertRuleTemplateInstance{
+			Values: map[string]string{"threshold": "95"},
+		}, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, rule.UID)
+		require.Equal(t, "Disk above 95%", rule.Title)
+
+		instances, err := sut.GetAlertRuleTemplateInstances(ctx, orgID, tmpl.UID)
+		require.NoError(t, err)
+		require.Equal(t, []definitions.AlertRuleTemplateInstance{
+			{RuleUID: rule.UID, Values: map[string]string{"threshold": "95"}, TemplateVersion: 1},
+		}, instances)
+	})
+
+	t.Run("create from template fails for invalid values and unknown templates", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl, err := sut.CreateAlertRuleTemplate(ctx, createAlertRuleTemplate(), orgID)
+		require.NoError(t, err)
+
+		_, err = sut.CreateAlertRuleFromTemplate(ctx, orgID, tmpl.UID, definitions.AlertRuleTemplateInstance{}, models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, ErrValidation)
+
+		_, err = sut.CreateAlertRuleFromTemplate(ctx, orgID, "unknown", definitions.AlertRuleTemplateInstance{
+			Values: map[string]string{"threshold": "95"},
+		}, models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("update increments the version and rolls it out", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl, err := sut.CreateAlertRuleTemplate(ctx, createAlertRuleTemplate(), orgID)
+		require.NoError(t, err)
+		rule, err := sut.CreateAlertRuleFromTemplate(ctx, orgID, tmpl.UID, definitions.AlertRuleTemplateInstance{
+			Values: map[string]string{"threshold": "95"},
+		}, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		tmpl.Rule = json.RawMessage(`{
+			"folderUID": "my-namespace",
+			"ruleGroup": "disk",
+			"title": "Disk usage above ${threshold}%",
+			"condition": "A",
+			"data": [{"refId": "A", "datasourceUid": "-100", "model": {"threshold": "${threshold}"}, "relativeTimeRange": {"from": 600, "to": 0}}],
+			"noDataState": "OK",
+			"execErrState": "OK",
+			"for": "10m"
+		}`)
+		updated, err := sut.UpdateAlertRuleTemplate(ctx, tmpl, orgID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+
+		stored, provenance, err := sut.rules.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, "Disk usage above 95%", stored.Title)
+		require.Equal(t, 10*time.Minute, stored.For)
+		require.JSONEq(t, `{"threshold": 95, "intervalMs": 1000, "maxDataPoints": 43200}`, string(stored.Data[0].Model))
+		require.Equal(t, models.ProvenanceAPI, provenance)
+
+		instances, err := sut.GetAlertRuleTemplateInstances(ctx, orgID, tmpl.UID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), instances[0].TemplateVersion)
+	})
+
+	t.Run("update rejects templates that cannot be rendered for existing alert rules", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl, err := sut.CreateAlertRuleTemplate(ctx, createAlertRuleTemplate(), orgID)
+		require.NoError(t, err)
+		_, err = sut.CreateAlertRuleFromTemplate(ctx, orgID, tmpl.UID, definitions.AlertRuleTemplateInstance{
+			Values: map[string]string{"threshold": "95"},
+		}, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		tmpl.Parameters = append(tmpl.Parameters, definitions.AlertRuleTemplateParameter{Name: "team"})
+		_, err = sut.UpdateAlertRuleTemplate(ctx, tmpl, orgID)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("update returns nil for unknown templates", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl := createAlertRuleTemplate()
+		tmpl.UID = "unknown"
+
+		updated, err := sut.UpdateAlertRuleTemplate(ctx, tmpl, orgID)
+		require.NoError(t, err)
+		require.Nil(t, updated)
+	})
+
+	t.Run("rollout reports failed alert rules and retries them", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl, err := sut.CreateAlertRuleTemplate(ctx, createAlertRuleTemplate(), orgID)
+		require.NoError(t, err)
+		rule, err := sut.CreateAlertRuleFromTemplate(ctx, orgID, tmpl.UID, definitions.AlertRuleTemplateInstance{
+			Values: map[string]string{"threshold": "95"},
+		}, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		// Store a new version and a value that cannot be rendered, which the service would reject.
+		stored, err := sut.store.GetAlertRuleTemplate(ctx, orgID, tmpl.UID)
+		require.NoError(t, err)
+		stored.Version = 2
+		require.NoError(t, sut.store.UpdateAlertRuleTemplate(ctx, stored))
+		instance := &models.AlertRuleTemplateInstance{OrgID: orgID, TemplateUID: tmpl.UID, RuleUID: rule.UID, TemplateVersion: 1, Values: `{"threshold":"high"}`}
+		require.NoError(t, sut.store.UpdateAlertRuleTemplateInstance(ctx, instance))
+
+		rollout, err := sut.RolloutAlertRuleTemplate(ctx, orgID, tmpl.UID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), rollout.Version)
+		require.Empty(t, rollout.Updated)
+		require.Len(t, rollout.Failed, 1)
+		require.Equal(t, rule.UID, rollout.Failed[0].RuleUID)
+
+		instance.Values = `{"threshold":"90"}`
+		require.NoError(t, sut.store.UpdateAlertRuleTemplateInstance(ctx, instance))
+		rollout, err = sut.RolloutAlertRuleTemplate(ctx, orgID, tmpl.UID)
+		require.NoError(t, err)
+		require.Equal(t, []string{rule.UID}, rollout.Updated)
+		require.Empty(t, rollout.Failed)
+
+		// Alert rules at the current version are not updated again.
+		rollout, err = sut.RolloutAlertRuleTemplate(ctx, orgID, tmpl.UID)
+		require.NoError(t, err)
+		require.Empty(t, rollout.Updated)
+	})
+
+	t.Run("rollout unlinks deleted alert rules", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl, err := sut.CreateAlertRuleTemplate(ctx, createAlertRuleTemplate(), orgID)
+		require.NoError(t, err)
+		rule, err := sut.CreateAlertRuleFromTemplate(ctx, orgID, tmpl.UID, definitions.AlertRuleTemplateInstance{
+			Values: map[string]string{"threshold": "95"},
+		}, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		require.NoError(t, sut.rules.DeleteAlertRule(ctx, orgID, rule.UID, models.ProvenanceNone))
+
+		_, err = sut.UpdateAlertRuleTemplate(ctx, tmpl, orgID)
+		require.NoError(t, err)
+		instances, err := sut.GetAlertRuleTemplateInstances(ctx, orgID, tmpl.UID)
+		require.NoError(t, err)
+		require.Empty(t, instances)
+	})
+
+	t.Run("delete keeps the alert rules", func(t *testing.T) {
+		sut := createAlertRuleTemplateServiceSut(t)
+		tmpl, err := sut.CreateAlertRuleTemplate(ctx, createAlertRuleTemplate(), orgID)
+		require.NoError(t, err)
+		rule, err := sut.CreateAlertRuleFromTemplate(ctx, orgID, tmpl.UID, definitions.AlertRuleTemplateInstance{
+			Values: map[string]string{"threshold": "95"},
+		}, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.DeleteAlertRuleTemplate(ctx, tmpl.UID, orgID))
+		_, err = sut.GetAlertRuleTemplate(ctx, orgID, tmpl.UID)
+		require.ErrorIs(t, err, ErrNotFound)
+		_, _, err = sut.rules.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+	})
+}
+
+func createAlertRuleTemplateServiceSut(t *testing.T) *AlertRuleTemplateService {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	store := store.DBstore{
+		SQLStore: sqlStore,
+		Cfg: setting.UnifiedAlertingSettings{
+			BaseInterval: time.Second * 10,
+		},
+		Logger: log.NewNopLogger(),
+	}
+	quotas := MockQuotaChecker{}
+	quotas.EXPECT().LimitOK()
+	rules := &AlertRuleService{
+		ruleStore:              store,
+		provenanceStore:        store,
+		quotas:                 &quotas,
+		xact:                   sqlStore,
+		log:                    log.NewNopLogger(),
+		baseIntervalSeconds:    10,
+		defaultIntervalSeconds: 60,
+	}
+	return NewAlertRuleTemplateService(store, rules, store, sqlStore, log.NewNopLogger())
+}
+
+func createAlertRuleTemplate() definitions.AlertRuleTemplate {
+	return definitions.AlertRuleTemplate{
+		Title: "Disk full",
+		Parameters: []definitions.AlertRuleTemplateParameter{
+			{Name: "threshold", Type: definitions.AlertRuleTemplateParameterTypeNumber},
+		},
+		Rule: json.RawMessage(`{
+			"folderUID": "my-namespace",
+			"ruleGroup": "disk",
+			"title": "Disk above ${threshold}%",
+			"condition": "A",
+			"data": [{"refId": "A", "datasourceUid": "-100", "model": {"threshold": "${threshold}"}, "relativeTimeRange": {"from": 600, "to": 0}}],
+			"noDataState": "OK",
+			"execErrState": "OK",
+			"for": "5m"
+		}`),
+	}
+}
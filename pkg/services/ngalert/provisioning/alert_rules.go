@@ -238,7 +238,7 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 		NamespaceUID: group.FolderUID,
 		RuleGroup:    group.Title,
 	}
-	rules := make([]*models.AlertRule, len(group.Rules))
+	rules := make([]*models.AlertRule, 0, len(group.Rules))
 	group = *syncGroupRuleFields(&group, orgID)
 	for i := range group.Rules {
 		if err := group.Rules[i].SetDashboardAndPanelFromAnnotations(); err != nil {
@@ -246,6 +246,9 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 		}
 		rules = append(rules, &group.Rules[i])
 	}
+	if err := models.ValidateRuleGroupDependencies(rules); err != nil {
+		return err
+	}
 	delta, err := store.CalculateChanges(ctx, service.ruleStore, key, rules)
 	if err != nil {
 		return fmt.Errorf("failed to calculate diff for alert rules: %w", err)
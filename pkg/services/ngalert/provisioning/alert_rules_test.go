@@ -186,6 +186,17 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, panelId, *updatedGroup.Rules[0].PanelID)
 	})
 
+	t.Run("group write should reject rules that depend on rules of another group", func(t *testing.T) {
+		var orgID int64 = 1
+		group := createDummyGroup("group-test-dependencies", orgID)
+		group.Rules[0].UID = "dependent-rule"
+		group.Rules[0].DependsOn = []models.RuleDependency{
+			{RuleUID: "rule-of-another-group", State: models.RuleDependencyStateNormal},
+		}
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
 	t.Run("alert rule provenace should be correctly checked", func(t *testing.T) {
 		tests := []struct {
 			name   string
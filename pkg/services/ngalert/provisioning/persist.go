@@ -44,6 +44,29 @@ type RuleStore interface {
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) error
 }
 
+// RecurringSilenceStore represents the ability to persist and query recurring silences.
+type RecurringSilenceStore interface {
+	GetRecurringSilences(ctx context.Context, orgID int64) ([]models.RecurringSilence, error)
+	GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error)
+	InsertRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error
+	UpdateRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error
+	DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error
+}
+
+// AlertRuleTemplateStore represents the ability to persist and query alert rule templates and the alert rules
+// created from them.
+type AlertRuleTemplateStore interface {
+	GetAlertRuleTemplates(ctx context.Context, orgID int64) ([]models.AlertRuleTemplate, error)
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error)
+	InsertAlertRuleTemplate(ctx context.Context, template *models.AlertRuleTemplate) error
+	UpdateAlertRuleTemplate(ctx context.Context, template *models.AlertRuleTemplate) error
+	DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error
+	GetAlertRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]models.AlertRuleTemplateInstance, error)
+	InsertAlertRuleTemplateInstance(ctx context.Context, instance *models.AlertRuleTemplateInstance) error
+	UpdateAlertRuleTemplateInstance(ctx context.Context, instance *models.AlertRuleTemplateInstance) error
+	DeleteAlertRuleTemplateInstance(ctx context.Context, orgID int64, ruleUID string) error
+}
+
 // QuotaChecker represents the ability to evaluate whether quotas are met.
 //
 //go:generate mockery --name QuotaChecker --structname MockQuotaChecker --inpackage --filename quota_checker_mock.go --with-expecter
@@ -0,0 +1,140 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type RecurringSilenceService struct {
+	store RecurringSilenceStore
+	prov  ProvisioningStore
+	xact  TransactionManager
+	log   log.Logger
+}
+
+func NewRecurringSilenceService(store RecurringSilenceStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *RecurringSilenceService {
+	return &RecurringSilenceService{
+		store: store,
+		prov:  prov,
+		xact:  xact,
+		log:   log,
+	}
+}
+
+// GetRecurringSilences returns a slice of all recurring silences within the specified org.
+func (svc *RecurringSilenceService) GetRecurringSilences(ctx context.Context, orgID int64) ([]definitions.RecurringSilence, error) {
+	silences, err := svc.store.GetRecurringSilences(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	provenances, err := svc.prov.GetProvenances(ctx, orgID, (&definitions.RecurringSilence{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]definitions.RecurringSilence, 0, len(silences))
+	for _, silence := range silences {
+		s, err := definitions.NewRecurringSilence(silence, provenances[silence.UID])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// GetRecurringSilence returns the recurring silence with the UID. It returns ErrNotFound if it does not exist.
+func (svc *RecurringSilenceService) GetRecurringSilence(ctx context.Context, orgID int64, uid string) (definitions.RecurringSilence, error) {
+	silence, err := svc.store.GetRecurringSilence(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrRecurringSilenceNotFound) {
+			return definitions.RecurringSilence{}, ErrNotFound
+		}
+		return definitions.RecurringSilence{}, err
+	}
+	result, err := definitions.NewRecurringSilence(*silence, models.ProvenanceNone)
+	if err != nil {
+		return definitions.RecurringSilence{}, err
+	}
+	result.Provenance, err = svc.prov.GetProvenance(ctx, &result, orgID)
+	if err != nil {
+		return definitions.RecurringSilence{}, err
+	}
+	return result, nil
+}
+
+// CreateRecurringSilence adds a new recurring silence within the specified org. The created recurring silence is returned.
+func (svc *RecurringSilenceService) CreateRecurringSilence(ctx context.Context, silence definitions.RecurringSilence, orgID int64) (definitions.RecurringSilence, error) {
+	if err := silence.Validate(); err != nil {
+		return definitions.RecurringSilence{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if silence.UID == "" {
+		silence.UID = util.GenerateShortUID()
+	} else if !util.IsValidShortUID(silence.UID) || util.IsShortUIDTooLong(silence.UID) {
+		return definitions.RecurringSilence{}, fmt.Errorf("%w: %s", ErrValidation, "UID must be a valid short UID")
+	}
+	stored, err := silence.UpstreamModel(orgID)
+	if err != nil {
+		return definitions.RecurringSilence{}, err
+	}
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		_, err := svc.store.GetRecurringSilence(ctx, orgID, silence.UID)
+		if err == nil {
+			return fmt.Errorf("%w: %s", ErrValidation, "a recurring silence with this UID already exists")
+		}
+		if !errors.Is(err, models.ErrRecurringSilenceNotFound) {
+			return err
+		}
+		if err := svc.store.InsertRecurringSilence(ctx, &stored); err != nil {
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, &silence, orgID, silence.Provenance)
+	})
+	if err != nil {
+		return definitions.RecurringSilence{}, err
+	}
+	return silence, nil
+}
+
+// UpdateRecurringSilence replaces an existing recurring silence within the specified org. The replaced recurring silence is returned. If the recurring silence does not exist, nil is returned and no action is taken.
+func (svc *RecurringSilenceService) UpdateRecurringSilence(ctx context.Context, silence definitions.RecurringSilence, orgID int64) (*definitions.RecurringSilence, error) {
+	if err := silence.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	stored, err := silence.UpstreamModel(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.UpdateRecurringSilence(ctx, &stored); err != nil {
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, &silence, orgID, silence.Provenance)
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrRecurringSilenceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &silence, nil
+}
+
+// DeleteRecurringSilence deletes the recurring silence with the given UID in the given org. If the recurring silence does not exist, no error is returned.
+func (svc *RecurringSilenceService) DeleteRecurringSilence(ctx context.Context, uid string, orgID int64) error {
+	target := definitions.RecurringSilence{UID: uid}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteRecurringSilence(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.prov.DeleteProvenance(ctx, &target, orgID)
+	})
+}
@@ -0,0 +1,129 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRecurringSilenceService(t *testing.T) {
+	t.Run("create generates a UID and stores the provenance", func(t *testing.T) {
+		sut := createRecurringSilenceSvcSut()
+		silence := createRecurringSilence(t)
+		silence.UID = ""
+		silence.Provenance = models.ProvenanceAPI
+
+		created, err := sut.CreateRecurringSilence(context.Background(), silence, 1)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+
+		stored, err := sut.GetRecurringSilence(context.Background(), 1, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, created, stored)
+	})
+
+	t.Run("create rejects invalid recurring silences", func(t *testing.T) {
+		sut := createRecurringSilenceSvcSut()
+		silence := createRecurringSilence(t)
+		silence.Duration = 0
+
+		_, err := sut.CreateRecurringSilence(context.Background(), silence, 1)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("create rejects duplicate UIDs", func(t *testing.T) {
+		sut := createRecurringSilenceSvcSut()
+		silence := createRecurringSilence(t)
+
+		_, err := sut.CreateRecurringSilence(context.Background(), silence, 1)
+		require.NoError(t, err)
+		_, err = sut.CreateRecurringSilence(context.Background(), silence, 1)
+		require.ErrorIs(t, err, ErrValidation)
+
+		// UIDs are unique per organization
+		_, err = sut.CreateRecurringSilence(context.Background(), silence, 2)
+		require.NoError(t, err)
+	})
+
+	t.Run("get returns ErrNotFound for unknown UIDs", func(t *testing.T) {
+		sut := createRecurringSilenceSvcSut()
+
+		_, err := sut.GetRecurringSilence(context.Background(), 1, "unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("update replaces the recurring silence", func(t *testing.T) {
+		sut := createRecurringSilenceSvcSut()
+		silence := createRecurringSilence(t)
+		_, err := sut.CreateRecurringSilence(context.Background(), silence, 1)
+		require.NoError(t, err)
+
+		silence.Cron = "0 20 * * SUN"
+		silence.Provenance = models.ProvenanceFile
+		updated, err := sut.UpdateRecurringSilence(context.Background(), silence, 1)
+		require.NoError(t, err)
+		require.NotNil(t, updated)
+
+		all, err := sut.GetRecurringSilences(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, "0 20 * * SUN", all[0].Cron)
+		require.Equal(t, models.ProvenanceFile, all[0].Provenance)
+	})
+
+	t.Run("update returns nil if the recurring silence does not exist", func(t *testing.T) {
+		sut := createRecurringSilenceSvcSut()
+
+		updated, err := sut.UpdateRecurringSilence(context.Background(), createRecurringSilence(t), 1)
+		require.NoError(t, err)
+		require.Nil(t, updated)
+	})
+
+	t.Run("delete removes the recurring silence and its provenance", func(t *testing.T) {
+		sut := createRecurringSilenceSvcSut()
+		silence := createRecurringSilence(t)
+		silence.Provenance = models.ProvenanceAPI
+		_, err := sut.CreateRecurringSilence(context.Background(), silence, 1)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.DeleteRecurringSilence(context.Background(), silence.UID, 1))
+
+		all, err := sut.GetRecurringSilences(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, all)
+		provenance, err := sut.prov.GetProvenance(context.Background(), &silence, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+}
+
+func createRecurringSilenceSvcSut() *RecurringSilenceService {
+	return &RecurringSilenceService{
+		store: newFakeRecurringSilenceStore(),
+		prov:  NewFakeProvisioningStore(),
+		xact:  newNopTransactionManager(),
+		log:   log.NewNopLogger(),
+	}
+}
+
+func createRecurringSilence(t *testing.T) definitions.RecurringSilence {
+	t.Helper()
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "cluster", "eu-1")
+	require.NoError(t, err)
+	return definitions.RecurringSilence{
+		UID:       "maintenance",
+		Comment:   "weekly maintenance",
+		CreatedBy: "ops",
+		Matchers:  definitions.ObjectMatchers{matcher},
+		Cron:      "0 22 * * SAT",
+		Duration:  model.Duration(4 * time.Hour),
+	}
+}
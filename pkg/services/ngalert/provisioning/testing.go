@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/md5"
 	"fmt"
+	"sort"
 	"strings"
 
 	mock "github.com/stretchr/testify/mock"
@@ -137,6 +138,55 @@ func (f *fakeProvisioningStore) DeleteProvenance(ctx context.Context, o models.P
 	return nil
 }
 
+type fakeRecurringSilenceStore struct {
+	silences map[int64]map[string]models.RecurringSilence
+}
+
+func newFakeRecurringSilenceStore() *fakeRecurringSilenceStore {
+	return &fakeRecurringSilenceStore{
+		silences: map[int64]map[string]models.RecurringSilence{},
+	}
+}
+
+func (f *fakeRecurringSilenceStore) GetRecurringSilences(ctx context.Context, orgID int64) ([]models.RecurringSilence, error) {
+	result := make([]models.RecurringSilence, 0, len(f.silences[orgID]))
+	for _, s := range f.silences[orgID] {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UID < result[j].UID
+	})
+	return result, nil
+}
+
+func (f *fakeRecurringSilenceStore) GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	if s, ok := f.silences[orgID][uid]; ok {
+		return &s, nil
+	}
+	return nil, models.ErrRecurringSilenceNotFound
+}
+
+func (f *fakeRecurringSilenceStore) InsertRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error {
+	if _, ok := f.silences[silence.OrgID]; !ok {
+		f.silences[silence.OrgID] = map[string]models.RecurringSilence{}
+	}
+	f.silences[silence.OrgID][silence.UID] = *silence
+	return nil
+}
+
+func (f *fakeRecurringSilenceStore) UpdateRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error {
+	if _, ok := f.silences[silence.OrgID][silence.UID]; !ok {
+		return models.ErrRecurringSilenceNotFound
+	}
+	f.silences[silence.OrgID][silence.UID] = *silence
+	return nil
+}
+
+func (f *fakeRecurringSilenceStore) DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error {
+	delete(f.silences[orgID], uid)
+	return nil
+}
+
 type NopTransactionManager struct{}
 
 func newNopTransactionManager() *NopTransactionManager {
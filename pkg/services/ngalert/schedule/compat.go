@@ -91,6 +91,11 @@ func stateToPostableAlert(alertState *state.State, appURL *url.URL) *models.Post
 	}
 }
 
+// AlertLabels returns the labels of the alert that is sent to the Alertmanager for the state.
+func AlertLabels(alertState *state.State) models.LabelSet {
+	return stateToPostableAlert(alertState, nil).Labels
+}
+
 // NoDataAlert is a special alert sent by Grafana to the Alertmanager, that indicates we received no data from the datasource.
 // It effectively replaces the legacy behavior of "Keep Last State" by separating the regular alerting flow from the no data scenario into a separate alerts.
 // The Alert is defined as:
@@ -0,0 +1,100 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// linkDependencies makes the evaluations of rules wait for the evaluations of the upstream rules they depend on,
+// if the upstream rules are scheduled at the same tick. Rules of the same group share the interval,
+// so an upstream rule is always scheduled at the same tick as the rules that depend on it.
+func linkDependencies(items []readyToRunItem) {
+	index := make(map[ngmodels.AlertRuleKey]int, len(items))
+	for i, item := range items {
+		index[item.rule.GetKey()] = i
+	}
+	for i := range items {
+		for _, dep := range items[i].rule.DependsOn {
+			j, ok := index[ngmodels.AlertRuleKey{OrgID: items[i].rule.OrgID, UID: dep.RuleUID}]
+			if !ok {
+				continue
+			}
+			if items[j].done == nil {
+				items[j].done = make(chan struct{})
+			}
+			items[i].dependencies = append(items[i].dependencies, items[j].done)
+		}
+	}
+}
+
+// waitForDependencies blocks until the evaluations of the upstream rules of the evaluation are finished.
+// It gives up when the context is cancelled or when the evaluation interval of the rule elapses.
+func waitForDependencies(ctx context.Context, e *evaluation) error {
+	if len(e.dependencies) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(e.rule.IntervalSeconds)*time.Second)
+	defer cancel()
+	for _, done := range e.dependencies {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for the evaluation of upstream rules: %w", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// missingDependency returns the first dependency of the rule whose upstream rule is not scheduled anymore,
+// for example because it was deleted. It returns false if all upstream rules exist.
+func missingDependency(rule *ngmodels.AlertRule, registry *alertRulesRegistry) (ngmodels.RuleDependency, bool) {
+	for _, dep := range rule.DependsOn {
+		if registry.get(ngmodels.AlertRuleKey{OrgID: rule.OrgID, UID: dep.RuleUID}) == nil {
+			return dep, true
+		}
+	}
+	return ngmodels.RuleDependency{}, false
+}
+
+// unsatisfiedDependency returns the first dependency of the rule whose upstream rule is not in the expected state.
+// It returns false if all dependencies are satisfied.
+func unsatisfiedDependency(rule *ngmodels.AlertRule, stateManager *state.Manager) (ngmodels.RuleDependency, bool) {
+	for _, dep := range rule.DependsOn {
+		if ruleDependencyState(stateManager.GetStatesForRuleUID(rule.OrgID, dep.RuleUID)) != dep.State {
+			return dep, true
+		}
+	}
+	return ngmodels.RuleDependency{}, false
+}
+
+// ruleDependencyState summarizes the states of the alert instances of a rule.
+// The rule is Alerting if at least one instance is firing, Pending if at least one instance is pending,
+// and Normal otherwise.
+func ruleDependencyState(states []*state.State) ngmodels.RuleDependencyState {
+	result := ngmodels.RuleDependencyStateNormal
+	for _, s := range states {
+		switch s.State {
+		case eval.Alerting:
+			return ngmodels.RuleDependencyStateAlerting
+		case eval.Pending:
+			result = ngmodels.RuleDependencyStatePending
+		}
+	}
+	return result
+}
+
+// suppressAlerting turns the firing results of an evaluation into normal ones.
+// It is used to prevent a rule from firing when its dependencies are not satisfied.
+func suppressAlerting(results eval.Results) eval.Results {
+	for i := range results {
+		if results[i].State == eval.Alerting {
+			results[i].State = eval.Normal
+		}
+	}
+	return results
+}
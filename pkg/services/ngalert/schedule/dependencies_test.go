@@ -0,0 +1,129 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestLinkDependencies(t *testing.T) {
+	gen := models.AlertRuleGen(models.WithOrgID(1))
+	upstream := gen()
+	dependent := gen()
+	dependent.DependsOn = []models.RuleDependency{
+		{RuleUID: upstream.UID, State: models.RuleDependencyStateNormal},
+		{RuleUID: "not-scheduled", State: models.RuleDependencyStateNormal},
+	}
+	other := gen()
+
+	items := []readyToRunItem{
+		{evaluation: evaluation{rule: dependent}},
+		{evaluation: evaluation{rule: upstream}},
+		{evaluation: evaluation{rule: other}},
+	}
+	linkDependencies(items)
+
+	require.Len(t, items[0].dependencies, 1)
+	require.NotNil(t, items[1].done)
+	require.Nil(t, items[0].done)
+	require.Nil(t, items[2].done)
+	require.Empty(t, items[2].dependencies)
+
+	t.Run("dependent evaluation waits until upstream is finished", func(t *testing.T) {
+		waitErr := make(chan error)
+		go func() {
+			waitErr <- waitForDependencies(context.Background(), &items[0].evaluation)
+		}()
+
+		select {
+		case <-waitErr:
+			t.Fatal("dependent evaluation should wait for upstream evaluation")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		items[1].finish()
+		require.NoError(t, waitForErrChannel(t, waitErr))
+	})
+
+	t.Run("dependent evaluation stops waiting when context is cancelled", func(t *testing.T) {
+		e := &evaluation{rule: dependent, dependencies: []<-chan struct{}{make(chan struct{})}}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, waitForDependencies(ctx, e), context.Canceled)
+	})
+}
+
+func TestRuleDependencyState(t *testing.T) {
+	testCases := []struct {
+		name     string
+		states   []eval.State
+		expected models.RuleDependencyState
+	}{
+		{
+			name:     "no instances",
+			expected: models.RuleDependencyStateNormal,
+		},
+		{
+			name:     "all instances are normal",
+			states:   []eval.State{eval.Normal, eval.NoData, eval.Error},
+			expected: models.RuleDependencyStateNormal,
+		},
+		{
+			name:     "an instance is pending",
+			states:   []eval.State{eval.Normal, eval.Pending},
+			expected: models.RuleDependencyStatePending,
+		},
+		{
+			name:     "an instance is firing",
+			states:   []eval.State{eval.Pending, eval.Alerting, eval.Normal},
+			expected: models.RuleDependencyStateAlerting,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			states := make([]*state.State, 0, len(tc.states))
+			for _, s := range tc.states {
+				states = append(states, &state.State{State: s})
+			}
+			require.Equal(t, tc.expected, ruleDependencyState(states))
+		})
+	}
+}
+
+func TestSuppressAlerting(t *testing.T) {
+	results := eval.Results{
+		{State: eval.Alerting},
+		{State: eval.Normal},
+		{State: eval.Error},
+	}
+	results = suppressAlerting(results)
+	require.Equal(t, eval.Normal, results[0].State)
+	require.Equal(t, eval.Normal, results[1].State)
+	require.Equal(t, eval.Error, results[2].State)
+}
+
+func TestMissingDependency(t *testing.T) {
+	gen := models.AlertRuleGen(models.WithOrgID(1))
+	upstream := gen()
+	dependent := gen()
+	dependent.DependsOn = []models.RuleDependency{
+		{RuleUID: upstream.UID, State: models.RuleDependencyStateNormal},
+	}
+
+	registry := alertRulesRegistry{rules: make(map[models.AlertRuleKey]*models.AlertRule)}
+	registry.set([]*models.AlertRule{upstream, dependent}, nil)
+	_, ok := missingDependency(dependent, &registry)
+	require.False(t, ok)
+
+	registry.del(upstream.GetKey())
+	dep, ok := missingDependency(dependent, &registry)
+	require.True(t, ok)
+	require.Equal(t, upstream.UID, dep.RuleUID)
+}
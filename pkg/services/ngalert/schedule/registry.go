@@ -144,6 +144,17 @@ type evaluation struct {
 	scheduledAt time.Time
 	rule        *models.AlertRule
 	folderTitle string
+	// done is closed when the evaluation is finished or dropped. It is nil if no other evaluation waits for it.
+	done chan struct{}
+	// dependencies are the done channels of the evaluations of the upstream rules scheduled at the same tick.
+	dependencies []<-chan struct{}
+}
+
+// finish notifies the evaluations that depend on this evaluation that it is finished.
+func (e *evaluation) finish() {
+	if e.done != nil {
+		close(e.done)
+	}
 }
 
 type alertRulesRegistry struct {
@@ -8,9 +8,8 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
-	alertingModels "github.com/grafana/alerting/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/hashicorp/go-multierror"
-	prometheusModel "github.com/prometheus/common/model"
 	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 
@@ -54,6 +53,11 @@ type RulesStore interface {
 	GetAlertRulesForScheduling(ctx context.Context, query *ngmodels.GetAlertRulesForSchedulingQuery) error
 }
 
+// RecordingWriter is an interface for a service that stores the results of recording rules.
+type RecordingWriter interface {
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error
+}
+
 type schedule struct {
 	// base tick rate (fastest possible configured check)
 	baseInterval time.Duration
@@ -91,6 +95,12 @@ type schedule struct {
 	alertsSender    AlertsSender
 	minRuleInterval time.Duration
 
+	// recordingWriter stores the results of recording rules. If it is nil, recording rules are not evaluated.
+	recordingWriter RecordingWriter
+
+	// sharding distributes the evaluation of rules across the instances of the HA cluster. If it is nil, all rules are evaluated.
+	sharding *ruleSharding
+
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
 	// current tick depends on its evaluation interval and when it was
@@ -113,6 +123,10 @@ type SchedulerCfg struct {
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
 	Tracer               tracing.Tracer
+	RecordingWriter      RecordingWriter
+	// ClusterMembership is used to distribute the evaluation of rules across the instances of the HA cluster.
+	// If it is nil, this instance evaluates all rules.
+	ClusterMembership ClusterMembership
 }
 
 // NewScheduler returns a new schedule.
@@ -133,6 +147,11 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
+		recordingWriter:       cfg.RecordingWriter,
+	}
+
+	if cfg.ClusterMembership != nil {
+		sch.sharding = newRuleSharding(cfg.ClusterMembership)
 	}
 
 	return &sch
@@ -228,6 +247,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	sch.metrics.SchedulableAlertRules.Set(float64(len(alertRules)))
 	sch.metrics.SchedulableAlertRulesHash.Set(float64(hashUIDs(alertRules)))
 
+	if sch.sharding != nil && sch.sharding.refresh() {
+		sch.log.Info("Cluster membership has changed, rebalancing alert rules", "self", sch.sharding.self, "members", sch.sharding.members)
+	}
+	owned := make(map[ngmodels.AlertRuleKey]bool, len(alertRules))
+
 	readyToRun := make([]readyToRunItem, 0)
 	missingFolder := make(map[string][]string)
 	for _, item := range alertRules {
@@ -255,8 +279,10 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 			continue
 		}
 
+		isOwner := sch.ownsRule(ctx, item, owned)
+
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-		if item.IntervalSeconds != 0 && tickNum%itemFrequency == 0 {
+		if isOwner && item.IntervalSeconds != 0 && tickNum%itemFrequency == 0 {
 			var folderTitle string
 			if !sch.disableGrafanaFolder {
 				title, ok := folderTitles[item.NamespaceUID]
@@ -277,10 +303,16 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		delete(registeredDefinitions, key)
 	}
 
+	if sch.sharding != nil {
+		sch.sharding.owned = owned
+	}
+
 	if len(missingFolder) > 0 { // if this happens then there can be problems with fetching folders from the database.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
 
+	linkDependencies(readyToRun)
+
 	var step int64 = 0
 	if len(readyToRun) > 0 {
 		step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
@@ -293,10 +325,12 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 			key := item.rule.GetKey()
 			success, dropped := item.ruleInfo.eval(&item.evaluation)
 			if !success {
+				item.evaluation.finish()
 				sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", append(key.LogContext(), "time", tick)...)
 				return
 			}
 			if dropped != nil {
+				dropped.finish()
 				sch.log.Warn("Tick dropped because alert rule evaluation is too slow", append(key.LogContext(), "time", tick)...)
 				orgID := fmt.Sprint(key.OrgID)
 				sch.metrics.EvaluationMissed.WithLabelValues(orgID, item.rule.Title).Inc()
@@ -340,6 +374,57 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		notify(states)
 	}
 
+	// evaluateRecording evaluates a recording rule and writes the result of the queried expression as a new metric.
+	// Recording rules do not have state, so the state manager is not involved.
+	evaluateRecording := func(evalCtx eval.EvaluationContext, logger log.Logger, e *evaluation, span tracing.Span) {
+		if sch.recordingWriter == nil {
+			logger.Debug("Skip evaluation of recording rule because recording rules are disabled")
+			return
+		}
+		start := sch.clock.Now()
+		err := func() error {
+			ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
+			if err != nil {
+				return fmt.Errorf("failed to build rule evaluator: %w", err)
+			}
+			resp, err := ruleEval.EvaluateRaw(evalCtx.Ctx, e.scheduledAt)
+			if err != nil {
+				return fmt.Errorf("failed to evaluate rule: %w", err)
+			}
+			result, ok := resp.Responses[e.rule.Record.From]
+			if !ok {
+				return fmt.Errorf("no result for query or expression %s", e.rule.Record.From)
+			}
+			if result.Error != nil {
+				return fmt.Errorf("failed to evaluate query or expression %s: %w", e.rule.Record.From, result.Error)
+			}
+			return sch.recordingWriter.Write(evalCtx.Ctx, e.rule.Record.Metric, e.scheduledAt, result.Frames, e.rule.Labels)
+		}()
+		dur := sch.clock.Now().Sub(start)
+
+		evalTotal.Inc()
+		evalDuration.Observe(dur.Seconds())
+
+		if err != nil {
+			evalTotalFailures.Inc()
+			logger.Error("Failed to evaluate recording rule", "error", err, "duration", dur)
+			span.RecordError(err)
+			span.AddEvents(
+				[]string{"error", "message"},
+				[]tracing.EventValue{
+					{Str: fmt.Sprintf("%v", err)},
+					{Str: "recording rule evaluation failed"},
+				})
+			return
+		}
+		logger.Debug("Recording rule evaluated", "metric", e.rule.Record.Metric, "duration", dur)
+		span.AddEvents(
+			[]string{"message"},
+			[]tracing.EventValue{
+				{Str: "recording rule evaluated"},
+			})
+	}
+
 	evaluate := func(ctx context.Context, attempt int64, e *evaluation, span tracing.Span) {
 		logger := logger.New("version", e.rule.Version, "attempt", attempt, "now", e.scheduledAt)
 		start := sch.clock.Now()
@@ -359,6 +444,10 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 			},
 		}
 		evalCtx := eval.Context(ctx, schedulerUser)
+		if e.rule.Type() == ngmodels.RuleTypeRecording {
+			evaluateRecording(evalCtx, logger, e, span)
+			return
+		}
 		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
 		var results eval.Results
 		var dur time.Duration
@@ -371,6 +460,12 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 			logger.Error("Failed to build rule evaluator", "error", err)
 		}
 		dur = sch.clock.Now().Sub(start)
+		if dep, ok := missingDependency(e.rule, &sch.schedulableAlertRules); ok && err == nil {
+			// a rule that depends on a deleted rule is misconfigured, so report an error instead of evaluating it as if the dependency was satisfied.
+			err = fmt.Errorf("upstream rule %s that the rule depends on does not exist", dep.RuleUID)
+			logger.Error("Rule depends on a rule that does not exist", "upstreamRuleUID", dep.RuleUID)
+			results = eval.Results{eval.NewResultFromError(err, e.scheduledAt, dur)}
+		}
 
 		evalTotal.Inc()
 		evalDuration.Observe(dur.Seconds())
@@ -407,6 +502,10 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 			logger.Debug("Skip updating the state because the context has been cancelled")
 			return
 		}
+		if dep, ok := unsatisfiedDependency(e.rule, sch.stateManager); ok {
+			logger.Debug("Suppressing firing alerts because upstream rule is not in the expected state", "upstreamRuleUID", dep.RuleUID, "expectedState", dep.State)
+			results = suppressAlerting(results)
+		}
 		processedStates := sch.stateManager.ProcessEvalResults(ctx, e.scheduledAt, e.rule, results, sch.getRuleExtraLabels(e))
 		alerts := FromStateTransitionToPostableAlerts(processedStates, sch.stateManager, sch.appURL)
 		span.AddEvents(
@@ -459,6 +558,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				return nil
 			}
 			if evalRunning {
+				ctx.finish()
 				continue
 			}
 
@@ -466,9 +566,14 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				evalRunning = true
 				defer func() {
 					evalRunning = false
+					ctx.finish()
 					sch.evalApplied(key, ctx.scheduledAt)
 				}()
 
+				if err := waitForDependencies(grafanaCtx, ctx); err != nil {
+					logger.Warn("Evaluating rule without waiting for upstream rules", "error", err)
+				}
+
 				err := retryIfError(func(attempt int64) error {
 					newVersion := ctx.rule.Version
 					isPaused := ctx.rule.IsPaused
@@ -539,14 +644,5 @@ func (sch *schedule) stopApplied(alertDefKey ngmodels.AlertRuleKey) {
 }
 
 func (sch *schedule) getRuleExtraLabels(evalCtx *evaluation) map[string]string {
-	extraLabels := make(map[string]string, 4)
-
-	extraLabels[alertingModels.NamespaceUIDLabel] = evalCtx.rule.NamespaceUID
-	extraLabels[prometheusModel.AlertNameLabel] = evalCtx.rule.Title
-	extraLabels[alertingModels.RuleUIDLabel] = evalCtx.rule.UID
-
-	if !sch.disableGrafanaFolder {
-		extraLabels[ngmodels.FolderTitleLabel] = evalCtx.folderTitle
-	}
-	return extraLabels
+	return state.GetRuleExtraLabels(evalCtx.rule, evalCtx.folderTitle, !sch.disableGrafanaFolder)
 }
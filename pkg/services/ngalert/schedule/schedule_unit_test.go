@@ -563,6 +563,70 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 		})
 	})
 
+	t.Run("when rule is a recording rule", func(t *testing.T) {
+		createRecordingSchedule := func(writer RecordingWriter) (*schedule, *fakeRulesStore, chan time.Time, *AlertsSenderMock) {
+			evalAppliedChan := make(chan time.Time)
+			sender := AlertsSenderMock{}
+			sender.EXPECT().Send(mock.Anything, mock.Anything).Return()
+			sch, ruleStore, _, _ := createSchedule(evalAppliedChan, &sender)
+			sch.recordingWriter = writer
+			return sch, ruleStore, evalAppliedChan, &sender
+		}
+
+		t.Run("it should write the result and not touch the state", func(t *testing.T) {
+			writer := &fakeRecordingWriter{}
+			sch, ruleStore, evalAppliedChan, sender := createRecordingSchedule(writer)
+			rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithRecord("test_metric"))()
+			rule.For = 0
+			ruleStore.PutRule(context.Background(), rule)
+
+			evalChan := make(chan *evaluation)
+			go func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				t.Cleanup(cancel)
+				_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+			}()
+
+			expectedTime := sch.clock.Now()
+			evalChan <- &evaluation{
+				scheduledAt: expectedTime,
+				rule:        rule,
+			}
+			waitForTimeChannel(t, evalAppliedChan)
+
+			writes := writer.getWrites()
+			require.Len(t, writes, 1)
+			require.Equal(t, "test_metric", writes[0].name)
+			require.Equal(t, expectedTime, writes[0].t)
+			require.Equal(t, rule.Labels, writes[0].extraLabels)
+			require.NotEmpty(t, writes[0].frames)
+
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+			sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+		})
+
+		t.Run("it should not evaluate the rule if recording rules are disabled", func(t *testing.T) {
+			sch, ruleStore, evalAppliedChan, _ := createRecordingSchedule(nil)
+			rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithRecord("test_metric"))()
+			ruleStore.PutRule(context.Background(), rule)
+
+			evalChan := make(chan *evaluation)
+			go func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				t.Cleanup(cancel)
+				_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+			}()
+
+			evalChan <- &evaluation{
+				scheduledAt: sch.clock.Now(),
+				rule:        rule,
+			}
+			waitForTimeChannel(t, evalAppliedChan)
+
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+	})
+
 	t.Run("when there are no alerts to send it should not call notifiers", func(t *testing.T) {
 		rule := models.AlertRuleGen(withQueryForState(t, eval.Normal))()
 
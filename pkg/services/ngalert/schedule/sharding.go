@@ -0,0 +1,151 @@
+package schedule
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ringTokensPerMember is the number of virtual nodes each member of the cluster gets on the hash ring.
+// More tokens make the distribution of rules more even at the cost of a larger ring.
+const ringTokensPerMember = 128
+
+// ClusterMembership provides the members of the HA cluster the evaluation of rules is distributed across.
+type ClusterMembership interface {
+	// ClusterMembers returns the name of this instance and the names of all alive members of the cluster, including this instance.
+	ClusterMembers() (string, []string)
+}
+
+// hashRing assigns rule groups to members of the cluster using consistent hashing.
+// When a member joins or leaves the cluster, only the rule groups of that member are reassigned.
+// All rules of a group are assigned to the same member, because rules depend on the state of other rules of their group.
+type hashRing struct {
+	tokens []uint32
+	owners map[uint32]string
+}
+
+func newHashRing(members []string) *hashRing {
+	r := &hashRing{
+		tokens: make([]uint32, 0, len(members)*ringTokensPerMember),
+		owners: make(map[uint32]string, len(members)*ringTokensPerMember),
+	}
+	for _, member := range members {
+		for i := 0; i < ringTokensPerMember; i++ {
+			token := hashString(member + "#" + strconv.Itoa(i))
+			// on collision, the token belongs to the member whose name sorts first, so that all instances agree on the owner
+			if owner, ok := r.owners[token]; ok {
+				if owner < member {
+					continue
+				}
+			} else {
+				r.tokens = append(r.tokens, token)
+			}
+			r.owners[token] = member
+		}
+	}
+	sort.Slice(r.tokens, func(i, j int) bool { return r.tokens[i] < r.tokens[j] })
+	return r
+}
+
+// owner returns the member that owns the rule group. It returns an empty string if the ring has no members.
+func (r *hashRing) owner(key ngmodels.AlertRuleGroupKey) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := hashString(strconv.FormatInt(key.OrgID, 10) + "/" + key.NamespaceUID + "/" + key.RuleGroup)
+	i := sort.Search(len(r.tokens), func(i int) bool { return r.tokens[i] >= h })
+	if i == len(r.tokens) {
+		i = 0
+	}
+	return r.owners[r.tokens[i]]
+}
+
+func hashString(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// ruleSharding distributes the evaluation of rules across the members of the HA cluster.
+// It is used only by the scheduling loop and therefore is not safe for concurrent use.
+type ruleSharding struct {
+	membership ClusterMembership
+
+	self    string
+	members string
+	ring    *hashRing
+
+	// owned contains the rules that were scheduled in the previous tick and whether this instance owned them.
+	owned map[ngmodels.AlertRuleKey]bool
+}
+
+func newRuleSharding(membership ClusterMembership) *ruleSharding {
+	return &ruleSharding{
+		membership: membership,
+		ring:       newHashRing(nil),
+		owned:      make(map[ngmodels.AlertRuleKey]bool),
+	}
+}
+
+// refresh rebuilds the hash ring if the members of the cluster have changed since the last call.
+// It returns true if the ring was rebuilt.
+func (s *ruleSharding) refresh() bool {
+	self, members := s.membership.ClusterMembers()
+	sorted := append([]string(nil), members...)
+	sort.Strings(sorted)
+	joined := strings.Join(sorted, ",")
+	if self == s.self && joined == s.members {
+		return false
+	}
+	s.self = self
+	s.members = joined
+	s.ring = newHashRing(sorted)
+	return true
+}
+
+// owns returns true if this instance should evaluate the rule.
+// If the membership of the cluster is unknown, every instance evaluates every rule.
+func (s *ruleSharding) owns(rule *ngmodels.AlertRule) bool {
+	if s.self == "" {
+		return true
+	}
+	owner := s.ring.owner(rule.GetGroupKey())
+	return owner == "" || owner == s.self
+}
+
+// ownsRule returns true if this instance should evaluate the rule in the current tick.
+// When the rule moves to another instance, its state is removed from the cache. When the rule moves to this instance,
+// its state is loaded from the database, so that the evaluation continues where the previous owner stopped.
+// The first time a rule is scheduled, its state is removed from the cache if this instance does not own it, because
+// the state manager warms up the states of all rules on start.
+func (sch *schedule) ownsRule(ctx context.Context, rule *ngmodels.AlertRule, owned map[ngmodels.AlertRuleKey]bool) bool {
+	if sch.sharding == nil {
+		return true
+	}
+	key := rule.GetKey()
+	isOwner := sch.sharding.owns(rule)
+	owned[key] = isOwner
+
+	wasOwner, known := sch.sharding.owned[key]
+	if !known {
+		if !isOwner {
+			sch.stateManager.ForgetRule(key)
+		}
+		return isOwner
+	}
+	if wasOwner == isOwner {
+		return isOwner
+	}
+	if isOwner {
+		sch.log.Info("Taking over evaluation of the rule from another instance", key.LogContext()...)
+		sch.stateManager.WarmRule(ctx, rule)
+	} else {
+		sch.log.Info("Handing over evaluation of the rule to another instance", key.LogContext()...)
+		sch.stateManager.ForgetRule(key)
+	}
+	return isOwner
+}
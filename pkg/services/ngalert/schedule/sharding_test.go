@@ -0,0 +1,235 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestHashRing(t *testing.T) {
+	keys := make([]models.AlertRuleGroupKey, 0, 3000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, models.AlertRuleGroupKey{OrgID: int64(i%3 + 1), NamespaceUID: fmt.Sprintf("folder-%d", i%10), RuleGroup: fmt.Sprintf("group-%d", i)})
+	}
+
+	t.Run("empty ring has no owners", func(t *testing.T) {
+		require.Empty(t, newHashRing(nil).owner(keys[0]))
+	})
+
+	t.Run("rule groups are distributed across all members", func(t *testing.T) {
+		ring := newHashRing([]string{"a", "b", "c"})
+		counts := map[string]int{}
+		for _, key := range keys {
+			counts[ring.owner(key)]++
+		}
+		require.Len(t, counts, 3)
+		for member, count := range counts {
+			require.Greaterf(t, count, len(keys)/6, "member %s owns too few rule groups", member)
+		}
+	})
+
+	t.Run("only rule groups of the new member are reassigned when a member joins", func(t *testing.T) {
+		before := newHashRing([]string{"a", "b", "c"})
+		after := newHashRing([]string{"a", "b", "c", "d"})
+		moved := 0
+		for _, key := range keys {
+			if before.owner(key) != after.owner(key) {
+				require.Equal(t, "d", after.owner(key))
+				moved++
+			}
+		}
+		require.Greater(t, moved, 0)
+		require.Less(t, moved, len(keys)/2)
+	})
+}
+
+type fakeClusterMembership struct {
+	self    string
+	members []string
+}
+
+func (f *fakeClusterMembership) ClusterMembers() (string, []string) {
+	return f.self, f.members
+}
+
+func TestSchedule_sharding(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	membership := &fakeClusterMembership{self: "a", members: []string{"a", "b"}}
+	sch.sharding = newRuleSharding(membership)
+	evalAppliedCh := make(chan models.AlertRuleKey, 200)
+	sch.evalAppliedFunc = func(key models.AlertRuleKey, _ time.Time) {
+		evalAppliedCh <- key
+	}
+	waitForEvaluations := func(t *testing.T, count int) {
+		t.Helper()
+		for i := 0; i < count; i++ {
+			select {
+			case <-evalAppliedCh:
+			case <-time.After(10 * time.Second):
+				t.Fatal("Timeout waiting for rule evaluations")
+			}
+		}
+	}
+
+	rules := models.GenerateAlertRules(50, models.AlertRuleGen(withQueryForState(t, eval.Normal), models.WithOrgID(1), models.WithInterval(time.Second)))
+	ruleStore.PutRule(context.Background(), rules...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	tick := time.Time{}
+
+	scheduledKeys := func(items []readyToRunItem) map[models.AlertRuleKey]struct{} {
+		result := make(map[models.AlertRuleKey]struct{}, len(items))
+		for _, item := range items {
+			result[item.rule.GetKey()] = struct{}{}
+		}
+		return result
+	}
+
+	t.Run("instance evaluates only the rules it owns", func(t *testing.T) {
+		tick = tick.Add(time.Second)
+		scheduled, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		keys := scheduledKeys(scheduled)
+
+		ring := newHashRing([]string{"a", "b"})
+		for _, rule := range rules {
+			_, ok := keys[rule.GetKey()]
+			require.Equal(t, ring.owner(rule.GetGroupKey()) == "a", ok)
+		}
+		require.NotEmpty(t, keys)
+		require.Less(t, len(keys), len(rules))
+		waitForEvaluations(t, len(keys))
+	})
+
+	t.Run("state of rules handed over to another instance is forgotten", func(t *testing.T) {
+		var owned *models.AlertRule
+		for _, rule := range rules {
+			if sch.sharding.owns(rule) {
+				owned = rule
+				break
+			}
+		}
+		require.NotNil(t, owned)
+		sch.stateManager.Put([]*state.State{{OrgID: owned.OrgID, AlertRuleUID: owned.UID, CacheID: "test", State: eval.Alerting}})
+
+		// a replica that owns no rules
+		membership.members = []string{"a", "b"}
+		membership.self = "c"
+		tick = tick.Add(time.Second)
+		scheduled, _ := sch.processTick(ctx, dispatcherGroup, tick)
+
+		require.Empty(t, scheduled)
+		require.Empty(t, sch.stateManager.GetStatesForRuleUID(owned.OrgID, owned.UID))
+	})
+
+	t.Run("instance evaluates all rules when it is the only member", func(t *testing.T) {
+		membership.self = "a"
+		membership.members = []string{"a"}
+		tick = tick.Add(time.Second)
+		scheduled, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, scheduled, len(rules))
+		waitForEvaluations(t, len(rules))
+	})
+}
+
+func TestSchedule_shardingWarmStates(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sch.sharding = newRuleSharding(&fakeClusterMembership{self: "a", members: []string{"a", "b"}})
+	sch.evalAppliedFunc = func(models.AlertRuleKey, time.Time) {}
+
+	rules := models.GenerateAlertRules(50, models.AlertRuleGen(withQueryForState(t, eval.Normal), models.WithOrgID(1), models.WithInterval(time.Second)))
+	ruleStore.PutRule(context.Background(), rules...)
+
+	// the states of all rules are loaded on start
+	for _, rule := range rules {
+		sch.stateManager.Put([]*state.State{{OrgID: rule.OrgID, AlertRuleUID: rule.UID, CacheID: "warm", State: eval.Alerting}})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	_, _ = sch.processTick(ctx, dispatcherGroup, time.Time{}.Add(time.Second))
+
+	ring := newHashRing([]string{"a", "b"})
+	for _, rule := range rules {
+		states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		if ring.owner(rule.GetGroupKey()) == "a" {
+			require.NotEmpty(t, states)
+		} else {
+			require.Empty(t, states)
+		}
+	}
+}
+
+func TestSchedule_shardingDependentGroup(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+
+	gen := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithOrgID(1), models.WithInterval(time.Second))
+	upstream := gen()
+	group := []*models.AlertRule{upstream}
+	for i := 0; i < 20; i++ {
+		dependent := gen()
+		dependent.DependsOn = []models.RuleDependency{{RuleUID: upstream.UID, State: models.RuleDependencyStateAlerting}}
+		group = append(group, dependent)
+	}
+	for _, rule := range group {
+		rule.NamespaceUID = upstream.NamespaceUID
+		rule.RuleGroup = upstream.RuleGroup
+	}
+	ruleStore.PutRule(context.Background(), group...)
+
+	newMember := func(self string) (*schedule, chan models.AlertRuleKey) {
+		sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+		sch.sharding = newRuleSharding(&fakeClusterMembership{self: self, members: []string{"a", "b"}})
+		evalAppliedCh := make(chan models.AlertRuleKey, len(group))
+		sch.evalAppliedFunc = func(key models.AlertRuleKey, _ time.Time) {
+			evalAppliedCh <- key
+		}
+		return sch, evalAppliedCh
+	}
+	a, aEvalAppliedCh := newMember("a")
+	b, bEvalAppliedCh := newMember("b")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	tick := time.Time{}.Add(time.Second)
+
+	aScheduled, _ := a.processTick(ctx, dispatcherGroup, tick)
+	bScheduled, _ := b.processTick(ctx, dispatcherGroup, tick)
+
+	// the whole group is evaluated by a single member
+	owner, ownerEvalAppliedCh, other := a, aEvalAppliedCh, b
+	if len(aScheduled) == 0 {
+		owner, ownerEvalAppliedCh, other = b, bEvalAppliedCh, a
+		require.Len(t, bScheduled, len(group))
+	} else {
+		require.Len(t, aScheduled, len(group))
+		require.Empty(t, bScheduled)
+	}
+
+	for i := 0; i < len(group); i++ {
+		select {
+		case <-ownerEvalAppliedCh:
+		case <-time.After(10 * time.Second):
+			t.Fatal("Timeout waiting for rule evaluations")
+		}
+	}
+
+	// the dependent rules read the state of the upstream rule from the member that evaluated it
+	require.NotEmpty(t, owner.stateManager.GetStatesForRuleUID(upstream.OrgID, upstream.UID))
+	for _, rule := range group {
+		require.Empty(t, other.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+	}
+}
@@ -2,9 +2,12 @@ package schedule
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -80,3 +83,28 @@ func (f *fakeRulesStore) DeleteRule(rules ...*models.AlertRule) {
 func (f *fakeRulesStore) getNamespaceTitle(uid string) string {
 	return "TEST-FOLDER-" + uid
 }
+
+type fakeRecordingWriter struct {
+	mtx    sync.Mutex
+	writes []fakeRecordingWrite
+}
+
+type fakeRecordingWrite struct {
+	name        string
+	t           time.Time
+	frames      data.Frames
+	extraLabels map[string]string
+}
+
+func (w *fakeRecordingWriter) Write(_ context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.writes = append(w.writes, fakeRecordingWrite{name: name, t: t, frames: frames, extraLabels: extraLabels})
+	return nil
+}
+
+func (w *fakeRecordingWriter) getWrites() []fakeRecordingWrite {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return append([]fakeRecordingWrite(nil), w.writes...)
+}
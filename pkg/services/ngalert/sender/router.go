@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 
 	"github.com/grafana/grafana/pkg/api/datasource"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -38,6 +40,8 @@ type AlertsRouter struct {
 	sendAlertsTo                 map[int64]models.AlertmanagersChoice
 	externalAlertmanagers        map[int64]*ExternalAlertmanager
 	externalAlertmanagersCfgHash map[int64]string
+	// parentOrgs maps organizations to the organization whose Alertmanager also receives their alerts.
+	parentOrgs map[int64]int64
 
 	multiOrgNotifier *notifier.MultiOrgAlertmanager
 
@@ -61,6 +65,7 @@ func NewAlertsRouter(multiOrgNotifier *notifier.MultiOrgAlertmanager, store stor
 		externalAlertmanagers:        map[int64]*ExternalAlertmanager{},
 		externalAlertmanagersCfgHash: map[int64]string{},
 		sendAlertsTo:                 map[int64]models.AlertmanagersChoice{},
+		parentOrgs:                   map[int64]int64{},
 
 		multiOrgNotifier: multiOrgNotifier,
 
@@ -85,6 +90,7 @@ func (d *AlertsRouter) SyncAndApplyConfigFromDatabase() error {
 	d.logger.Debug("Attempting to sync admin configs", "count", len(cfgs))
 
 	orgsFound := make(map[int64]struct{}, len(cfgs))
+	parentOrgs := make(map[int64]int64)
 	d.adminConfigMtx.Lock()
 	for _, cfg := range cfgs {
 		_, isDisabledOrg := d.disabledOrgs[cfg.OrgID]
@@ -94,6 +100,9 @@ func (d *AlertsRouter) SyncAndApplyConfigFromDatabase() error {
 
 		// Update the Alertmanagers choice for the organization.
 		d.sendAlertsTo[cfg.OrgID] = cfg.SendAlertsTo
+		if cfg.ParentOrgID > 0 && cfg.ParentOrgID != cfg.OrgID {
+			parentOrgs[cfg.OrgID] = cfg.ParentOrgID
+		}
 
 		orgsFound[cfg.OrgID] = struct{}{} // keep track of the which externalAlertmanagers we need to keep.
 
@@ -161,6 +170,8 @@ func (d *AlertsRouter) SyncAndApplyConfigFromDatabase() error {
 		d.externalAlertmanagersCfgHash[cfg.OrgID] = amHash
 	}
 
+	d.parentOrgs = parentOrgs
+
 	sendersToStop := map[int64]*ExternalAlertmanager{}
 
 	for orgID, s := range d.externalAlertmanagers {
@@ -297,6 +308,37 @@ func (d *AlertsRouter) Send(key models.AlertRuleKey, alerts definitions.Postable
 	if !localNotifierExist && !externalNotifierExist {
 		logger.Error("No external or internal notifier - alerts not delivered", "count", len(alerts.PostableAlerts))
 	}
+
+	if parentOrgID, ok := d.parentOrgs[key.OrgID]; ok {
+		d.sendToParentOrg(logger, key.OrgID, parentOrgID, alerts)
+	}
+}
+
+// sendToParentOrg sends alerts to the internal Alertmanager of the parent organization. The ID of the organization
+// is added as a label so that the routing tree of the parent can tell the alerts of its child organizations apart.
+// Alerts are only forwarded to the direct parent, not to its parent in turn.
+func (d *AlertsRouter) sendToParentOrg(logger log.Logger, orgID, parentOrgID int64, alerts definitions.PostableAlerts) {
+	n, err := d.multiOrgNotifier.AlertmanagerFor(parentOrgID)
+	if err != nil {
+		logger.Error("Notifier of the parent organization is not available", "parentOrg", parentOrgID, "error", err)
+		return
+	}
+
+	forwarded := definitions.PostableAlerts{PostableAlerts: make([]amv2.PostableAlert, 0, len(alerts.PostableAlerts))}
+	for _, alert := range alerts.PostableAlerts {
+		labels := make(amv2.LabelSet, len(alert.Labels)+1)
+		for k, v := range alert.Labels {
+			labels[k] = v
+		}
+		labels[models.OrgIDLabel] = strconv.FormatInt(orgID, 10)
+		alert.Labels = labels
+		forwarded.PostableAlerts = append(forwarded.PostableAlerts, alert)
+	}
+
+	logger.Info("Sending alerts to the notifier of the parent organization", "parentOrg", parentOrgID, "count", len(forwarded.PostableAlerts))
+	if err := n.PutAlerts(forwarded); err != nil {
+		logger.Error("Failed to put alerts in the notifier of the parent organization", "parentOrg", parentOrgID, "count", len(forwarded.PostableAlerts), "error", err)
+	}
 }
 
 // AlertmanagersFor returns all the discovered Alertmanager(s) for a particular organization.
@@ -355,6 +355,72 @@ func TestChangingAlertmanagersChoice(t *testing.T) {
 	require.Len(t, actualAlerts, len(expected))
 }
 
+func TestSendingToParentOrg(t *testing.T) {
+	childKey := models.GenerateRuleKey(2)
+	parentOrgID := int64(1)
+
+	fakeAdminConfigStore := &store.AdminConfigurationStoreMock{}
+	mockedGetAdminConfigurations := fakeAdminConfigStore.EXPECT().GetAdminConfigurations()
+
+	mockedClock := clock.NewMock()
+	mockedClock.Set(time.Now())
+
+	moa := createMultiOrgAlertmanager(t, []int64{parentOrgID, childKey.OrgID})
+
+	appUrl := &url.URL{
+		Scheme: "http",
+		Host:   "localhost",
+	}
+	alertsRouter := NewAlertsRouter(moa, fakeAdminConfigStore, mockedClock, appUrl, map[int64]struct{}{},
+		10*time.Minute, &fake_ds.FakeDataSourceService{}, fake_secrets.NewFakeSecretsService())
+
+	getAlerts := func(t *testing.T, orgID int64) []*models2.GettableAlert {
+		t.Helper()
+		am, err := moa.AlertmanagerFor(orgID)
+		require.NoError(t, err)
+		alerts, err := am.GetAlerts(true, true, true, nil, "")
+		require.NoError(t, err)
+		return alerts
+	}
+
+	alert := generatePostableAlert(t, mockedClock)
+	alerts := definitions.PostableAlerts{PostableAlerts: []models2.PostableAlert{alert}}
+
+	t.Run("alerts are not forwarded without a parent organization", func(t *testing.T) {
+		mockedGetAdminConfigurations.Return([]*models.AdminConfiguration{
+			{OrgID: childKey.OrgID, SendAlertsTo: models.InternalAlertmanager},
+		}, nil)
+		require.NoError(t, alertsRouter.SyncAndApplyConfigFromDatabase())
+
+		alertsRouter.Send(childKey, alerts)
+		require.Len(t, getAlerts(t, childKey.OrgID), 1)
+		require.Empty(t, getAlerts(t, parentOrgID))
+	})
+
+	t.Run("alerts are forwarded to the parent organization with the org label", func(t *testing.T) {
+		mockedGetAdminConfigurations.Return([]*models.AdminConfiguration{
+			{OrgID: childKey.OrgID, SendAlertsTo: models.InternalAlertmanager, ParentOrgID: parentOrgID},
+		}, nil)
+		require.NoError(t, alertsRouter.SyncAndApplyConfigFromDatabase())
+
+		alertsRouter.Send(childKey, alerts)
+
+		childAlerts := getAlerts(t, childKey.OrgID)
+		require.Len(t, childAlerts, 1)
+		require.NotContains(t, childAlerts[0].Labels, models.OrgIDLabel)
+
+		parentAlerts := getAlerts(t, parentOrgID)
+		require.Len(t, parentAlerts, 1)
+		require.Equal(t, "2", parentAlerts[0].Labels[models.OrgIDLabel])
+		for k, v := range alert.Labels {
+			require.Equal(t, v, parentAlerts[0].Labels[k])
+		}
+		// The alerts of the parent organization are not forwarded to its children.
+		alertsRouter.Send(models.GenerateRuleKey(parentOrgID), alerts)
+		require.Len(t, getAlerts(t, childKey.OrgID), 1)
+	})
+}
+
 func assertAlertmanagersStatusForOrg(t *testing.T, alertsRouter *AlertsRouter, orgID int64, active, dropped int) {
 	t.Helper()
 	require.Eventuallyf(t, func() bool {
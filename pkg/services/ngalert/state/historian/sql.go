@@ -2,6 +2,9 @@ package historian
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -9,24 +12,163 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
+// StateHistoryStore is the database interface used by the SQL state history backend.
+type StateHistoryStore interface {
+	SaveStateHistory(ctx context.Context, entries []models.StateHistoryEntry) error
+	QueryStateHistory(ctx context.Context, query models.HistoryQuery) ([]models.StateHistoryEntry, error)
+}
+
+// SqlBackend is an implementation of state.Historian that stores state transitions in the Grafana database.
 type SqlBackend struct {
-	log log.Logger
+	store StateHistoryStore
+	log   log.Logger
 }
 
-func NewSqlBackend() *SqlBackend {
+func NewSqlBackend(store StateHistoryStore) *SqlBackend {
 	return &SqlBackend{
-		log: log.New("ngalert.state.historian"),
+		store: store,
+		log:   log.New("ngalert.state.historian", "backend", "sql"),
 	}
 }
 
-func (h *SqlBackend) RecordStatesAsync(ctx context.Context, _ history_model.RuleMeta, _ []state.StateTransition) <-chan error {
-	errCh := make(chan error)
-	close(errCh)
+// RecordStatesAsync writes a number of state transitions for a given rule to state history.
+// All transitions of a single evaluation are written as a batch in one transaction.
+func (h *SqlBackend) RecordStatesAsync(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	// Build entries before starting goroutine, to make sure all data is copied and won't mutate underneath us.
+	entries := h.buildEntries(rule, states, logger)
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+	go func() {
+		defer close(errCh)
+		if err := h.store.SaveStateHistory(ctx, entries); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "count", len(entries))
+	}()
 	return errCh
 }
 
+// QueryStates returns the state transitions that match the query as a single frame.
+// The labels of each transition are returned as a JSON string in the labels field of the frame.
 func (h *SqlBackend) QueryStates(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
-	return data.NewFrame("states"), nil
+	logger := h.log.FromContext(ctx)
+	entries, err := h.store.QueryStateHistory(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// We represent state history as six vectors:
+	//   1. `time` - when the transition happened
+	//   2. `ruleUID` - the UID of the rule that transitioned
+	//   3. `prev` - the previous state and reason
+	//   4. `next` - the next state and reason
+	//   5. `data` - a JSON string, containing the values, error or no-data marker of the evaluation
+	//   6. `labels` - a JSON string, containing the labels of the alert instance
+	times := make([]time.Time, 0, len(entries))
+	ruleUIDs := make([]string, 0, len(entries))
+	prevStates := make([]string, 0, len(entries))
+	nextStates := make([]string, 0, len(entries))
+	values := make([]string, 0, len(entries))
+	lbls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		var il models.InstanceLabels
+		if err := il.FromDB([]byte(entry.Labels)); err != nil {
+			logger.Error("State history entry has unparseable labels, skipping", "id", entry.ID, "error", err)
+			continue
+		}
+		labels := data.Labels(il)
+		// The store matches labels case-insensitively in some databases.
+		if !labels.Contains(query.Labels) {
+			continue
+		}
+		l, err := json.Marshal(labels)
+		if err != nil {
+			logger.Error("Failed to encode state history labels, skipping", "id", entry.ID, "error", err)
+			continue
+		}
+
+		times = append(times, time.UnixMilli(entry.At))
+		ruleUIDs = append(ruleUIDs, entry.RuleUID)
+		prevStates = append(prevStates, entry.PreviousState)
+		nextStates = append(nextStates, entry.CurrentState)
+		values = append(values, entry.Data)
+		lbls = append(lbls, string(l))
+	}
+
+	frame := data.NewFrame("states")
+	frame.Fields = append(frame.Fields, data.NewField("time", nil, times))
+	frame.Fields = append(frame.Fields, data.NewField("ruleUID", nil, ruleUIDs))
+	frame.Fields = append(frame.Fields, data.NewField("labels", nil, lbls))
+	frame.Fields = append(frame.Fields, data.NewField("prev", nil, prevStates))
+	frame.Fields = append(frame.Fields, data.NewField("next", nil, nextStates))
+	frame.Fields = append(frame.Fields, data.NewField("data", nil, values))
+
+	return frame, nil
+}
+
+func (h *SqlBackend) buildEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) []models.StateHistoryEntry {
+	entries := make([]models.StateHistoryEntry, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		il := models.InstanceLabels(removePrivateLabels(state.State.Labels))
+		labels, hash, err := il.StringAndHash()
+		if err != nil {
+			logger.Error("Failed to encode labels of state history record, skipping", "error", err)
+			continue
+		}
+		values, err := valuesAsDataBlob(state.State).MarshalJSON()
+		if err != nil {
+			logger.Error("Failed to encode values of state history record, skipping", "error", err)
+			continue
+		}
+
+		entries = append(entries, models.StateHistoryEntry{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			RuleGroup:     rule.Group,
+			NamespaceUID:  rule.NamespaceUID,
+			Labels:        labels,
+			LabelsHash:    hash,
+			PreviousState: state.PreviousFormatted(),
+			CurrentState:  state.Formatted(),
+			Data:          string(values),
+			At:            state.State.LastEvaluationTime.UnixMilli(),
+		})
+	}
+	return entries
+}
+
+// DeleteExpiredService is a service to delete state history that is older than the configured retention.
+type DeleteExpiredService struct {
+	retention time.Duration
+	store     store.StateHistoryStore
+}
+
+func ProvideDeleteExpiredService(cfg *setting.Cfg, store *store.DBstore) *DeleteExpiredService {
+	return &DeleteExpiredService{
+		retention: cfg.UnifiedAlerting.StateHistory.SQLRetention,
+		store:     store,
+	}
+}
+
+// DeleteExpired deletes state history entries older than the retention. It returns the number of deleted entries.
+// A retention of zero or less keeps state history forever.
+func (s *DeleteExpiredService) DeleteExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	return s.store.DeleteStateHistoryOlderThan(ctx, store.TimeNow().Add(-s.retention))
 }
@@ -0,0 +1,112 @@
+package historian
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+func TestSqlBackend(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	rule := history_model.RuleMeta{OrgID: 1, UID: "rule-uid", Group: "group", NamespaceUID: "folder"}
+
+	t.Run("records only changed states", func(t *testing.T) {
+		store := &fakeStateHistoryStore{}
+		sql := NewSqlBackend(store)
+		states := []state.StateTransition{
+			sqlTestTransition(data.Labels{"a": "b"}, eval.Normal, eval.Alerting, now),
+			sqlTestTransition(data.Labels{"a": "c"}, eval.Normal, eval.Normal, now),
+		}
+
+		err := <-sql.RecordStatesAsync(context.Background(), rule, states)
+
+		require.NoError(t, err)
+		require.Len(t, store.entries, 1)
+		entry := store.entries[0]
+		require.Equal(t, int64(1), entry.OrgID)
+		require.Equal(t, "rule-uid", entry.RuleUID)
+		require.Equal(t, "group", entry.RuleGroup)
+		require.Equal(t, "folder", entry.NamespaceUID)
+		require.Equal(t, "Normal", entry.PreviousState)
+		require.Equal(t, "Alerting", entry.CurrentState)
+		require.Equal(t, now.UnixMilli(), entry.At)
+	})
+
+	t.Run("private labels are not recorded", func(t *testing.T) {
+		store := &fakeStateHistoryStore{}
+		sql := NewSqlBackend(store)
+		states := []state.StateTransition{
+			sqlTestTransition(data.Labels{"a": "b", "__private__": "x"}, eval.Normal, eval.Alerting, now),
+		}
+
+		err := <-sql.RecordStatesAsync(context.Background(), rule, states)
+
+		require.NoError(t, err)
+		require.Len(t, store.entries, 1)
+		require.NotContains(t, store.entries[0].Labels, "__private__")
+	})
+
+	t.Run("query filters by labels", func(t *testing.T) {
+		store := &fakeStateHistoryStore{}
+		sql := NewSqlBackend(store)
+		states := []state.StateTransition{
+			sqlTestTransition(data.Labels{"a": "b", "c": "d"}, eval.Normal, eval.Alerting, now),
+			sqlTestTransition(data.Labels{"a": "b", "c": "e"}, eval.Normal, eval.Alerting, now),
+			sqlTestTransition(data.Labels{"a": "x"}, eval.Normal, eval.Alerting, now),
+		}
+		require.NoError(t, <-sql.RecordStatesAsync(context.Background(), rule, states))
+
+		frame, err := sql.QueryStates(context.Background(), models.HistoryQuery{
+			OrgID:   1,
+			RuleUID: "rule-uid",
+			Labels:  map[string]string{"a": "b"},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 6)
+		for _, f := range frame.Fields {
+			require.Equal(t, 2, f.Len())
+		}
+		require.Equal(t, now, frame.Fields[0].At(0))
+		require.Equal(t, "rule-uid", frame.Fields[1].At(0))
+	})
+}
+
+func sqlTestTransition(labels data.Labels, from, to eval.State, at time.Time) state.StateTransition {
+	return state.StateTransition{
+		State: &state.State{
+			State:              to,
+			Labels:             labels,
+			LastEvaluationTime: at,
+		},
+		PreviousState: from,
+	}
+}
+
+type fakeStateHistoryStore struct {
+	entries []models.StateHistoryEntry
+}
+
+func (f *fakeStateHistoryStore) SaveStateHistory(_ context.Context, entries []models.StateHistoryEntry) error {
+	f.entries = append(f.entries, entries...)
+	return nil
+}
+
+func (f *fakeStateHistoryStore) QueryStateHistory(_ context.Context, query models.HistoryQuery) ([]models.StateHistoryEntry, error) {
+	var result []models.StateHistoryEntry
+	for _, e := range f.entries {
+		if e.OrgID != query.OrgID || (query.RuleUID != "" && e.RuleUID != query.RuleUID) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
@@ -136,24 +136,8 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("Error getting cacheId for entry", "error", err)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-			}
+			state := st.stateFromInstance(entry, ruleForEntry)
+			rulesStates.states[state.CacheID] = state
 			statesCount++
 		}
 	}
@@ -161,6 +145,57 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// WarmRule loads the state of a single rule from the instance store and replaces the cached state of the rule.
+// It is used when this instance takes over the evaluation of the rule from another instance.
+func (st *Manager) WarmRule(ctx context.Context, rule *ngModels.AlertRule) {
+	if st.instanceStore == nil {
+		return
+	}
+	logger := st.log.New(rule.GetKey().LogContext()...)
+	cmd := ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	}
+	if err := st.instanceStore.ListAlertInstances(ctx, &cmd); err != nil {
+		logger.Error("Unable to fetch previous state of the rule", "error", err)
+		return
+	}
+	states := &ruleStates{states: make(map[string]*State, len(cmd.Result))}
+	for _, entry := range cmd.Result {
+		state := st.stateFromInstance(entry, rule)
+		states.states[state.CacheID] = state
+	}
+	st.cache.setRuleStates(rule.GetKey(), states)
+	logger.Debug("State of the rule has been loaded", "states", len(states.states))
+}
+
+// ForgetRule removes the state of the rule from the cache without deleting it from the instance store
+// and without sending any notifications. It is used when another instance takes over the evaluation of the rule.
+func (st *Manager) ForgetRule(key ngModels.AlertRuleKey) {
+	st.cache.removeByRuleUID(key.OrgID, key.UID)
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule) *State {
+	cacheID, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("Error getting cacheId for entry", "error", err)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               map[string]string(entry.Labels),
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		KeepFiringSince:      entry.KeepFiringSince,
+		Annotations:          rule.Annotations,
+	}
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -370,6 +405,7 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 			LastEvalTime:      s.LastEvaluationTime,
 			CurrentStateSince: s.StartsAt,
 			CurrentStateEnd:   s.EndsAt,
+			KeepFiringSince:   s.KeepFiringSince,
 		}
 		instances = append(instances, fields)
 	}
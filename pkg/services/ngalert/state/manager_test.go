@@ -67,6 +67,7 @@ func TestWarmStateCache(t *testing.T) {
 			StartsAt:           evaluationTime.Add(-1 * time.Minute),
 			EndsAt:             evaluationTime.Add(1 * time.Minute),
 			LastEvaluationTime: evaluationTime,
+			KeepFiringSince:    evaluationTime.Add(-30 * time.Second),
 			Annotations:        map[string]string{"testAnnoKey": "testAnnoValue"},
 		},
 		{
@@ -140,6 +141,7 @@ func TestWarmStateCache(t *testing.T) {
 		LastEvalTime:      evaluationTime,
 		CurrentStateSince: evaluationTime.Add(-1 * time.Minute),
 		CurrentStateEnd:   evaluationTime.Add(1 * time.Minute),
+		KeepFiringSince:   evaluationTime.Add(-30 * time.Second),
 		Labels:            labels,
 	}
 
@@ -212,6 +214,58 @@ func TestWarmStateCache(t *testing.T) {
 	})
 }
 
+func TestWarmRule(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+
+	labels := models.InstanceLabels{"test1": "testValue1"}
+	cacheID, hash, _ := labels.StringAndHash()
+	instance := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		CurrentState:      models.InstanceStateFiring,
+		LastEvalTime:      evaluationTime,
+		CurrentStateSince: evaluationTime.Add(-1 * time.Minute),
+		CurrentStateEnd:   evaluationTime.Add(1 * time.Minute),
+		KeepFiringSince:   evaluationTime.Add(-30 * time.Second),
+		Labels:            labels,
+	}
+	require.NoError(t, dbstore.SaveAlertInstances(ctx, instance))
+
+	cfg := state.ManagerCfg{
+		Metrics:       testMetrics.GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: dbstore,
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+	}
+	st := state.NewManager(cfg)
+
+	t.Run("state of the rule is restored when the rule is handed over", func(t *testing.T) {
+		st.WarmRule(ctx, rule)
+
+		s := st.Get(rule.OrgID, rule.UID, cacheID)
+		require.NotNil(t, s)
+		require.Equal(t, eval.Alerting, s.State)
+		require.True(t, instance.CurrentStateSince.Equal(s.StartsAt))
+		require.True(t, instance.KeepFiringSince.Equal(s.KeepFiringSince))
+	})
+
+	t.Run("state of the rule is forgotten when the rule is handed over to another instance", func(t *testing.T) {
+		st.ForgetRule(rule.GetKey())
+		require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+	})
+}
+
 func TestDashboardAnnotations(t *testing.T) {
 	evaluationTime, err := time.Parse("2006-01-02", "2022-01-01")
 	require.NoError(t, err)
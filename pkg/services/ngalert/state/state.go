@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	alertingModels "github.com/grafana/alerting/alerting/models"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prometheusModel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -58,6 +60,11 @@ type State struct {
 	// conditions.
 	Values map[string]float64
 
+	// KeepFiringSince is the time of the first evaluation that did not fire while the state was kept
+	// Alerting due to the keep_firing_for setting of the alert rule. It is zero if the state is not
+	// being kept firing.
+	KeepFiringSince time.Time
+
 	StartsAt             time.Time
 	EndsAt               time.Time
 	LastSentAt           time.Time
@@ -89,6 +96,7 @@ func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetPending the state to Pending. It changes both the start and end time.
@@ -98,6 +106,7 @@ func (a *State) SetPending(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetNoData sets the state to NoData. It changes both the start and end time.
@@ -107,6 +116,7 @@ func (a *State) SetNoData(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetError sets the state to Error. It changes both the start and end time.
@@ -116,6 +126,7 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = err
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetNormal sets the state to Normal. It changes both the start and end time.
@@ -125,6 +136,7 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // Resolve sets the State to Normal. It updates the StateReason, the end time, and sets Resolved to true.
@@ -184,9 +196,12 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger) {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	if state.State == eval.Normal {
 		logger.Debug("Keeping state", "state", state.State)
+	} else if state.State == eval.Alerting && rule.KeepFiringFor > 0 && keepFiring(state, rule, result.EvaluatedAt) {
+		logger.Debug("Keeping state", "state", state.State, "keep_firing_since", state.KeepFiringSince)
+		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
 	} else {
 		logger.Debug("Changing state", "previous_state", state.State, "next_state", eval.Normal)
 		// Normal states have the same start and end timestamps
@@ -194,11 +209,21 @@ func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger
 	}
 }
 
+// keepFiring returns true if the state should be kept Alerting although the condition is no longer met.
+// The first such evaluation starts the keep_firing_for period.
+func keepFiring(state *State, rule *models.AlertRule, evaluatedAt time.Time) bool {
+	if state.KeepFiringSince.IsZero() {
+		state.KeepFiringSince = evaluatedAt
+	}
+	return evaluatedAt.Sub(state.KeepFiringSince) < rule.KeepFiringFor
+}
+
 func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	switch state.State {
 	case eval.Alerting:
 		logger.Debug("Keeping state", "state", state.State)
 		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		state.KeepFiringSince = time.Time{}
 	case eval.Pending:
 		// If the previous state is Pending then check if the For duration has been observed
 		if result.EvaluatedAt.Sub(state.StartsAt) >= rule.For {
@@ -389,3 +414,18 @@ func FormatStateAndReason(state eval.State, reason string) string {
 	}
 	return s
 }
+
+// GetRuleExtraLabels returns the labels that are added to all alert instances of the rule. The title of the folder is
+// added only if includeFolder is true.
+func GetRuleExtraLabels(rule *models.AlertRule, folderTitle string, includeFolder bool) data.Labels {
+	extraLabels := make(data.Labels, 4)
+
+	extraLabels[alertingModels.NamespaceUIDLabel] = rule.NamespaceUID
+	extraLabels[prometheusModel.AlertNameLabel] = rule.Title
+	extraLabels[alertingModels.RuleUIDLabel] = rule.UID
+
+	if includeFolder {
+		extraLabels[models.FolderTitleLabel] = folderTitle
+	}
+	return extraLabels
+}
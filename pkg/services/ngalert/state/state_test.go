@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 	ptr "github.com/xorcare/pointer"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/screenshot"
@@ -263,6 +264,57 @@ func TestMaintain(t *testing.T) {
 	assert.Equal(t, now.Add(190*time.Second), s.EndsAt)
 }
 
+func TestKeepFiringFor(t *testing.T) {
+	mock := clock.NewMock()
+	now := mock.Now()
+	rule := &ngmodels.AlertRule{IntervalSeconds: 10, KeepFiringFor: 30 * time.Second}
+	normal := func(at time.Time) eval.Result {
+		return eval.Result{State: eval.Normal, EvaluatedAt: at}
+	}
+
+	t.Run("state is kept Alerting until keep_firing_for has elapsed", func(t *testing.T) {
+		s := State{State: eval.Alerting, StartsAt: now, EndsAt: now.Add(time.Minute)}
+
+		resultNormal(&s, rule, normal(now.Add(10*time.Second)), log.NewNopLogger())
+		assert.Equal(t, eval.Alerting, s.State)
+		assert.Equal(t, now.Add(10*time.Second), s.KeepFiringSince)
+		assert.Equal(t, now, s.StartsAt)
+
+		resultNormal(&s, rule, normal(now.Add(30*time.Second)), log.NewNopLogger())
+		assert.Equal(t, eval.Alerting, s.State)
+		assert.Equal(t, now.Add(10*time.Second), s.KeepFiringSince)
+
+		resultNormal(&s, rule, normal(now.Add(40*time.Second)), log.NewNopLogger())
+		assert.Equal(t, eval.Normal, s.State)
+		assert.True(t, s.KeepFiringSince.IsZero())
+	})
+
+	t.Run("firing again resets keep_firing_for", func(t *testing.T) {
+		s := State{State: eval.Alerting, StartsAt: now, EndsAt: now.Add(time.Minute)}
+
+		resultNormal(&s, rule, normal(now.Add(10*time.Second)), log.NewNopLogger())
+		resultAlerting(&s, rule, eval.Result{State: eval.Alerting, EvaluatedAt: now.Add(20 * time.Second)}, log.NewNopLogger())
+		assert.Equal(t, eval.Alerting, s.State)
+		assert.True(t, s.KeepFiringSince.IsZero())
+
+		resultNormal(&s, rule, normal(now.Add(40*time.Second)), log.NewNopLogger())
+		assert.Equal(t, eval.Alerting, s.State)
+		assert.Equal(t, now.Add(40*time.Second), s.KeepFiringSince)
+	})
+
+	t.Run("state is resolved immediately without keep_firing_for", func(t *testing.T) {
+		s := State{State: eval.Alerting, StartsAt: now, EndsAt: now.Add(time.Minute)}
+		resultNormal(&s, &ngmodels.AlertRule{IntervalSeconds: 10}, normal(now.Add(10*time.Second)), log.NewNopLogger())
+		assert.Equal(t, eval.Normal, s.State)
+	})
+
+	t.Run("pending state is not kept", func(t *testing.T) {
+		s := State{State: eval.Pending, StartsAt: now, EndsAt: now.Add(time.Minute)}
+		resultNormal(&s, rule, normal(now.Add(10*time.Second)), log.NewNopLogger())
+		assert.Equal(t, eval.Normal, s.State)
+	})
+}
+
 func TestEnd(t *testing.T) {
 	evaluationTime, _ := time.Parse("2006-01-02", "2021-03-25")
 	testCases := []struct {
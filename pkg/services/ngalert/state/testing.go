@@ -63,9 +63,12 @@ func (f *FakeRuleReader) ListAlertRules(_ context.Context, q *models.ListAlertRu
 
 type FakeHistorian struct {
 	StateTransitions []StateTransition
+	mtx              sync.Mutex
 }
 
 func (f *FakeHistorian) RecordStatesAsync(ctx context.Context, rule history_model.RuleMeta, states []StateTransition) <-chan error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
 	f.StateTransitions = append(f.StateTransitions, states...)
 	errCh := make(chan error)
 	close(errCh)
@@ -63,6 +63,12 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("deleted alert instances", "count", rows)
+
+		rows, err = sess.Table("alert_rule_template_instance").Where("org_id = ?", orgID).In("rule_uid", ruleUID).Delete(ngmodels.AlertRuleTemplateInstance{})
+		if err != nil {
+			return err
+		}
+		logger.Debug("deleted alert rule template instances", "count", rows)
 		return nil
 	})
 }
@@ -148,8 +154,11 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				NoDataState:      r.NoDataState,
 				ExecErrState:     r.ExecErrState,
 				For:              r.For,
+				KeepFiringFor:    r.KeepFiringFor,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				Record:           r.Record,
+				DependsOn:        r.DependsOn,
 			})
 		}
 		if len(newRules) > 0 {
@@ -190,7 +199,7 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				return err
 			}
 			// no way to update multiple rules at once
-			if updated, err := sess.ID(r.Existing.ID).AllCols().Update(r.New); err != nil || updated == 0 {
+			if updated, err := sess.ID(r.Existing.ID).AllCols().Update(&r.New); err != nil || updated == 0 {
 				if err != nil {
 					if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
 						return ngmodels.ErrAlertRuleUniqueConstraintViolation
@@ -216,8 +225,11 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				NoDataState:      r.New.NoDataState,
 				ExecErrState:     r.New.ExecErrState,
 				For:              r.New.For,
+				KeepFiringFor:    r.New.KeepFiringFor,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				Record:           r.New.Record,
+				DependsOn:        r.New.DependsOn,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -535,5 +547,9 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.Type() == ngmodels.RuleTypeRecording && (alertRule.Record.Metric == "" || alertRule.Record.From == "") {
+		return fmt.Errorf("%w: recording rule must specify both metric and from", ngmodels.ErrAlertRuleFailedValidation)
+	}
 	return nil
 }
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type AlertRuleTemplateStore interface {
+	// GetAlertRuleTemplates returns all alert rule templates of the organization ordered by UID.
+	GetAlertRuleTemplates(ctx context.Context, orgID int64) ([]models.AlertRuleTemplate, error)
+
+	// GetAlertRuleTemplate returns the alert rule template with the UID. It returns ErrAlertRuleTemplateNotFound
+	// if the organization does not have an alert rule template with the UID.
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error)
+
+	// InsertAlertRuleTemplate saves a new alert rule template.
+	InsertAlertRuleTemplate(ctx context.Context, template *models.AlertRuleTemplate) error
+
+	// UpdateAlertRuleTemplate replaces the alert rule template with the same organization and UID.
+	// It returns ErrAlertRuleTemplateNotFound if it does not exist.
+	UpdateAlertRuleTemplate(ctx context.Context, template *models.AlertRuleTemplate) error
+
+	// DeleteAlertRuleTemplate deletes the alert rule template with the UID and unlinks the alert rules that
+	// were created from it. Deleting an alert rule template that does not exist is not an error.
+	DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error
+
+	// GetAlertRuleTemplateInstances returns the links of the alert rules created from the template ordered by rule UID.
+	GetAlertRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]models.AlertRuleTemplateInstance, error)
+
+	// InsertAlertRuleTemplateInstance links an alert rule to a template.
+	InsertAlertRuleTemplateInstance(ctx context.Context, instance *models.AlertRuleTemplateInstance) error
+
+	// UpdateAlertRuleTemplateInstance replaces the link of the alert rule with the same organization and rule UID.
+	UpdateAlertRuleTemplateInstance(ctx context.Context, instance *models.AlertRuleTemplateInstance) error
+
+	// DeleteAlertRuleTemplateInstance unlinks the alert rule from its template.
+	DeleteAlertRuleTemplateInstance(ctx context.Context, orgID int64, ruleUID string) error
+}
+
+func (st DBstore) GetAlertRuleTemplates(ctx context.Context, orgID int64) ([]models.AlertRuleTemplate, error) {
+	var templates []models.AlertRuleTemplate
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&templates)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get alert rule templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (st DBstore) GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	var template models.AlertRuleTemplate
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&template)
+		if err != nil {
+			return fmt.Errorf("failed to get alert rule template: %w", err)
+		} else if !exists {
+			return models.ErrAlertRuleTemplateNotFound
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (st DBstore) InsertAlertRuleTemplate(ctx context.Context, template *models.AlertRuleTemplate) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		template.Updated = TimeNow().UTC()
+		if _, err := sess.Insert(template); err != nil {
+			return fmt.Errorf("failed to insert alert rule template: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) UpdateAlertRuleTemplate(ctx context.Context, template *models.AlertRuleTemplate) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		template.Updated = TimeNow().UTC()
+		affected, err := sess.Where("org_id = ? AND uid = ?", template.OrgID, template.UID).AllCols().Omit("id").Update(template)
+		if err != nil {
+			return fmt.Errorf("failed to update alert rule template: %w", err)
+		}
+		if affected == 0 {
+			return models.ErrAlertRuleTemplateNotFound
+		}
+		return nil
+	})
+}
+
+func (st DBstore) DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&models.AlertRuleTemplate{}); err != nil {
+			return fmt.Errorf("failed to delete alert rule template: %w", err)
+		}
+		if _, err := sess.Where("org_id = ? AND template_uid = ?", orgID, uid).Delete(&models.AlertRuleTemplateInstance{}); err != nil {
+			return fmt.Errorf("failed to delete alert rule template instances: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetAlertRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]models.AlertRuleTemplateInstance, error) {
+	var instances []models.AlertRuleTemplateInstance
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND template_uid = ?", orgID, templateUID).Asc("rule_uid").Find(&instances)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get alert rule template instances: %w", err)
+	}
+	return instances, nil
+}
+
+func (st DBstore) InsertAlertRuleTemplateInstance(ctx context.Context, instance *models.AlertRuleTemplateInstance) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		instance.Updated = TimeNow().UTC()
+		if _, err := sess.Insert(instance); err != nil {
+			return fmt.Errorf("failed to insert alert rule template instance: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) UpdateAlertRuleTemplateInstance(ctx context.Context, instance *models.AlertRuleTemplateInstance) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		instance.Updated = TimeNow().UTC()
+		if _, err := sess.Where("org_id = ? AND rule_uid = ?", instance.OrgID, instance.RuleUID).AllCols().Omit("id").Update(instance); err != nil {
+			return fmt.Errorf("failed to update alert rule template instance: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) DeleteAlertRuleTemplateInstance(ctx context.Context, orgID int64, ruleUID string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND rule_uid = ?", orgID, ruleUID).Delete(&models.AlertRuleTemplateInstance{}); err != nil {
+			return fmt.Errorf("failed to delete alert rule template instance: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,124 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationAlertRuleTemplates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	template := func(orgID int64, uid string) *models.AlertRuleTemplate {
+		return &models.AlertRuleTemplate{
+			OrgID:      orgID,
+			UID:        uid,
+			Title:      "Disk full",
+			Parameters: `[{"name":"threshold","type":"number"}]`,
+			Rule:       `{"title":"Disk full"}`,
+			Version:    1,
+		}
+	}
+	instance := func(orgID int64, templateUID, ruleUID string) *models.AlertRuleTemplateInstance {
+		return &models.AlertRuleTemplateInstance{
+			OrgID:           orgID,
+			TemplateUID:     templateUID,
+			RuleUID:         ruleUID,
+			TemplateVersion: 1,
+			Values:          `{"threshold":"90"}`,
+		}
+	}
+
+	require.NoError(t, dbstore.InsertAlertRuleTemplate(ctx, template(1, "b")))
+	require.NoError(t, dbstore.InsertAlertRuleTemplate(ctx, template(1, "a")))
+	require.NoError(t, dbstore.InsertAlertRuleTemplate(ctx, template(2, "a")))
+
+	t.Run("UIDs are unique per organization", func(t *testing.T) {
+		require.Error(t, dbstore.InsertAlertRuleTemplate(ctx, template(1, "a")))
+	})
+
+	t.Run("get by organization", func(t *testing.T) {
+		result, err := dbstore.GetAlertRuleTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "a", result[0].UID)
+		assert.Equal(t, "b", result[1].UID)
+	})
+
+	t.Run("update replaces the template", func(t *testing.T) {
+		updated := template(1, "a")
+		updated.Title = "Disk almost full"
+		updated.Version = 2
+		require.NoError(t, dbstore.UpdateAlertRuleTemplate(ctx, updated))
+
+		result, err := dbstore.GetAlertRuleTemplate(ctx, 1, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "Disk almost full", result.Title)
+		assert.Equal(t, int64(2), result.Version)
+
+		other, err := dbstore.GetAlertRuleTemplate(ctx, 2, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "Disk full", other.Title)
+	})
+
+	t.Run("update and get return ErrAlertRuleTemplateNotFound for unknown templates", func(t *testing.T) {
+		require.ErrorIs(t, dbstore.UpdateAlertRuleTemplate(ctx, template(1, "unknown")), models.ErrAlertRuleTemplateNotFound)
+		_, err := dbstore.GetAlertRuleTemplate(ctx, 1, "unknown")
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+	})
+
+	t.Run("instances are linked to templates", func(t *testing.T) {
+		require.NoError(t, dbstore.InsertAlertRuleTemplateInstance(ctx, instance(1, "b", "rule-2")))
+		require.NoError(t, dbstore.InsertAlertRuleTemplateInstance(ctx, instance(1, "b", "rule-1")))
+		require.NoError(t, dbstore.InsertAlertRuleTemplateInstance(ctx, instance(2, "a", "rule-1")))
+
+		// A rule can only be created from one template.
+		require.Error(t, dbstore.InsertAlertRuleTemplateInstance(ctx, instance(1, "a", "rule-1")))
+
+		result, err := dbstore.GetAlertRuleTemplateInstances(ctx, 1, "b")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "rule-1", result[0].RuleUID)
+		assert.Equal(t, "rule-2", result[1].RuleUID)
+
+		updated := instance(1, "b", "rule-1")
+		updated.TemplateVersion = 2
+		require.NoError(t, dbstore.UpdateAlertRuleTemplateInstance(ctx, updated))
+		result, err = dbstore.GetAlertRuleTemplateInstances(ctx, 1, "b")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result[0].TemplateVersion)
+
+		require.NoError(t, dbstore.DeleteAlertRuleTemplateInstance(ctx, 1, "rule-2"))
+		result, err = dbstore.GetAlertRuleTemplateInstances(ctx, 1, "b")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+
+	t.Run("deleting alert rules unlinks them", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteAlertRulesByUID(ctx, 2, "rule-1"))
+		result, err := dbstore.GetAlertRuleTemplateInstances(ctx, 2, "a")
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("delete removes the template and its instances", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteAlertRuleTemplate(ctx, 1, "b"))
+		require.NoError(t, dbstore.DeleteAlertRuleTemplate(ctx, 1, "unknown"))
+
+		_, err := dbstore.GetAlertRuleTemplate(ctx, 1, "b")
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+		result, err := dbstore.GetAlertRuleTemplateInstances(ctx, 1, "b")
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -38,9 +39,10 @@ func BenchmarkAlertInstanceOperations(b *testing.B) {
 				RuleUID:    alertRule.UID,
 				LabelsHash: labelsHash,
 			},
-			CurrentState:  models.InstanceStateFiring,
-			CurrentReason: string(models.InstanceStateError),
-			Labels:        labels,
+			CurrentState:    models.InstanceStateFiring,
+			CurrentReason:   string(models.InstanceStateError),
+			Labels:          labels,
+			KeepFiringSince: time.Unix(1700000000, 0),
 		}
 		instances = append(instances, instance)
 		keys = append(keys, instance.AlertInstanceKey)
@@ -179,6 +181,7 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, alertRule1.OrgID, listCmd.Result[0].RuleOrgID)
 		require.Equal(t, alertRule1.UID, listCmd.Result[0].RuleUID)
 		require.Equal(t, instance.CurrentReason, listCmd.Result[0].CurrentReason)
+		require.Equal(t, instance.KeepFiringSince.Unix(), listCmd.Result[0].KeepFiringSince.Unix())
 	})
 
 	t.Run("can save and read new alert instance with no labels", func(t *testing.T) {
@@ -208,6 +211,7 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, alertRule2.OrgID, listCmd.Result[0].RuleOrgID)
 		require.Equal(t, alertRule2.UID, listCmd.Result[0].RuleUID)
 		require.Equal(t, instance.Labels, listCmd.Result[0].Labels)
+		require.True(t, listCmd.Result[0].KeepFiringSince.IsZero())
 	})
 
 	t.Run("can save two instances with same org_id, uid and different labels", func(t *testing.T) {
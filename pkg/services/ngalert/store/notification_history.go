@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type NotificationHistoryStore interface {
+	// SaveNotificationHistory saves a single notification attempt.
+	SaveNotificationHistory(ctx context.Context, entry *models.NotificationHistoryEntry) error
+
+	// QueryNotificationHistory returns the notification attempts that match the query, newest first.
+	QueryNotificationHistory(ctx context.Context, query models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error)
+
+	// DeleteNotificationHistoryOlderThan deletes notification attempts that started before the given time.
+	// It returns the number of deleted attempts or an error.
+	DeleteNotificationHistoryOlderThan(ctx context.Context, t time.Time) (int64, error)
+}
+
+func (st DBstore) SaveNotificationHistory(ctx context.Context, entry *models.NotificationHistoryEntry) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(entry); err != nil {
+			return fmt.Errorf("failed to insert notification history: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) QueryNotificationHistory(ctx context.Context, query models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	var entries []models.NotificationHistoryEntry
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.Integration != "" {
+			q = q.And("integration = ?", query.Integration)
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", query.Status)
+		}
+		if query.GroupKey != "" {
+			q = q.And("group_key = ?", query.GroupKey)
+		}
+		if !query.From.IsZero() {
+			q = q.And("at >= ?", query.From.UnixMilli())
+		}
+		if !query.To.IsZero() {
+			q = q.And("at <= ?", query.To.UnixMilli())
+		}
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Desc("at", "id").Find(&entries)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification history: %w", err)
+	}
+	return entries, nil
+}
+
+func (st DBstore) DeleteNotificationHistoryOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	var n int64
+	if err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("at < ?", t.UnixMilli()).Delete(&models.NotificationHistoryEntry{})
+		if err != nil {
+			return fmt.Errorf("failed to delete old notification history: %w", err)
+		}
+		n = rows
+		return nil
+	}); err != nil {
+		return -1, err
+	}
+	return n, nil
+}
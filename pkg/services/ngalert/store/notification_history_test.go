@@ -0,0 +1,78 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Now()
+	save := func(orgID int64, receiver, status string, at time.Time) {
+		require.NoError(t, dbstore.SaveNotificationHistory(ctx, &models.NotificationHistoryEntry{
+			OrgID:        orgID,
+			Receiver:     receiver,
+			Integration:  "webhook",
+			GroupKey:     "{}:{}",
+			Fingerprints: "a,b",
+			Status:       status,
+			At:           at.UnixMilli(),
+		}))
+	}
+	for i := 0; i < 10; i++ {
+		save(1, "receiver-1", models.NotificationStatusSuccess, now.Add(-time.Duration(i)*time.Hour))
+		save(1, "receiver-2", models.NotificationStatusFailed, now.Add(-time.Duration(i)*time.Hour))
+		save(2, "receiver-1", models.NotificationStatusSuccess, now)
+	}
+
+	t.Run("query by receiver", func(t *testing.T) {
+		result, err := dbstore.QueryNotificationHistory(ctx, models.NotificationHistoryQuery{OrgID: 1, Receiver: "receiver-1"})
+		require.NoError(t, err)
+		assert.Len(t, result, 10)
+		// newest attempts come first
+		assert.Equal(t, now.UnixMilli(), result[0].At)
+	})
+
+	t.Run("query by status and time range", func(t *testing.T) {
+		result, err := dbstore.QueryNotificationHistory(ctx, models.NotificationHistoryQuery{
+			OrgID:  1,
+			Status: models.NotificationStatusFailed,
+			From:   now.Add(-150 * time.Minute),
+			To:     now,
+		})
+		require.NoError(t, err)
+		assert.Len(t, result, 3)
+		for _, e := range result {
+			assert.Equal(t, "receiver-2", e.Receiver)
+		}
+	})
+
+	t.Run("query with limit", func(t *testing.T) {
+		result, err := dbstore.QueryNotificationHistory(ctx, models.NotificationHistoryQuery{OrgID: 2, Limit: 4})
+		require.NoError(t, err)
+		assert.Len(t, result, 4)
+	})
+
+	t.Run("delete old entries", func(t *testing.T) {
+		deleted, err := dbstore.DeleteNotificationHistoryOlderThan(ctx, now.Add(-5*time.Hour-time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), deleted)
+
+		result, err := dbstore.QueryNotificationHistory(ctx, models.NotificationHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Len(t, result, 12)
+	})
+}
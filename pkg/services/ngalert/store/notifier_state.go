@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type NotifierStateStore interface {
+	// GetNotifierState returns the concatenated entries of the kind of state of the organization in the order
+	// they were written. It returns nil if there are no entries.
+	GetNotifierState(ctx context.Context, orgID int64, kind string) ([]byte, error)
+
+	// AppendNotifierState saves a change of the kind of state of the organization.
+	AppendNotifierState(ctx context.Context, orgID int64, kind string, data []byte) error
+
+	// GetLastNotifierStateID returns the ID of the newest entry of the kind of state of the organization,
+	// or 0 if there are no entries.
+	GetLastNotifierStateID(ctx context.Context, orgID int64, kind string) (int64, error)
+
+	// CompactNotifierState replaces the entries of the kind of state of the organization up to and including
+	// the entry with the given ID by the snapshot. Entries that were written later are kept.
+	// If the ID is 0 the snapshot is saved as the first entry.
+	CompactNotifierState(ctx context.Context, orgID int64, kind string, lastID int64, snapshot []byte) error
+
+	// GetNotifierStateOrgIDs returns the IDs of all organizations that have notifier state.
+	GetNotifierStateOrgIDs(ctx context.Context) ([]int64, error)
+
+	// DeleteNotifierState deletes all state of the organization.
+	DeleteNotifierState(ctx context.Context, orgID int64) error
+}
+
+func (st DBstore) GetNotifierState(ctx context.Context, orgID int64, kind string) ([]byte, error) {
+	var entries []models.NotifierStateEntry
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND kind = ?", orgID, kind).Asc("id").Find(&entries)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get notifier state: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	result := []byte{}
+	for _, e := range entries {
+		result = append(result, e.Data...)
+	}
+	return result, nil
+}
+
+func (st DBstore) AppendNotifierState(ctx context.Context, orgID int64, kind string, data []byte) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&models.NotifierStateEntry{OrgID: orgID, Kind: kind, Data: data}); err != nil {
+			return fmt.Errorf("failed to insert notifier state: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetLastNotifierStateID(ctx context.Context, orgID int64, kind string) (int64, error) {
+	var entry models.NotifierStateEntry
+	var found bool
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Cols("id").Where("org_id = ? AND kind = ?", orgID, kind).Desc("id").Get(&entry)
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to get notifier state: %w", err)
+	}
+	if !found {
+		return 0, nil
+	}
+	return entry.ID, nil
+}
+
+func (st DBstore) CompactNotifierState(ctx context.Context, orgID int64, kind string, lastID int64, snapshot []byte) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if lastID == 0 {
+			if _, err := sess.Insert(&models.NotifierStateEntry{OrgID: orgID, Kind: kind, Data: snapshot}); err != nil {
+				return fmt.Errorf("failed to insert notifier state: %w", err)
+			}
+			return nil
+		}
+		// The snapshot takes the place of the newest entry it contains, so that entries written after it was
+		// taken are still applied after it.
+		if _, err := sess.Where("org_id = ? AND kind = ? AND id < ?", orgID, kind, lastID).Delete(&models.NotifierStateEntry{}); err != nil {
+			return fmt.Errorf("failed to delete notifier state: %w", err)
+		}
+		if _, err := sess.Where("org_id = ? AND kind = ? AND id = ?", orgID, kind, lastID).Cols("data").Update(&models.NotifierStateEntry{Data: snapshot}); err != nil {
+			return fmt.Errorf("failed to update notifier state: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetNotifierStateOrgIDs(ctx context.Context) ([]int64, error) {
+	var orgIDs []int64
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table(&models.NotifierStateEntry{}).Distinct("org_id").Find(&orgIDs)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get organizations with notifier state: %w", err)
+	}
+	return orgIDs, nil
+}
+
+func (st DBstore) DeleteNotifierState(ctx context.Context, orgID int64) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ?", orgID).Delete(&models.NotifierStateEntry{}); err != nil {
+			return fmt.Errorf("failed to delete notifier state: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,79 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotifierState(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	t.Run("no state", func(t *testing.T) {
+		state, err := dbstore.GetNotifierState(ctx, 1, "silences")
+		require.NoError(t, err)
+		require.Nil(t, state)
+
+		lastID, err := dbstore.GetLastNotifierStateID(ctx, 1, "silences")
+		require.NoError(t, err)
+		require.Zero(t, lastID)
+	})
+
+	t.Run("entries are concatenated in the order they were written", func(t *testing.T) {
+		require.NoError(t, dbstore.AppendNotifierState(ctx, 1, "silences", []byte("a")))
+		require.NoError(t, dbstore.AppendNotifierState(ctx, 1, "notifications", []byte("x")))
+		require.NoError(t, dbstore.AppendNotifierState(ctx, 2, "silences", []byte("y")))
+		require.NoError(t, dbstore.AppendNotifierState(ctx, 1, "silences", []byte("b")))
+
+		state, err := dbstore.GetNotifierState(ctx, 1, "silences")
+		require.NoError(t, err)
+		require.Equal(t, []byte("ab"), state)
+	})
+
+	t.Run("compaction keeps entries that were written later", func(t *testing.T) {
+		lastID, err := dbstore.GetLastNotifierStateID(ctx, 1, "silences")
+		require.NoError(t, err)
+		require.NoError(t, dbstore.AppendNotifierState(ctx, 1, "silences", []byte("c")))
+
+		require.NoError(t, dbstore.CompactNotifierState(ctx, 1, "silences", lastID, []byte("AB")))
+
+		state, err := dbstore.GetNotifierState(ctx, 1, "silences")
+		require.NoError(t, err)
+		require.Equal(t, []byte("ABc"), state)
+
+		other, err := dbstore.GetNotifierState(ctx, 1, "notifications")
+		require.NoError(t, err)
+		require.Equal(t, []byte("x"), other)
+	})
+
+	t.Run("compaction without entries saves the snapshot", func(t *testing.T) {
+		require.NoError(t, dbstore.CompactNotifierState(ctx, 3, "silences", 0, []byte("snapshot")))
+
+		state, err := dbstore.GetNotifierState(ctx, 3, "silences")
+		require.NoError(t, err)
+		require.Equal(t, []byte("snapshot"), state)
+	})
+
+	t.Run("delete by organization", func(t *testing.T) {
+		orgIDs, err := dbstore.GetNotifierStateOrgIDs(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []int64{1, 2, 3}, orgIDs)
+
+		require.NoError(t, dbstore.DeleteNotifierState(ctx, 1))
+
+		orgIDs, err = dbstore.GetNotifierStateOrgIDs(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []int64{2, 3}, orgIDs)
+		state, err := dbstore.GetNotifierState(ctx, 1, "silences")
+		require.NoError(t, err)
+		require.Nil(t, state)
+	})
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type RecurringSilenceStore interface {
+	// GetRecurringSilences returns all recurring silences of the organization ordered by UID.
+	GetRecurringSilences(ctx context.Context, orgID int64) ([]models.RecurringSilence, error)
+
+	// GetAllRecurringSilences returns the recurring silences of all organizations.
+	GetAllRecurringSilences(ctx context.Context) ([]models.RecurringSilence, error)
+
+	// GetRecurringSilence returns the recurring silence with the UID. It returns ErrRecurringSilenceNotFound
+	// if the organization does not have a recurring silence with the UID.
+	GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error)
+
+	// InsertRecurringSilence saves a new recurring silence.
+	InsertRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error
+
+	// UpdateRecurringSilence replaces the recurring silence with the same organization and UID.
+	// It returns ErrRecurringSilenceNotFound if it does not exist.
+	UpdateRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error
+
+	// DeleteRecurringSilence deletes the recurring silence with the UID. Deleting a recurring silence that
+	// does not exist is not an error.
+	DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error
+}
+
+func (st DBstore) GetRecurringSilences(ctx context.Context, orgID int64) ([]models.RecurringSilence, error) {
+	var silences []models.RecurringSilence
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&silences)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get recurring silences: %w", err)
+	}
+	return silences, nil
+}
+
+func (st DBstore) GetAllRecurringSilences(ctx context.Context) ([]models.RecurringSilence, error) {
+	var silences []models.RecurringSilence
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Asc("org_id", "uid").Find(&silences)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get recurring silences: %w", err)
+	}
+	return silences, nil
+}
+
+func (st DBstore) GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	var silence models.RecurringSilence
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&silence)
+		if err != nil {
+			return fmt.Errorf("failed to get recurring silence: %w", err)
+		} else if !exists {
+			return models.ErrRecurringSilenceNotFound
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+func (st DBstore) InsertRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		silence.Updated = TimeNow().UTC()
+		if _, err := sess.Insert(silence); err != nil {
+			return fmt.Errorf("failed to insert recurring silence: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) UpdateRecurringSilence(ctx context.Context, silence *models.RecurringSilence) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		silence.Updated = TimeNow().UTC()
+		affected, err := sess.Where("org_id = ? AND uid = ?", silence.OrgID, silence.UID).AllCols().Omit("id").Update(silence)
+		if err != nil {
+			return fmt.Errorf("failed to update recurring silence: %w", err)
+		}
+		if affected == 0 {
+			return models.ErrRecurringSilenceNotFound
+		}
+		return nil
+	})
+}
+
+func (st DBstore) DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&models.RecurringSilence{}); err != nil {
+			return fmt.Errorf("failed to delete recurring silence: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,78 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationRecurringSilences(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	silence := func(orgID int64, uid string) *models.RecurringSilence {
+		return &models.RecurringSilence{
+			OrgID:         orgID,
+			UID:           uid,
+			Comment:       "maintenance",
+			CreatedBy:     "ops",
+			Matchers:      `[["cluster","=","eu-1"]]`,
+			Cron:          "0 22 * * SAT",
+			Duration:      3600,
+			TimeIntervals: "[]",
+		}
+	}
+
+	require.NoError(t, dbstore.InsertRecurringSilence(ctx, silence(1, "b")))
+	require.NoError(t, dbstore.InsertRecurringSilence(ctx, silence(1, "a")))
+	require.NoError(t, dbstore.InsertRecurringSilence(ctx, silence(2, "a")))
+
+	t.Run("UIDs are unique per organization", func(t *testing.T) {
+		require.Error(t, dbstore.InsertRecurringSilence(ctx, silence(1, "a")))
+	})
+
+	t.Run("get by organization", func(t *testing.T) {
+		result, err := dbstore.GetRecurringSilences(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "a", result[0].UID)
+		assert.Equal(t, "b", result[1].UID)
+
+		all, err := dbstore.GetAllRecurringSilences(ctx)
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updated := silence(1, "a")
+		updated.Cron = "0 20 * * SUN"
+		require.NoError(t, dbstore.UpdateRecurringSilence(ctx, updated))
+
+		result, err := dbstore.GetRecurringSilence(ctx, 1, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "0 20 * * SUN", result.Cron)
+
+		other, err := dbstore.GetRecurringSilence(ctx, 2, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "0 22 * * SAT", other.Cron)
+
+		require.ErrorIs(t, dbstore.UpdateRecurringSilence(ctx, silence(3, "a")), models.ErrRecurringSilenceNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteRecurringSilence(ctx, 1, "a"))
+		_, err := dbstore.GetRecurringSilence(ctx, 1, "a")
+		require.ErrorIs(t, err, models.ErrRecurringSilenceNotFound)
+		_, err = dbstore.GetRecurringSilence(ctx, 2, "a")
+		require.NoError(t, err)
+	})
+}
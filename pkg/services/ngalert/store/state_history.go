@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// stateHistoryBatchSize is the maximum number of state history entries inserted by a single statement.
+// This makes sure we don't create statements that are too long for some databases to process.
+// For example, SQLite has a limit of 999 variables per write.
+const stateHistoryBatchSize = 50
+
+// defaultStateHistoryQueryLimit is the maximum number of state history entries returned by a query without a limit.
+const defaultStateHistoryQueryLimit = 1000
+
+// likeEscaper escapes the wildcards of a LIKE pattern. The escape character is '!' because backslashes are
+// escape characters of string literals in MySQL.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+type StateHistoryStore interface {
+	// SaveStateHistory saves the provided state history entries.
+	SaveStateHistory(ctx context.Context, entries []models.StateHistoryEntry) error
+
+	// QueryStateHistory returns the most recent state history entries of a single organization that match the query,
+	// ordered by time. At most query.Limit entries are returned, or 1000 if the query has no limit.
+	QueryStateHistory(ctx context.Context, query models.HistoryQuery) ([]models.StateHistoryEntry, error)
+
+	// DeleteStateHistoryOlderThan deletes state history entries that were recorded before the given time.
+	// It returns the number of deleted entries or an error.
+	DeleteStateHistoryOlderThan(ctx context.Context, t time.Time) (int64, error)
+}
+
+func (st DBstore) SaveStateHistory(ctx context.Context, entries []models.StateHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for start := 0; start < len(entries); start += stateHistoryBatchSize {
+			end := start + stateHistoryBatchSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			batch := entries[start:end]
+			if _, err := sess.Table(&models.StateHistoryEntry{}).InsertMulti(&batch); err != nil {
+				return fmt.Errorf("failed to insert state history: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (st DBstore) QueryStateHistory(ctx context.Context, query models.HistoryQuery) ([]models.StateHistoryEntry, error) {
+	var entries []models.StateHistoryEntry
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.RuleUID != "" {
+			q = q.And("rule_uid = ?", query.RuleUID)
+		}
+		if !query.From.IsZero() {
+			q = q.And("at >= ?", query.From.UnixMilli())
+		}
+		if !query.To.IsZero() {
+			q = q.And("at <= ?", query.To.UnixMilli())
+		}
+		// Labels are stored as a JSON array of [key, value] pairs, so a label matches if its pair is in the array.
+		for name, value := range query.Labels {
+			pair, err := json.Marshal([2]string{name, value})
+			if err != nil {
+				return err
+			}
+			q = q.And("labels "+st.SQLStore.GetDialect().LikeStr()+" ? ESCAPE '!'", "%"+likeEscaper.Replace(string(pair))+"%")
+		}
+		limit := query.Limit
+		if limit <= 0 {
+			limit = defaultStateHistoryQueryLimit
+		}
+		return q.Desc("at", "id").Limit(limit).Find(&entries)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query state history: %w", err)
+	}
+	// The most recent entries are queried, but they are returned in the order they were recorded.
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+func (st DBstore) DeleteStateHistoryOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	var n int64
+	if err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("at < ?", t.UnixMilli()).Delete(&models.StateHistoryEntry{})
+		if err != nil {
+			return fmt.Errorf("failed to delete old state history: %w", err)
+		}
+		n = rows
+		return nil
+	}); err != nil {
+		return -1, err
+	}
+	return n, nil
+}
@@ -0,0 +1,109 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationStateHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Now()
+	entry := func(orgID int64, ruleUID string, at time.Time) models.StateHistoryEntry {
+		return models.StateHistoryEntry{
+			OrgID:         orgID,
+			RuleUID:       ruleUID,
+			Labels:        `[["team","a_b"]]`,
+			PreviousState: "Normal",
+			CurrentState:  "Alerting",
+			Data:          "{}",
+			At:            at.UnixMilli(),
+		}
+	}
+
+	// more entries than fit into a single insert statement
+	entries := make([]models.StateHistoryEntry, 0, 120)
+	for i := 0; i < 100; i++ {
+		entries = append(entries, entry(1, "rule-1", now.Add(-time.Duration(i)*time.Minute)))
+	}
+	for i := 0; i < 10; i++ {
+		entries = append(entries, entry(1, "rule-2", now.Add(-time.Duration(i)*time.Hour)))
+		entries = append(entries, entry(2, "rule-1", now))
+	}
+	for _, labels := range []string{`[["pod","web-1"],["team","a_b"]]`, `[["pod","web-2"],["team","axb"]]`} {
+		e := entry(3, "rule-1", now)
+		e.Labels = labels
+		entries = append(entries, e)
+	}
+	require.NoError(t, dbstore.SaveStateHistory(ctx, entries))
+
+	t.Run("query by rule UID", func(t *testing.T) {
+		result, err := dbstore.QueryStateHistory(ctx, models.HistoryQuery{OrgID: 1, RuleUID: "rule-1"})
+		require.NoError(t, err)
+		assert.Len(t, result, 100)
+		// results are ordered by time
+		assert.Equal(t, now.Add(-99*time.Minute).UnixMilli(), result[0].At)
+	})
+
+	t.Run("query by time range", func(t *testing.T) {
+		result, err := dbstore.QueryStateHistory(ctx, models.HistoryQuery{
+			OrgID:   1,
+			RuleUID: "rule-2",
+			From:    now.Add(-150 * time.Minute),
+			To:      now,
+		})
+		require.NoError(t, err)
+		assert.Len(t, result, 3)
+	})
+
+	t.Run("query by org", func(t *testing.T) {
+		result, err := dbstore.QueryStateHistory(ctx, models.HistoryQuery{OrgID: 2})
+		require.NoError(t, err)
+		assert.Len(t, result, 10)
+	})
+
+	t.Run("query by labels", func(t *testing.T) {
+		result, err := dbstore.QueryStateHistory(ctx, models.HistoryQuery{OrgID: 3, Labels: map[string]string{"team": "a_b"}})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, `[["pod","web-1"],["team","a_b"]]`, result[0].Labels)
+
+		result, err = dbstore.QueryStateHistory(ctx, models.HistoryQuery{OrgID: 3, Labels: map[string]string{"pod": "web-2", "team": "axb"}})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+
+		result, err = dbstore.QueryStateHistory(ctx, models.HistoryQuery{OrgID: 3, Labels: map[string]string{"pod": "web"}})
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("query returns the most recent entries up to the limit", func(t *testing.T) {
+		result, err := dbstore.QueryStateHistory(ctx, models.HistoryQuery{OrgID: 1, RuleUID: "rule-1", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result, 10)
+		assert.Equal(t, now.Add(-9*time.Minute).UnixMilli(), result[0].At)
+		assert.Equal(t, now.UnixMilli(), result[9].At)
+	})
+
+	t.Run("delete old entries", func(t *testing.T) {
+		deleted, err := dbstore.DeleteStateHistoryOlderThan(ctx, now.Add(-5*time.Hour-time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), deleted)
+
+		result, err := dbstore.QueryStateHistory(ctx, models.HistoryQuery{OrgID: 1, RuleUID: "rule-2"})
+		require.NoError(t, err)
+		assert.Len(t, result, 6)
+	})
+}
@@ -0,0 +1,25 @@
+package writer
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// NoopWriter is a writer that discards the results of recording rules.
+// It is used when no remote write endpoint is configured.
+type NoopWriter struct {
+	logger log.Logger
+}
+
+func NewNoopWriter(logger log.Logger) *NoopWriter {
+	return &NoopWriter{logger: logger}
+}
+
+func (w *NoopWriter) Write(ctx context.Context, name string, _ time.Time, frames data.Frames, _ map[string]string) error {
+	w.logger.FromContext(ctx).Debug("Discarding recording rule result, no remote write endpoint is configured", "metric", name, "frames", len(frames))
+	return nil
+}
@@ -0,0 +1,129 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// PrometheusWriter writes the results of recording rules to a Prometheus remote write endpoint.
+type PrometheusWriter struct {
+	url               string
+	basicAuthUsername string
+	basicAuthPassword string
+	client            *http.Client
+	logger            log.Logger
+}
+
+func NewPrometheusWriter(cfg setting.RecordingRuleSettings, logger log.Logger) *PrometheusWriter {
+	return &PrometheusWriter{
+		url:               cfg.URL,
+		basicAuthUsername: cfg.BasicAuthUsername,
+		basicAuthPassword: cfg.BasicAuthPassword,
+		client:            &http.Client{Timeout: cfg.Timeout},
+		logger:            logger,
+	}
+}
+
+// Write converts the frames to series of the metric with the given name, and sends them to the remote write endpoint.
+// Every numeric field of the frames becomes one series with a single sample at time t. The labels of the series are
+// the labels of the field, overridden by extraLabels.
+func (w *PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	series, err := FramesToSeries(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+	if len(series) == 0 {
+		l.Debug("No series to write", "metric", name)
+		return nil
+	}
+
+	body, err := remotewrite.TimeSeriesToBytes(series)
+	if err != nil {
+		return fmt.Errorf("failed to encode series: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.basicAuthUsername != "" || w.basicAuthPassword != "" {
+		req.SetBasicAuth(w.basicAuthUsername, w.basicAuthPassword)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote write request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			l.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("remote write endpoint responded with status %d: %s", resp.StatusCode, string(msg))
+	}
+
+	l.Debug("Wrote recording rule result", "metric", name, "series", len(series))
+	return nil
+}
+
+// FramesToSeries converts the result of a query or expression to Prometheus series of the metric with the given name.
+// Numeric fields become one series each. If a frame has more than one row, the last non-null value is used.
+func FramesToSeries(name string, t time.Time, frames data.Frames, extraLabels map[string]string) ([]prompb.TimeSeries, error) {
+	result := make([]prompb.TimeSeries, 0, len(frames))
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() {
+				continue
+			}
+			value, ok := lastValue(field)
+			if !ok {
+				continue
+			}
+
+			labels := make(map[string]string, len(field.Labels)+len(extraLabels))
+			for k, v := range field.Labels {
+				labels[k] = v
+			}
+			for k, v := range extraLabels {
+				labels[k] = v
+			}
+
+			series, ok := remotewrite.NewTimeSeries(name, labels, remotewrite.NewSample(t, value))
+			if !ok {
+				return nil, fmt.Errorf("invalid metric name %q", name)
+			}
+			result = append(result, series)
+		}
+	}
+	return result, nil
+}
+
+func lastValue(field *data.Field) (float64, bool) {
+	for i := field.Len() - 1; i >= 0; i-- {
+		v, err := field.FloatAt(i)
+		if err != nil {
+			return 0, false
+		}
+		if _, ok := field.ConcreteAt(i); !ok {
+			continue
+		}
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package writer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestPrometheusWriter_Write(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	frames := data.Frames{
+		data.NewFrame("",
+			data.NewField("", data.Labels{"instance": "a"}, []float64{1, 2}),
+		),
+		data.NewFrame("",
+			data.NewField("", data.Labels{"instance": "b"}, []*float64{ptr(3), nil}),
+		),
+		data.NewFrame("",
+			data.NewField("", data.Labels{"instance": "c"}, []*float64{nil}),
+		),
+	}
+
+	t.Run("writes last value of every series", func(t *testing.T) {
+		target := NewTestRemoteWriteTarget(t)
+		w := NewPrometheusWriter(setting.RecordingRuleSettings{
+			URL:               target.URL(),
+			BasicAuthUsername: "user",
+			BasicAuthPassword: "pass",
+			Timeout:           time.Second,
+		}, log.NewNopLogger())
+
+		err := w.Write(context.Background(), "test_metric", now, frames, map[string]string{"rule": "x"})
+		require.NoError(t, err)
+
+		require.Equal(t, 1, target.RequestsCount)
+		require.Equal(t, "snappy", target.LastRequest.Header.Get("Content-Encoding"))
+		user, pass, ok := target.LastRequest.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "pass", pass)
+
+		series := target.Series()
+		require.Len(t, series, 2)
+		values := map[string]float64{}
+		for _, s := range series {
+			lbls := map[string]string{}
+			for _, l := range s.Labels {
+				lbls[l.Name] = l.Value
+			}
+			require.Equal(t, "test_metric", lbls["__name__"])
+			require.Equal(t, "x", lbls["rule"])
+			require.Len(t, s.Samples, 1)
+			require.Equal(t, now.UnixMilli(), s.Samples[0].Timestamp)
+			values[lbls["instance"]] = s.Samples[0].Value
+		}
+		require.Equal(t, map[string]float64{"a": 2, "b": 3}, values)
+	})
+
+	t.Run("returns error if endpoint fails", func(t *testing.T) {
+		w := NewPrometheusWriter(setting.RecordingRuleSettings{
+			URL:     "http://127.0.0.1:0",
+			Timeout: time.Second,
+		}, log.NewNopLogger())
+
+		err := w.Write(context.Background(), "test_metric", now, frames, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("returns error for invalid metric name", func(t *testing.T) {
+		_, err := FramesToSeries("", now, frames, nil)
+		require.Error(t, err)
+	})
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,67 @@
+package writer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// TestRemoteWriteTarget is a fake remote write endpoint that records the series it receives.
+type TestRemoteWriteTarget struct {
+	srv *httptest.Server
+
+	mtx            sync.Mutex
+	RequestsCount  int
+	LastRequest    *http.Request
+	ReceivedSeries []prompb.TimeSeries
+}
+
+func NewTestRemoteWriteTarget(t *testing.T) *TestRemoteWriteTarget {
+	t.Helper()
+
+	target := &TestRemoteWriteTarget{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		target.mtx.Lock()
+		defer target.mtx.Unlock()
+
+		compressed, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var req prompb.WriteRequest
+		if err := req.Unmarshal(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		target.RequestsCount++
+		target.LastRequest = r
+		target.ReceivedSeries = append(target.ReceivedSeries, req.Timeseries...)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	target.srv = httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(target.srv.Close)
+	return target
+}
+
+func (s *TestRemoteWriteTarget) URL() string {
+	return s.srv.URL
+}
+
+// Series returns a copy of all series received so far.
+func (s *TestRemoteWriteTarget) Series() []prompb.TimeSeries {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]prompb.TimeSeries(nil), s.ReceivedSeries...)
+}
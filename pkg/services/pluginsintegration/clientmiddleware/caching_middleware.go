@@ -0,0 +1,267 @@
+package clientmiddleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/proxyutil"
+)
+
+const (
+	queryCacheKeyPrefix = "query-cache:"
+
+	queryCacheResultHit  = "hit"
+	queryCacheResultMiss = "miss"
+	queryCacheResultSkip = "skip"
+)
+
+var queryCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "grafana",
+	Name:      "plugin_query_cache_requests_total",
+	Help:      "The total amount of data source query requests handled by the query cache",
+}, []string{"plugin_id", "result"})
+
+// queryCacheUserHeaders are the headers that identify the user to the data source.
+// They are part of the cache key so that a user never gets results of queries made with the identity of another user.
+var queryCacheUserHeaders = []string{tokenHeaderName, idTokenHeaderName, cookieHeaderName, proxyutil.UserHeaderName}
+
+// NewCachingMiddleware creates a new plugins.ClientMiddleware that caches the results
+// of data source queries made through the HTTP API in the remote cache.
+// Queries made without an HTTP request, such as alert rule evaluations, are never cached.
+func NewCachingMiddleware(cache remotecache.CacheStorage, settings setting.QueryCachingSettings) plugins.ClientMiddleware {
+	return plugins.ClientMiddlewareFunc(func(next plugins.Client) plugins.Client {
+		return &CachingMiddleware{
+			next:     next,
+			cache:    cache,
+			settings: settings,
+			logger:   log.New("query-caching"),
+		}
+	})
+}
+
+type CachingMiddleware struct {
+	next     plugins.Client
+	cache    remotecache.CacheStorage
+	settings setting.QueryCachingSettings
+	logger   log.Logger
+}
+
+// dataSourceCachingSettings are the query caching settings of a data source, stored in its JSON data.
+type dataSourceCachingSettings struct {
+	Enabled *bool `json:"queryCachingEnabled"`
+	// TTL in milliseconds.
+	TTL int64 `json:"queryCachingTTL"`
+}
+
+// ttl returns how long the results of queries of the data source are cached, or false if they must not be cached.
+func (m *CachingMiddleware) ttl(settings *backend.DataSourceInstanceSettings) (time.Duration, bool) {
+	var dsSettings dataSourceCachingSettings
+	if len(settings.JSONData) > 0 {
+		if err := json.Unmarshal(settings.JSONData, &dsSettings); err != nil {
+			m.logger.Warn("Failed to read query caching settings of data source", "datasource_uid", settings.UID, "error", err)
+		}
+	}
+	if dsSettings.Enabled != nil && !*dsSettings.Enabled {
+		return 0, false
+	}
+	if dsSettings.TTL > 0 {
+		return time.Duration(dsSettings.TTL) * time.Millisecond, true
+	}
+	return m.settings.TTL, m.settings.TTL > 0
+}
+
+// parseCacheControl returns whether the Cache-Control header of the request forbids reading from and writing to the cache.
+func parseCacheControl(header http.Header) (noCache bool, noStore bool) {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			switch strings.ToLower(strings.TrimSpace(directive)) {
+			case "no-cache":
+				noCache = true
+			case "no-store":
+				noStore = true
+			}
+		}
+	}
+	return noCache, noStore
+}
+
+func (m *CachingMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	reqCtx := contexthandler.FromContext(ctx)
+	// if request not for a datasource or no HTTP request context skip middleware
+	if req == nil || req.PluginContext.DataSourceInstanceSettings == nil || reqCtx == nil || reqCtx.Req == nil {
+		return m.next.QueryData(ctx, req)
+	}
+
+	pluginID := req.PluginContext.PluginID
+	ttl, enabled := m.ttl(req.PluginContext.DataSourceInstanceSettings)
+	noCache, noStore := parseCacheControl(reqCtx.Req.Header)
+	if !enabled || noStore {
+		queryCacheRequests.WithLabelValues(pluginID, queryCacheResultSkip).Inc()
+		return m.next.QueryData(ctx, req)
+	}
+
+	key, err := queryCacheKey(req)
+	if err != nil {
+		m.logger.Warn("Failed to calculate query cache key", "error", err)
+		queryCacheRequests.WithLabelValues(pluginID, queryCacheResultSkip).Inc()
+		return m.next.QueryData(ctx, req)
+	}
+
+	if !noCache && !reqCtx.SkipCache {
+		if cached, ok := m.get(ctx, key); ok {
+			queryCacheRequests.WithLabelValues(pluginID, queryCacheResultHit).Inc()
+			return cached, nil
+		}
+	}
+	queryCacheRequests.WithLabelValues(pluginID, queryCacheResultMiss).Inc()
+
+	resp, err := m.next.QueryData(ctx, req)
+	if err == nil && isCacheable(resp) {
+		m.set(ctx, key, resp, ttl)
+	}
+	return resp, err
+}
+
+func (m *CachingMiddleware) get(ctx context.Context, key string) (*backend.QueryDataResponse, bool) {
+	value, err := m.cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			m.logger.Warn("Failed to read query result from the cache", "error", err)
+		}
+		return nil, false
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil, false
+	}
+	resp := &backend.QueryDataResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		m.logger.Warn("Failed to decode cached query result", "error", err)
+		return nil, false
+	}
+	return resp, true
+}
+
+func (m *CachingMiddleware) set(ctx context.Context, key string, resp *backend.QueryDataResponse, ttl time.Duration) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		m.logger.Warn("Failed to encode query result for the cache", "error", err)
+		return
+	}
+	if m.settings.MaxValueSize > 0 && int64(len(b)) > m.settings.MaxValueSize {
+		m.logger.Debug("Query result is too large to be cached", "size", len(b))
+		return
+	}
+	if err := m.cache.Set(ctx, key, b, ttl); err != nil {
+		m.logger.Warn("Failed to write query result to the cache", "error", err)
+	}
+}
+
+// isCacheable returns true if none of the queries failed.
+func isCacheable(resp *backend.QueryDataResponse) bool {
+	if resp == nil {
+		return false
+	}
+	for _, r := range resp.Responses {
+		if r.Error != nil || r.Status >= http.StatusBadRequest {
+			return false
+		}
+	}
+	return true
+}
+
+type queryCacheKeyQuery struct {
+	RefID         string          `json:"refId"`
+	QueryType     string          `json:"queryType"`
+	MaxDataPoints int64           `json:"maxDataPoints"`
+	Interval      time.Duration   `json:"interval"`
+	From          int64           `json:"from"`
+	To            int64           `json:"to"`
+	JSON          json.RawMessage `json:"json"`
+}
+
+type queryCacheKeyData struct {
+	OrgID             int64                `json:"orgId"`
+	PluginID          string               `json:"pluginId"`
+	DatasourceUID     string               `json:"datasourceUid"`
+	DatasourceUpdated int64                `json:"datasourceUpdated"`
+	Headers           map[string]string    `json:"headers,omitempty"`
+	Queries           []queryCacheKeyQuery `json:"queries"`
+}
+
+// queryCacheKey returns the cache key of the request. It contains the exact time ranges of the queries, so that
+// only requests for the same absolute time range share the cached result. The key changes when the data source is updated.
+func queryCacheKey(req *backend.QueryDataRequest) (string, error) {
+	settings := req.PluginContext.DataSourceInstanceSettings
+	data := queryCacheKeyData{
+		OrgID:             req.PluginContext.OrgID,
+		PluginID:          req.PluginContext.PluginID,
+		DatasourceUID:     settings.UID,
+		DatasourceUpdated: settings.Updated.UnixMilli(),
+		Queries:           make([]queryCacheKeyQuery, 0, len(req.Queries)),
+	}
+	for _, name := range queryCacheUserHeaders {
+		if value, ok := req.Headers[name]; ok {
+			if data.Headers == nil {
+				data.Headers = make(map[string]string)
+			}
+			data.Headers[name] = value
+		}
+	}
+	for _, q := range req.Queries {
+		data.Queries = append(data.Queries, queryCacheKeyQuery{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval,
+			From:          q.TimeRange.From.UnixMilli(),
+			To:            q.TimeRange.To.UnixMilli(),
+			JSON:          q.JSON,
+		})
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return queryCacheKeyPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+func (m *CachingMiddleware) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return m.next.CallResource(ctx, req, sender)
+}
+
+func (m *CachingMiddleware) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	return m.next.CheckHealth(ctx, req)
+}
+
+func (m *CachingMiddleware) CollectMetrics(ctx context.Context, req *backend.CollectMetricsRequest) (*backend.CollectMetricsResult, error) {
+	return m.next.CollectMetrics(ctx, req)
+}
+
+func (m *CachingMiddleware) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	return m.next.SubscribeStream(ctx, req)
+}
+
+func (m *CachingMiddleware) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return m.next.PublishStream(ctx, req)
+}
+
+func (m *CachingMiddleware) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	return m.next.RunStream(ctx, req, sender)
+}
@@ -0,0 +1,216 @@
+package clientmiddleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/plugins/manager/client/clienttest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeCacheStorage struct {
+	mtx   sync.Mutex
+	items map[string]interface{}
+	ttls  map[string]time.Duration
+}
+
+func newFakeCacheStorage() *fakeCacheStorage {
+	return &fakeCacheStorage{items: map[string]interface{}{}, ttls: map[string]time.Duration{}}
+}
+
+func (f *fakeCacheStorage) Get(_ context.Context, key string) (interface{}, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	item, ok := f.items[key]
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	return item, nil
+}
+
+func (f *fakeCacheStorage) Set(_ context.Context, key string, value interface{}, expire time.Duration) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.items[key] = value
+	f.ttls[key] = expire
+	return nil
+}
+
+func (f *fakeCacheStorage) Delete(_ context.Context, key string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.items, key)
+	return nil
+}
+
+func TestCachingMiddleware(t *testing.T) {
+	settings := setting.QueryCachingSettings{Enabled: true, TTL: 5 * time.Minute, MaxValueSize: 1024 * 1024}
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	queryReq := func(from time.Time, jsonData string, headers map[string]string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:    1,
+				PluginID: "elasticsearch",
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					UID:      "es",
+					JSONData: []byte(jsonData),
+				},
+			},
+			Headers: headers,
+			Queries: []backend.DataQuery{
+				{
+					RefID:     "A",
+					JSON:      []byte(`{"query": "level:error"}`),
+					TimeRange: backend.TimeRange{From: from.Add(-time.Hour), To: from},
+				},
+			},
+		}
+	}
+
+	setup := func(t *testing.T, reqHeaders http.Header) (*clienttest.ClientDecoratorTest, *fakeCacheStorage, *http.Request, *int) {
+		req, err := http.NewRequest(http.MethodPost, "/api/ds/query", nil)
+		require.NoError(t, err)
+		for name, values := range reqHeaders {
+			req.Header[name] = values
+		}
+		cache := newFakeCacheStorage()
+		cdt := clienttest.NewClientDecoratorTest(t,
+			clienttest.WithReqContext(req, &user.SignedInUser{}),
+			clienttest.WithMiddlewares(NewCachingMiddleware(cache, settings)),
+		)
+		calls := 0
+		cdt.TestClient.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			calls++
+			return &backend.QueryDataResponse{
+				Responses: backend.Responses{
+					"A": {Frames: data.Frames{data.NewFrame("errors", data.NewField("count", nil, []int64{int64(calls)}))}},
+				},
+			}, nil
+		}
+		return cdt, cache, req, &calls
+	}
+
+	t.Run("returns cached results of identical queries", func(t *testing.T) {
+		cdt, cache, req, calls := setup(t, nil)
+
+		first, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		second, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+
+		require.Equal(t, 1, *calls)
+		require.Len(t, cache.items, 1)
+		for _, ttl := range cache.ttls {
+			require.Equal(t, 5*time.Minute, ttl)
+		}
+		require.Equal(t, int64(1), second.Responses["A"].Frames[0].Fields[0].At(0))
+		require.Equal(t, first.Responses["A"].Frames[0].Name, second.Responses["A"].Frames[0].Name)
+	})
+
+	t.Run("does not share results between different time ranges", func(t *testing.T) {
+		cdt, _, req, calls := setup(t, nil)
+
+		// both ranges are within the same TTL window
+		_, err := cdt.Decorator.QueryData(req.Context(), queryReq(now.Add(2*time.Minute), `{}`, nil))
+		require.NoError(t, err)
+		_, err = cdt.Decorator.QueryData(req.Context(), queryReq(now.Add(4*time.Minute), `{}`, nil))
+		require.NoError(t, err)
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("does not share results between users when the data source forwards their identity", func(t *testing.T) {
+		cdt, _, req, calls := setup(t, nil)
+
+		_, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, map[string]string{"Authorization": "Bearer a"}))
+		require.NoError(t, err)
+		_, err = cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, map[string]string{"Authorization": "Bearer b"}))
+		require.NoError(t, err)
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("uses the TTL of the data source", func(t *testing.T) {
+		cdt, cache, req, _ := setup(t, nil)
+
+		_, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{"queryCachingTTL": 60000}`, nil))
+		require.NoError(t, err)
+		for _, ttl := range cache.ttls {
+			require.Equal(t, time.Minute, ttl)
+		}
+	})
+
+	t.Run("does not cache queries of data sources with caching disabled", func(t *testing.T) {
+		cdt, cache, req, calls := setup(t, nil)
+
+		_, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{"queryCachingEnabled": false}`, nil))
+		require.NoError(t, err)
+		_, err = cdt.Decorator.QueryData(req.Context(), queryReq(now, `{"queryCachingEnabled": false}`, nil))
+		require.NoError(t, err)
+		require.Equal(t, 2, *calls)
+		require.Empty(t, cache.items)
+	})
+
+	t.Run("Cache-Control no-cache refreshes the cached result", func(t *testing.T) {
+		cdt, cache, req, calls := setup(t, http.Header{"Cache-Control": []string{"no-cache"}})
+
+		_, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		_, err = cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		require.Equal(t, 2, *calls)
+		require.Len(t, cache.items, 1)
+	})
+
+	t.Run("Cache-Control no-store bypasses the cache", func(t *testing.T) {
+		cdt, cache, req, calls := setup(t, http.Header{"Cache-Control": []string{"max-age=0, no-store"}})
+
+		_, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		require.Equal(t, 1, *calls)
+		require.Empty(t, cache.items)
+	})
+
+	t.Run("X-Grafana-NoCache refreshes the cached result", func(t *testing.T) {
+		cdt, _, req, calls := setup(t, nil)
+		cdt.ReqContext.SkipCache = true
+
+		_, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		_, err = cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		require.Equal(t, 2, *calls)
+	})
+
+	t.Run("does not cache failed queries", func(t *testing.T) {
+		cdt, cache, req, _ := setup(t, nil)
+		cdt.TestClient.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			return &backend.QueryDataResponse{
+				Responses: backend.Responses{"A": {Error: context.DeadlineExceeded}},
+			}, nil
+		}
+
+		_, err := cdt.Decorator.QueryData(req.Context(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		require.Empty(t, cache.items)
+	})
+
+	t.Run("does not cache queries without an HTTP request", func(t *testing.T) {
+		cdt, cache, _, calls := setup(t, nil)
+
+		_, err := cdt.Decorator.QueryData(context.Background(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		_, err = cdt.Decorator.QueryData(context.Background(), queryReq(now, `{}`, nil))
+		require.NoError(t, err)
+		require.Equal(t, 2, *calls)
+		require.Empty(t, cache.items)
+	})
+}
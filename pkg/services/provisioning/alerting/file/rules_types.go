@@ -67,18 +67,33 @@ type AlertRuleGroupWithFolderTitle struct {
 }
 
 type AlertRuleV1 struct {
-	UID          values.StringValue    `json:"uid" yaml:"uid"`
-	Title        values.StringValue    `json:"title" yaml:"title"`
-	Condition    values.StringValue    `json:"condition" yaml:"condition"`
-	Data         []QueryV1             `json:"data" yaml:"data"`
-	DashboardUID values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
-	PanelID      values.Int64Value     `json:"panelId" yaml:"panelId"`
-	NoDataState  values.StringValue    `json:"noDataState" yaml:"noDataState"`
-	ExecErrState values.StringValue    `json:"execErrState" yaml:"execErrState"`
-	For          values.StringValue    `json:"for" yaml:"for"`
-	Annotations  values.StringMapValue `json:"annotations" yaml:"annotations"`
-	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
-	IsPaused     values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	UID           values.StringValue    `json:"uid" yaml:"uid"`
+	Title         values.StringValue    `json:"title" yaml:"title"`
+	Condition     values.StringValue    `json:"condition" yaml:"condition"`
+	Data          []QueryV1             `json:"data" yaml:"data"`
+	DashboardUID  values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
+	PanelID       values.Int64Value     `json:"panelId" yaml:"panelId"`
+	NoDataState   values.StringValue    `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  values.StringValue    `json:"execErrState" yaml:"execErrState"`
+	For           values.StringValue    `json:"for" yaml:"for"`
+	KeepFiringFor values.StringValue    `json:"keepFiringFor" yaml:"keepFiringFor"`
+	Annotations   values.StringMapValue `json:"annotations" yaml:"annotations"`
+	Labels        values.StringMapValue `json:"labels" yaml:"labels"`
+	IsPaused      values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	Record        *RecordV1             `json:"record" yaml:"record"`
+	DependsOn     []RuleDependencyV1    `json:"dependsOn" yaml:"dependsOn"`
+}
+
+// RecordV1 contains the settings of a recording rule.
+type RecordV1 struct {
+	Metric values.StringValue `json:"metric" yaml:"metric"`
+	From   values.StringValue `json:"from" yaml:"from"`
+}
+
+// RuleDependencyV1 is a dependency of a rule on another rule of the same rule group.
+type RuleDependencyV1 struct {
+	RuleUID values.StringValue `json:"ruleUid" yaml:"ruleUid"`
+	State   values.StringValue `json:"state" yaml:"state"`
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -97,6 +112,13 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
 	}
 	alertRule.For = time.Duration(duration)
+	if keepFiringFor := strings.TrimSpace(rule.KeepFiringFor.Value()); keepFiringFor != "" {
+		duration, err := model.ParseDuration(keepFiringFor)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.KeepFiringFor = time.Duration(duration)
+	}
 	dashboardUID := rule.DashboardUID.Value()
 	alertRule.DashboardUID = &dashboardUID
 	panelID := rule.PanelID.Value()
@@ -120,7 +142,18 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 	}
 	alertRule.NoDataState = noDataState
 	alertRule.Condition = rule.Condition.Value()
-	if alertRule.Condition == "" {
+	if rule.Record != nil {
+		alertRule.Record = models.Record{
+			Metric: rule.Record.Metric.Value(),
+			From:   rule.Record.From.Value(),
+		}
+		if err := alertRule.Record.Validate(); err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		if alertRule.Condition != "" {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: recording rule cannot have a condition", alertRule.Title)
+		}
+	} else if alertRule.Condition == "" {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no condition set", alertRule.Title)
 	}
 	alertRule.Annotations = rule.Annotations.Raw
@@ -136,6 +169,15 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
 	alertRule.IsPaused = rule.IsPaused.Value()
+	for _, dep := range rule.DependsOn {
+		alertRule.DependsOn = append(alertRule.DependsOn, models.RuleDependency{
+			RuleUID: dep.RuleUID.Value(),
+			State:   models.RuleDependencyState(strings.TrimSpace(dep.State.Value())),
+		})
+	}
+	if err := models.ValidateRuleDependencies(alertRule.UID, alertRule.DependsOn); err != nil {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+	}
 	return alertRule, nil
 }
 
@@ -196,18 +238,33 @@ type AlertRuleGroupExport struct {
 
 // AlertRuleExport is the provisioned file export of models.AlertRule.
 type AlertRuleExport struct {
-	UID          string                     `json:"uid" yaml:"uid"`
-	Title        string                     `json:"title" yaml:"title"`
-	Condition    string                     `json:"condition" yaml:"condition"`
-	Data         []AlertQueryExport         `json:"data" yaml:"data"`
-	DashboardUID string                     `json:"dasboardUid,omitempty" yaml:"dashboardUid,omitempty"`
-	PanelID      int64                      `json:"panelId,omitempty" yaml:"panelId,omitempty"`
-	NoDataState  models.NoDataState         `json:"noDataState" yaml:"noDataState"`
-	ExecErrState models.ExecutionErrorState `json:"execErrState" yaml:"execErrState"`
-	For          model.Duration             `json:"for" yaml:"for"`
-	Annotations  map[string]string          `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Labels       map[string]string          `json:"labels,omitempty" yaml:"labels,omitempty"`
-	IsPaused     bool                       `json:"isPaused" yaml:"isPaused"`
+	UID           string                     `json:"uid" yaml:"uid"`
+	Title         string                     `json:"title" yaml:"title"`
+	Condition     string                     `json:"condition,omitempty" yaml:"condition,omitempty"`
+	Data          []AlertQueryExport         `json:"data" yaml:"data"`
+	DashboardUID  string                     `json:"dasboardUid,omitempty" yaml:"dashboardUid,omitempty"`
+	PanelID       int64                      `json:"panelId,omitempty" yaml:"panelId,omitempty"`
+	NoDataState   models.NoDataState         `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  models.ExecutionErrorState `json:"execErrState" yaml:"execErrState"`
+	For           model.Duration             `json:"for" yaml:"for"`
+	KeepFiringFor model.Duration             `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	Annotations   map[string]string          `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Labels        map[string]string          `json:"labels,omitempty" yaml:"labels,omitempty"`
+	IsPaused      bool                       `json:"isPaused" yaml:"isPaused"`
+	Record        *RecordExport              `json:"record,omitempty" yaml:"record,omitempty"`
+	DependsOn     []RuleDependencyExport     `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+}
+
+// RecordExport is the provisioned export of models.Record.
+type RecordExport struct {
+	Metric string `json:"metric" yaml:"metric"`
+	From   string `json:"from" yaml:"from"`
+}
+
+// RuleDependencyExport is the provisioned export of models.RuleDependency.
+type RuleDependencyExport struct {
+	RuleUID string `json:"ruleUid" yaml:"ruleUid"`
+	State   string `json:"state" yaml:"state"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -272,19 +329,38 @@ func newAlertRuleExport(rule models.AlertRule) (AlertRuleExport, error) {
 		panelID = *rule.PanelID
 	}
 
+	var record *RecordExport
+	if !rule.Record.IsEmpty() {
+		record = &RecordExport{
+			Metric: rule.Record.Metric,
+			From:   rule.Record.From,
+		}
+	}
+
+	var dependsOn []RuleDependencyExport
+	for _, dep := range rule.DependsOn {
+		dependsOn = append(dependsOn, RuleDependencyExport{
+			RuleUID: dep.RuleUID,
+			State:   string(dep.State),
+		})
+	}
+
 	return AlertRuleExport{
-		UID:          rule.UID,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         data,
-		DashboardUID: dashboardUID,
-		PanelID:      panelID,
-		NoDataState:  rule.NoDataState,
-		ExecErrState: rule.ExecErrState,
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		IsPaused:     rule.IsPaused,
+		UID:           rule.UID,
+		Title:         rule.Title,
+		For:           model.Duration(rule.For),
+		KeepFiringFor: model.Duration(rule.KeepFiringFor),
+		Condition:     rule.Condition,
+		Data:          data,
+		DashboardUID:  dashboardUID,
+		PanelID:       panelID,
+		NoDataState:   rule.NoDataState,
+		ExecErrState:  rule.ExecErrState,
+		Annotations:   rule.Annotations,
+		Labels:        rule.Labels,
+		IsPaused:      rule.IsPaused,
+		Record:        record,
+		DependsOn:     dependsOn,
 	}, nil
 }
 
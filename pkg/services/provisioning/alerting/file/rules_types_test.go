@@ -125,6 +125,31 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule with out a keepFiringFor duration should default to zero", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with an invalid keepFiringFor duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("10x"), &keepFiringFor)
+		rule.KeepFiringFor = keepFiringFor
+		require.NoError(t, err)
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a rule with a keepFiringFor duration should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("5m"), &keepFiringFor)
+		rule.KeepFiringFor = keepFiringFor
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.KeepFiringFor)
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
@@ -189,6 +214,98 @@ func TestRules(t *testing.T) {
 	})
 }
 
+func TestRecordingRules(t *testing.T) {
+	recordingRuleV1 := func(t *testing.T, metric string) AlertRuleV1 {
+		t.Helper()
+		rule := validRuleV1(t)
+		rule.Condition = values.StringValue{}
+		var record RecordV1
+		require.NoError(t, yaml.Unmarshal([]byte("metric: "+metric+"\nfrom: A"), &record))
+		rule.Record = &record
+		return rule
+	}
+
+	t.Run("a recording rule should map the record settings", func(t *testing.T) {
+		rule := recordingRuleV1(t, "requests:rate5m")
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, models.Record{Metric: "requests:rate5m", From: "A"}, ruleMapped.Record)
+		require.Empty(t, ruleMapped.Condition)
+	})
+	t.Run("a recording rule with an invalid metric name should error", func(t *testing.T) {
+		rule := recordingRuleV1(t, "requests-rate")
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a recording rule with a condition should error", func(t *testing.T) {
+		rule := recordingRuleV1(t, "requests:rate5m")
+		rule.Condition = validRuleV1(t).Condition
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("an exported recording rule should be provisioned with the same record settings", func(t *testing.T) {
+		ruleV1 := recordingRuleV1(t, "requests:rate5m")
+		rule, err := ruleV1.mapToModel(1)
+		require.NoError(t, err)
+		rule.Data = []models.AlertQuery{{RefID: "A", Model: []byte(`{"expr":"rate(requests[5m])"}`)}}
+
+		export, err := newAlertRuleExport(rule)
+		require.NoError(t, err)
+		b, err := yaml.Marshal(export)
+		require.NoError(t, err)
+
+		var exportedV1 AlertRuleV1
+		require.NoError(t, yaml.Unmarshal(b, &exportedV1))
+		provisioned, err := exportedV1.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, rule.Record, provisioned.Record)
+		require.Empty(t, provisioned.Condition)
+	})
+}
+
+func TestRuleDependencies(t *testing.T) {
+	ruleWithDependencies := func(t *testing.T, deps string) AlertRuleV1 {
+		t.Helper()
+		rule := validRuleV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte(deps), &rule.DependsOn))
+		return rule
+	}
+
+	t.Run("a rule should map its dependencies", func(t *testing.T) {
+		rule := ruleWithDependencies(t, "- ruleUid: upstream\n  state: Alerting")
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, []models.RuleDependency{{RuleUID: "upstream", State: models.RuleDependencyStateAlerting}}, ruleMapped.DependsOn)
+	})
+	t.Run("a dependency with an unknown state should error", func(t *testing.T) {
+		rule := ruleWithDependencies(t, "- ruleUid: upstream\n  state: Firing")
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a dependency without a rule UID should error", func(t *testing.T) {
+		rule := ruleWithDependencies(t, "- state: Normal")
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("an exported rule should be provisioned with the same dependencies", func(t *testing.T) {
+		ruleV1 := ruleWithDependencies(t, "- ruleUid: upstream\n  state: Pending")
+		rule, err := ruleV1.mapToModel(1)
+		require.NoError(t, err)
+		rule.Data = []models.AlertQuery{{RefID: "A", Model: []byte(`{"expr":"up"}`)}}
+
+		export, err := newAlertRuleExport(rule)
+		require.NoError(t, err)
+		b, err := yaml.Marshal(export)
+		require.NoError(t, err)
+
+		var exportedV1 AlertRuleV1
+		require.NoError(t, yaml.Unmarshal(b, &exportedV1))
+		provisioned, err := exportedV1.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, rule.DependsOn, provisioned.DependsOn)
+	})
+}
+
 func validRuleGroupV1(t *testing.T) AlertRuleGroupV1 {
 	t.Helper()
 	var (
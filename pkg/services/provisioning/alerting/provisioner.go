@@ -17,6 +17,7 @@ type ProvisionerConfig struct {
 	ContactPointService        provisioning.ContactPointService
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
+	RecurringSilenceService    provisioning.RecurringSilenceService
 	TemplateService            provisioning.TemplateService
 }
 
@@ -48,6 +49,11 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("mute times: %w", err)
 	}
+	rsProvisioner := NewRecurringSilenceProvisioner(logger, cfg.RecurringSilenceService)
+	err = rsProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("recurring silences: %w", err)
+	}
 	ttProvsioner := NewTextTemplateProvisioner(logger, cfg.TemplateService)
 	err = ttProvsioner.Provision(ctx, files)
 	if err != nil {
@@ -70,6 +76,10 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("mute times: %w", err)
 	}
+	err = rsProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("recurring silences: %w", err)
+	}
 	err = ttProvsioner.Unprovision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
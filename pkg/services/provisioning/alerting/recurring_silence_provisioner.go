@@ -0,0 +1,64 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type RecurringSilenceProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultRecurringSilenceProvisioner struct {
+	logger                  log.Logger
+	recurringSilenceService provisioning.RecurringSilenceService
+}
+
+func NewRecurringSilenceProvisioner(logger log.Logger,
+	recurringSilenceService provisioning.RecurringSilenceService) RecurringSilenceProvisioner {
+	return &defaultRecurringSilenceProvisioner{
+		logger:                  logger,
+		recurringSilenceService: recurringSilenceService,
+	}
+}
+
+func (c *defaultRecurringSilenceProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, silence := range file.RecurringSilences {
+			silence.Silence.Provenance = models.ProvenanceFile
+			_, err := c.recurringSilenceService.GetRecurringSilence(ctx, silence.OrgID, silence.Silence.UID)
+			if err == nil {
+				if _, err := c.recurringSilenceService.UpdateRecurringSilence(ctx, silence.Silence, silence.OrgID); err != nil {
+					return err
+				}
+				continue
+			}
+			if !errors.Is(err, provisioning.ErrNotFound) {
+				return err
+			}
+			if _, err := c.recurringSilenceService.CreateRecurringSilence(ctx, silence.Silence, silence.OrgID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultRecurringSilenceProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deleteSilence := range file.DeleteRecurringSilences {
+			err := c.recurringSilenceService.DeleteRecurringSilence(ctx, deleteSilence.UID, deleteSilence.OrgID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -15,32 +15,36 @@ type OrgID int64
 
 type AlertingFile struct {
 	configVersion
-	Filename            string
-	Groups              []file.AlertRuleGroupWithFolderTitle
-	DeleteRules         []file.RuleDelete
-	ContactPoints       []ContactPoint
-	DeleteContactPoints []DeleteContactPoint
-	Policies            []NotificiationPolicy
-	ResetPolicies       []OrgID
-	MuteTimes           []MuteTime
-	DeleteMuteTimes     []DeleteMuteTime
-	Templates           []Template
-	DeleteTemplates     []DeleteTemplate
+	Filename                string
+	Groups                  []file.AlertRuleGroupWithFolderTitle
+	DeleteRules             []file.RuleDelete
+	ContactPoints           []ContactPoint
+	DeleteContactPoints     []DeleteContactPoint
+	Policies                []NotificiationPolicy
+	ResetPolicies           []OrgID
+	MuteTimes               []MuteTime
+	DeleteMuteTimes         []DeleteMuteTime
+	RecurringSilences       []RecurringSilence
+	DeleteRecurringSilences []DeleteRecurringSilence
+	Templates               []Template
+	DeleteTemplates         []DeleteTemplate
 }
 
 type AlertingFileV1 struct {
 	configVersion
-	Filename            string
-	Groups              []file.AlertRuleGroupV1 `json:"groups" yaml:"groups"`
-	DeleteRules         []file.RuleDeleteV1     `json:"deleteRules" yaml:"deleteRules"`
-	ContactPoints       []ContactPointV1        `json:"contactPoints" yaml:"contactPoints"`
-	DeleteContactPoints []DeleteContactPointV1  `json:"deleteContactPoints" yaml:"deleteContactPoints"`
-	Policies            []NotificiationPolicyV1 `json:"policies" yaml:"policies"`
-	ResetPolicies       []values.Int64Value     `json:"resetPolicies" yaml:"resetPolicies"`
-	MuteTimes           []MuteTimeV1            `json:"muteTimes" yaml:"muteTimes"`
-	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
-	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
-	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	Filename                string
+	Groups                  []file.AlertRuleGroupV1    `json:"groups" yaml:"groups"`
+	DeleteRules             []file.RuleDeleteV1        `json:"deleteRules" yaml:"deleteRules"`
+	ContactPoints           []ContactPointV1           `json:"contactPoints" yaml:"contactPoints"`
+	DeleteContactPoints     []DeleteContactPointV1     `json:"deleteContactPoints" yaml:"deleteContactPoints"`
+	Policies                []NotificiationPolicyV1    `json:"policies" yaml:"policies"`
+	ResetPolicies           []values.Int64Value        `json:"resetPolicies" yaml:"resetPolicies"`
+	MuteTimes               []MuteTimeV1               `json:"muteTimes" yaml:"muteTimes"`
+	DeleteMuteTimes         []DeleteMuteTimeV1         `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
+	RecurringSilences       []RecurringSilenceV1       `json:"recurringSilences" yaml:"recurringSilences"`
+	DeleteRecurringSilences []DeleteRecurringSilenceV1 `json:"deleteRecurringSilences" yaml:"deleteRecurringSilences"`
+	Templates               []TemplateV1               `json:"templates" yaml:"templates"`
+	DeleteTemplates         []DeleteTemplateV1         `json:"deleteTemplates" yaml:"deleteTemplates"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -58,6 +62,9 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapMuteTimes(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing mute times: %w", err)
 	}
+	if err := fileV1.mapRecurringSilences(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing recurring silences: %w", err)
+	}
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
@@ -92,6 +99,24 @@ func (fileV1 *AlertingFileV1) mapMuteTimes(alertingFile *AlertingFile) error {
 	return nil
 }
 
+func (fileV1 *AlertingFileV1) mapRecurringSilences(alertingFile *AlertingFile) error {
+	for _, silenceV1 := range fileV1.RecurringSilences {
+		silence, err := silenceV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.RecurringSilences = append(alertingFile.RecurringSilences, silence)
+	}
+	for _, deleteV1 := range fileV1.DeleteRecurringSilences {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteRecurringSilences = append(alertingFile.DeleteRecurringSilences, delReq)
+	}
+	return nil
+}
+
 func (fileV1 *AlertingFileV1) mapPolicies(alertingFile *AlertingFile) error {
 	for _, npV1 := range fileV1.Policies {
 		np, err := npV1.mapToModel()
@@ -0,0 +1,498 @@
+package query
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	incrementalResultIncremental = "incremental"
+	incrementalResultFull        = "full"
+	incrementalResultFallback    = "fallback"
+)
+
+var incrementalQueryRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "grafana",
+	Name:      "query_incremental_requests_total",
+	Help:      "The total amount of data source queries handled by incremental querying",
+}, []string{"plugin_id", "result"})
+
+// incrementalQuerier remembers the time series returned for the queries of dashboard panels.
+// When a panel is refreshed, it queries the data source only for the data since the last returned point,
+// plus an overlap, and merges it with the remembered time series.
+type incrementalQuerier struct {
+	settings setting.IncrementalQueryingSettings
+	cache    *incrementalCache
+	log      log.Logger
+}
+
+func newIncrementalQuerier(settings setting.IncrementalQueryingSettings) *incrementalQuerier {
+	return &incrementalQuerier{
+		settings: settings,
+		cache:    newIncrementalCache(settings.MaxAge, settings.MaxSize),
+		log:      log.New("query_data.incremental"),
+	}
+}
+
+// incrementalEntry is the result of a query. It is never modified once it is stored.
+type incrementalEntry struct {
+	timeRange backend.TimeRange
+	// step is the distance between the points of the time series.
+	step time.Duration
+	// last is the time of the most recent point of all time series.
+	last   time.Time
+	frames data.Frames
+}
+
+// size returns the approximate size in bytes of the time series of the entry.
+func (e *incrementalEntry) size() int64 {
+	var size int64
+	for _, frame := range e.frames {
+		for _, field := range frame.Fields {
+			size += fieldSize(field)
+		}
+	}
+	return size
+}
+
+// incrementalCache keeps the entries of the queries until they are older than the max age. When the entries
+// are larger than the max size in total, the least recently used ones are evicted.
+type incrementalCache struct {
+	maxAge time.Duration
+	// maxSize is the maximum size in bytes of the entries, there is no limit if it is 0.
+	maxSize int64
+
+	mtx   sync.Mutex
+	size  int64
+	items map[string]*list.Element
+	// lru has the most recently used items at the front.
+	lru *list.List
+}
+
+type incrementalCacheItem struct {
+	key     string
+	entry   *incrementalEntry
+	size    int64
+	expires time.Time
+}
+
+func newIncrementalCache(maxAge time.Duration, maxSize int64) *incrementalCache {
+	return &incrementalCache{
+		maxAge:  maxAge,
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (c *incrementalCache) get(key string) (*incrementalEntry, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := elem.Value.(*incrementalCacheItem)
+	if !time.Now().Before(item.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return item.entry, true
+}
+
+// set stores the entry of a query. Entries larger than the max size are not stored.
+func (c *incrementalCache) set(key string, entry *incrementalEntry) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+	now := time.Now()
+	item := &incrementalCacheItem{key: key, entry: entry, size: entry.size(), expires: now.Add(c.maxAge)}
+	if c.maxSize > 0 && item.size > c.maxSize {
+		return
+	}
+	c.items[key] = c.lru.PushFront(item)
+	c.size += item.size
+
+	// Entries are stored again every time they are used, so the least recently used entries expire first.
+	for elem := c.lru.Back(); elem != nil; elem = c.lru.Back() {
+		item := elem.Value.(*incrementalCacheItem)
+		if (c.maxSize == 0 || c.size <= c.maxSize) && now.Before(item.expires) {
+			break
+		}
+		c.remove(elem)
+	}
+}
+
+func (c *incrementalCache) delete(key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+}
+
+func (c *incrementalCache) remove(elem *list.Element) {
+	item := c.lru.Remove(elem).(*incrementalCacheItem)
+	delete(c.items, item.key)
+	c.size -= item.size
+}
+
+// narrowedQuery is a query whose time range was narrowed to the data since the last refresh.
+type narrowedQuery struct {
+	original backend.DataQuery
+	entry    *incrementalEntry
+	tailFrom time.Time
+}
+
+func (q *incrementalQuerier) supports(dsType string) bool {
+	return q.settings.DatasourceTypes[dsType]
+}
+
+// queryData queries the data source for the queries of a dashboard panel, narrowing the time range of the queries
+// that were made before. Requests that do not come from a dashboard panel are passed through.
+func (q *incrementalQuerier) queryData(ctx context.Context, client plugins.Client, user *user.SignedInUser, ds *datasources.DataSource, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	reqCtx := contexthandler.FromContext(ctx)
+	if user == nil || reqCtx == nil || reqCtx.Req == nil {
+		return client.QueryData(ctx, req)
+	}
+	dashboardUID := reqCtx.Req.Header.Get(HeaderDashboardUID)
+	panelID := reqCtx.Req.Header.Get(HeaderPanelID)
+	if dashboardUID == "" || panelID == "" {
+		return client.QueryData(ctx, req)
+	}
+
+	pluginID := req.PluginContext.PluginID
+	keys := make(map[string]string, len(req.Queries))
+	timeRanges := make(map[string]backend.TimeRange, len(req.Queries))
+	narrowed := make(map[string]narrowedQuery)
+	queries := make([]backend.DataQuery, len(req.Queries))
+	for i, query := range req.Queries {
+		queries[i] = query
+		if !isIncrementalQuery(query) {
+			continue
+		}
+		key, err := incrementalKey(user, ds, dashboardUID, panelID, query)
+		if err != nil {
+			q.log.Warn("Failed to calculate incremental query key", "error", err)
+			continue
+		}
+		keys[query.RefID] = key
+		timeRanges[query.RefID] = query.TimeRange
+		if reqCtx.SkipCache {
+			continue
+		}
+		entry, ok := q.get(key)
+		if !ok {
+			continue
+		}
+		tailFrom, ok := entry.tailFrom(query.TimeRange, q.settings.Overlap)
+		if !ok {
+			continue
+		}
+		narrowed[query.RefID] = narrowedQuery{original: query, entry: entry, tailFrom: tailFrom}
+		queries[i] = narrowQuery(query, tailFrom)
+	}
+
+	narrowedReq := *req
+	narrowedReq.Queries = queries
+	resp, err := client.QueryData(ctx, &narrowedReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var fallback []backend.DataQuery
+	for refID, nq := range narrowed {
+		dr, ok := resp.Responses[refID]
+		if !ok || dr.Error != nil {
+			continue
+		}
+		frames, ok := nq.entry.merge(dr.Frames, nq.original.TimeRange, nq.tailFrom)
+		if !ok {
+			q.log.Debug("Querying the whole time range because the new data does not match the previous data", "refId", refID)
+			incrementalQueryRequests.WithLabelValues(pluginID, incrementalResultFallback).Inc()
+			fallback = append(fallback, nq.original)
+			continue
+		}
+		incrementalQueryRequests.WithLabelValues(pluginID, incrementalResultIncremental).Inc()
+		dr.Frames = frames
+		resp.Responses[refID] = dr
+	}
+	for refID := range keys {
+		if _, ok := narrowed[refID]; !ok {
+			incrementalQueryRequests.WithLabelValues(pluginID, incrementalResultFull).Inc()
+		}
+	}
+
+	if len(fallback) > 0 {
+		fallbackReq := *req
+		fallbackReq.Queries = fallback
+		fallbackResp, err := client.QueryData(ctx, &fallbackReq)
+		for _, query := range fallback {
+			if err != nil {
+				resp.Responses[query.RefID] = backend.DataResponse{Error: err}
+				continue
+			}
+			resp.Responses[query.RefID] = fallbackResp.Responses[query.RefID]
+		}
+	}
+
+	for refID, key := range keys {
+		if entry, ok := newIncrementalEntry(timeRanges[refID], resp.Responses[refID]); ok {
+			q.cache.set(key, entry)
+		} else {
+			q.cache.delete(key)
+		}
+	}
+
+	return resp, nil
+}
+
+func (q *incrementalQuerier) get(key string) (*incrementalEntry, bool) {
+	return q.cache.get(key)
+}
+
+// isIncrementalQuery returns false for queries whose result cannot be merged with the result of a query of
+// a different time range: instant queries, and queries that depend on the length of the time range.
+func isIncrementalQuery(query backend.DataQuery) bool {
+	if !query.TimeRange.To.After(query.TimeRange.From) {
+		return false
+	}
+	if bytes.Contains(query.JSON, []byte("__range")) {
+		return false
+	}
+	var model struct {
+		Instant   bool   `json:"instant"`
+		QueryType string `json:"queryType"`
+	}
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return false
+	}
+	return !model.Instant && model.QueryType != "instant"
+}
+
+type incrementalKeyData struct {
+	OrgID             int64           `json:"orgId"`
+	UserID            int64           `json:"userId"`
+	APIKeyID          int64           `json:"apiKeyId"`
+	DatasourceUID     string          `json:"datasourceUid"`
+	DatasourceVersion int             `json:"datasourceVersion"`
+	DashboardUID      string          `json:"dashboardUid"`
+	PanelID           string          `json:"panelId"`
+	RefID             string          `json:"refId"`
+	QueryType         string          `json:"queryType"`
+	MaxDataPoints     int64           `json:"maxDataPoints"`
+	Interval          time.Duration   `json:"interval"`
+	Range             time.Duration   `json:"range"`
+	JSON              json.RawMessage `json:"json"`
+}
+
+// incrementalKey identifies a query of a panel made by a user. Queries for time ranges of different lengths
+// have different keys, because the data source returns their time series with a different step.
+func incrementalKey(user *user.SignedInUser, ds *datasources.DataSource, dashboardUID, panelID string, query backend.DataQuery) (string, error) {
+	b, err := json.Marshal(incrementalKeyData{
+		OrgID:             user.OrgID,
+		UserID:            user.UserID,
+		APIKeyID:          user.ApiKeyID,
+		DatasourceUID:     ds.Uid,
+		DatasourceVersion: ds.Version,
+		DashboardUID:      dashboardUID,
+		PanelID:           panelID,
+		RefID:             query.RefID,
+		QueryType:         query.QueryType,
+		MaxDataPoints:     query.MaxDataPoints,
+		Interval:          query.Interval,
+		Range:             query.TimeRange.To.Sub(query.TimeRange.From),
+		JSON:              query.JSON,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// narrowQuery returns the query for the data since tailFrom. The max data points are reduced in proportion to
+// the time range, so that the data source calculates the same step as for the whole time range.
+func narrowQuery(query backend.DataQuery, tailFrom time.Time) backend.DataQuery {
+	full := query.TimeRange.To.Sub(query.TimeRange.From)
+	tail := query.TimeRange.To.Sub(tailFrom)
+	if query.MaxDataPoints > 0 {
+		query.MaxDataPoints = (query.MaxDataPoints*int64(tail) + int64(full) - 1) / int64(full)
+		if query.MaxDataPoints < 1 {
+			query.MaxDataPoints = 1
+		}
+	}
+	query.TimeRange.From = tailFrom
+	return query
+}
+
+// newIncrementalEntry returns the entry of a query response, or false if the response cannot be merged
+// with the response of a later query.
+func newIncrementalEntry(timeRange backend.TimeRange, dr backend.DataResponse) (*incrementalEntry, bool) {
+	if dr.Error != nil || dr.Status >= 400 || !isTimeSeries(dr.Frames) {
+		return nil, false
+	}
+	step := seriesStep(dr.Frames)
+	if step <= 0 {
+		return nil, false
+	}
+	entry := &incrementalEntry{timeRange: timeRange, step: step, frames: dr.Frames}
+	for _, frame := range dr.Frames {
+		if n := frame.Rows(); n > 0 {
+			if t := timeAt(frame, n-1); t.After(entry.last) {
+				entry.last = t
+			}
+		}
+	}
+	return entry, true
+}
+
+// tailFrom returns the start of the time range that has to be queried to refresh the entry for the given
+// time range. It is a whole number of steps before the last point, so that the new points are aligned
+// with the previous ones.
+func (e *incrementalEntry) tailFrom(timeRange backend.TimeRange, overlap time.Duration) (time.Time, bool) {
+	if timeRange.From.Before(e.timeRange.From) || timeRange.To.Before(e.timeRange.To) {
+		return time.Time{}, false
+	}
+	steps := (overlap + e.step - 1) / e.step
+	tailFrom := e.last.Add(-steps * e.step)
+	if !tailFrom.After(timeRange.From) || !tailFrom.Before(timeRange.To) {
+		return time.Time{}, false
+	}
+	return tailFrom, true
+}
+
+// merge returns the time series of the entry within the time range, with the points since tailFrom replaced
+// by the given ones. It returns false if the given time series do not match the time series of the entry.
+func (e *incrementalEntry) merge(tail data.Frames, timeRange backend.TimeRange, tailFrom time.Time) (data.Frames, bool) {
+	if !isTimeSeries(tail) {
+		return nil, false
+	}
+	if step := seriesStep(tail); step > 0 && step != e.step {
+		return nil, false
+	}
+	tailByKey := make(map[string]*data.Frame, len(tail))
+	for _, frame := range tail {
+		for i := 0; i < frame.Rows(); i++ {
+			if timeAt(frame, i).Sub(e.last)%e.step != 0 {
+				return nil, false
+			}
+		}
+		tailByKey[seriesKey(frame)] = frame
+	}
+
+	merged := make(data.Frames, 0, len(e.frames)+len(tail))
+	for _, frame := range e.frames {
+		key := seriesKey(frame)
+		newFrame, cut := frame, tailFrom
+		if tailFrame, ok := tailByKey[key]; ok {
+			if tailFrame.Fields[1].Type() != frame.Fields[1].Type() {
+				return nil, false
+			}
+			newFrame = tailFrame
+			if tailFrame.Rows() > 0 {
+				cut = timeAt(tailFrame, 0)
+			}
+		}
+		out := newFrame.EmptyCopy()
+		for i := 0; i < frame.Rows(); i++ {
+			if t := timeAt(frame, i); !t.Before(timeRange.From) && t.Before(cut) {
+				out.AppendRow(frame.RowCopy(i)...)
+			}
+		}
+		if newFrame != frame {
+			for i := 0; i < newFrame.Rows(); i++ {
+				out.AppendRow(newFrame.RowCopy(i)...)
+			}
+			delete(tailByKey, key)
+		}
+		if out.Rows() > 0 {
+			merged = append(merged, out)
+		}
+	}
+	// time series that were not returned before
+	for _, frame := range tail {
+		if _, ok := tailByKey[seriesKey(frame)]; ok {
+			merged = append(merged, frame)
+		}
+	}
+	return merged, true
+}
+
+// isTimeSeries returns true if every frame is a single time series with points in ascending order of time.
+func isTimeSeries(frames data.Frames) bool {
+	for _, frame := range frames {
+		if frame.Meta == nil || frame.Meta.Type != data.FrameTypeTimeSeriesMulti || len(frame.Fields) != 2 ||
+			frame.Fields[0].Type() != data.FieldTypeTime {
+			return false
+		}
+		for i := 1; i < frame.Rows(); i++ {
+			if !timeAt(frame, i).After(timeAt(frame, i-1)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// seriesStep returns the smallest distance between two points of the time series, or zero if no time series
+// has more than one point.
+func seriesStep(frames data.Frames) time.Duration {
+	var step time.Duration
+	for _, frame := range frames {
+		for i := 1; i < frame.Rows(); i++ {
+			if d := timeAt(frame, i).Sub(timeAt(frame, i-1)); step == 0 || d < step {
+				step = d
+			}
+		}
+	}
+	return step
+}
+
+// fieldSize returns the approximate size in bytes of the values of a field.
+func fieldSize(field *data.Field) int64 {
+	switch field.Type() {
+	case data.FieldTypeString, data.FieldTypeNullableString:
+		var size int64
+		for i := 0; i < field.Len(); i++ {
+			if v, ok := field.ConcreteAt(i); ok {
+				size += int64(len(v.(string)))
+			}
+		}
+		return size
+	case data.FieldTypeTime, data.FieldTypeNullableTime:
+		return int64(field.Len()) * 24
+	default:
+		return int64(field.Len()) * 8
+	}
+}
+
+func seriesKey(frame *data.Frame) string {
+	field := frame.Fields[1]
+	return frame.Name + "\x00" + field.Name + "\x00" + field.Labels.String()
+}
+
+func timeAt(frame *data.Frame, i int) time.Time {
+	return frame.Fields[0].At(i).(time.Time)
+}
@@ -0,0 +1,258 @@
+package query
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/manager/client/clienttest"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// seriesFrame returns a time series with a point at every step within the time range, like Prometheus does.
+func seriesFrame(instance string, from, to time.Time, step time.Duration) *data.Frame {
+	timeField := data.NewFieldFromFieldType(data.FieldTypeTime, 0)
+	timeField.Name = data.TimeSeriesTimeFieldName
+	valueField := data.NewFieldFromFieldType(data.FieldTypeFloat64, 0)
+	valueField.Name = data.TimeSeriesValueFieldName
+	valueField.Labels = data.Labels{"instance": instance}
+	for t := from.Truncate(step); !t.After(to); t = t.Add(step) {
+		if t.Before(from) {
+			continue
+		}
+		timeField.Append(t)
+		valueField.Append(float64(t.Unix()))
+	}
+	frame := data.NewFrame("", timeField, valueField)
+	frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti}
+	return frame
+}
+
+func TestIncrementalQuerying(t *testing.T) {
+	settings := setting.IncrementalQueryingSettings{
+		Enabled:         true,
+		DatasourceTypes: map[string]bool{"prometheus": true},
+		Overlap:         time.Minute,
+		MaxAge:          10 * time.Minute,
+	}
+	ds := &datasources.DataSource{Uid: "prom", Type: "prometheus", Version: 1}
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	type call struct {
+		timeRange     backend.TimeRange
+		maxDataPoints int64
+	}
+
+	setup := func(t *testing.T, panelID string) (*incrementalQuerier, *clienttest.TestClient, context.Context, *time.Duration, *[]call) {
+		httpreq, err := http.NewRequest(http.MethodPost, "http://localhost/api/ds/query", nil)
+		require.NoError(t, err)
+		if panelID != "" {
+			httpreq.Header.Set(HeaderDashboardUID, "dashboard")
+			httpreq.Header.Set(HeaderPanelID, panelID)
+		}
+		reqCtx := &contextmodel.ReqContext{Context: &web.Context{Req: httpreq}}
+		ctx := ctxkey.Set(context.Background(), reqCtx)
+
+		step := 15 * time.Second
+		var calls []call
+		client := &clienttest.TestClient{
+			QueryDataFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				resp := backend.NewQueryDataResponse()
+				for _, q := range req.Queries {
+					calls = append(calls, call{timeRange: q.TimeRange, maxDataPoints: q.MaxDataPoints})
+					resp.Responses[q.RefID] = backend.DataResponse{Frames: data.Frames{
+						seriesFrame("a", q.TimeRange.From, q.TimeRange.To, step),
+					}}
+				}
+				return resp, nil
+			},
+		}
+		return newIncrementalQuerier(settings), client, ctx, &step, &calls
+	}
+
+	queryReq := func(to time.Time, model string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1, PluginID: "prometheus"},
+			Queries: []backend.DataQuery{{
+				RefID:         "A",
+				MaxDataPoints: 1440,
+				Interval:      15 * time.Second,
+				JSON:          []byte(model),
+				TimeRange:     backend.TimeRange{From: to.Add(-6 * time.Hour), To: to},
+			}},
+		}
+	}
+
+	t.Run("queries only the new data when a panel is refreshed", func(t *testing.T) {
+		q, client, ctx, step, calls := setup(t, "1")
+
+		_, err := q.queryData(ctx, client, signedInUser, ds, queryReq(now, `{"expr": "up"}`))
+		require.NoError(t, err)
+		refreshed := now.Add(30 * time.Second)
+		resp, err := q.queryData(ctx, client, signedInUser, ds, queryReq(refreshed, `{"expr": "up"}`))
+		require.NoError(t, err)
+
+		require.Len(t, *calls, 2)
+		tail := (*calls)[1]
+		require.Equal(t, now.Add(-time.Minute), tail.timeRange.From)
+		require.Equal(t, refreshed, tail.timeRange.To)
+		require.Equal(t, int64(6), tail.maxDataPoints)
+
+		expected := seriesFrame("a", refreshed.Add(-6*time.Hour), refreshed, *step)
+		require.Len(t, resp.Responses["A"].Frames, 1)
+		require.Equal(t, expected.Fields[0].Len(), resp.Responses["A"].Frames[0].Fields[0].Len())
+		for i := 0; i < expected.Rows(); i++ {
+			require.Equal(t, expected.RowCopy(i), resp.Responses["A"].Frames[0].RowCopy(i))
+		}
+	})
+
+	t.Run("queries the whole time range when the step of the new data is different", func(t *testing.T) {
+		q, client, ctx, step, calls := setup(t, "1")
+
+		_, err := q.queryData(ctx, client, signedInUser, ds, queryReq(now, `{"expr": "up"}`))
+		require.NoError(t, err)
+		*step = 20 * time.Second
+		refreshed := now.Add(time.Minute)
+		resp, err := q.queryData(ctx, client, signedInUser, ds, queryReq(refreshed, `{"expr": "up"}`))
+		require.NoError(t, err)
+
+		require.Len(t, *calls, 3)
+		require.Equal(t, refreshed.Add(-6*time.Hour), (*calls)[2].timeRange.From)
+		expected := seriesFrame("a", refreshed.Add(-6*time.Hour), refreshed, *step)
+		require.Equal(t, expected.Rows(), resp.Responses["A"].Frames[0].Rows())
+	})
+
+	t.Run("queries the whole time range of queries that depend on its length", func(t *testing.T) {
+		q, client, ctx, _, calls := setup(t, "1")
+
+		for _, to := range []time.Time{now, now.Add(30 * time.Second)} {
+			_, err := q.queryData(ctx, client, signedInUser, ds, queryReq(to, `{"expr": "increase(up[$__range])"}`))
+			require.NoError(t, err)
+		}
+		require.Len(t, *calls, 2)
+		require.Equal(t, now.Add(30*time.Second-6*time.Hour), (*calls)[1].timeRange.From)
+	})
+
+	t.Run("queries the whole time range of instant queries", func(t *testing.T) {
+		q, client, ctx, _, calls := setup(t, "1")
+
+		for _, to := range []time.Time{now, now.Add(30 * time.Second)} {
+			_, err := q.queryData(ctx, client, signedInUser, ds, queryReq(to, `{"expr": "up", "instant": true}`))
+			require.NoError(t, err)
+		}
+		require.Equal(t, now.Add(30*time.Second-6*time.Hour), (*calls)[1].timeRange.From)
+	})
+
+	t.Run("queries the whole time range of queries that are not made by a panel", func(t *testing.T) {
+		q, client, ctx, _, calls := setup(t, "")
+
+		for _, to := range []time.Time{now, now.Add(30 * time.Second)} {
+			_, err := q.queryData(ctx, client, signedInUser, ds, queryReq(to, `{"expr": "up"}`))
+			require.NoError(t, err)
+		}
+		require.Equal(t, now.Add(30*time.Second-6*time.Hour), (*calls)[1].timeRange.From)
+	})
+
+	t.Run("does not share the data of a panel between users", func(t *testing.T) {
+		q, client, ctx, _, calls := setup(t, "1")
+
+		_, err := q.queryData(ctx, client, signedInUser, ds, queryReq(now, `{"expr": "up"}`))
+		require.NoError(t, err)
+		_, err = q.queryData(ctx, client, &user.SignedInUser{UserID: 2, OrgID: 1}, ds, queryReq(now.Add(30*time.Second), `{"expr": "up"}`))
+		require.NoError(t, err)
+		require.Equal(t, now.Add(30*time.Second-6*time.Hour), (*calls)[1].timeRange.From)
+	})
+}
+
+func TestIncrementalEntryMerge(t *testing.T) {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	step := 15 * time.Second
+	entry, ok := newIncrementalEntry(backend.TimeRange{From: now.Add(-time.Hour), To: now}, backend.DataResponse{
+		Frames: data.Frames{
+			seriesFrame("a", now.Add(-time.Hour), now, step),
+			seriesFrame("gone", now.Add(-time.Hour), now.Add(-30*time.Minute), step),
+			seriesFrame("stopped", now.Add(-time.Hour), now.Add(-2*time.Minute), step),
+		},
+	})
+	require.True(t, ok)
+	require.Equal(t, now, entry.last)
+	require.Equal(t, step, entry.step)
+
+	timeRange := backend.TimeRange{From: now.Add(-30*time.Minute + step), To: now.Add(time.Minute)}
+	tailFrom, ok := entry.tailFrom(timeRange, time.Minute)
+	require.True(t, ok)
+	require.Equal(t, now.Add(-time.Minute), tailFrom)
+
+	merged, ok := entry.merge(data.Frames{
+		seriesFrame("a", tailFrom, timeRange.To, step),
+		seriesFrame("new", now, timeRange.To, step),
+	}, timeRange, tailFrom)
+	require.True(t, ok)
+
+	// the series that ended before the time range is dropped
+	require.Len(t, merged, 3)
+	require.Equal(t, seriesFrame("a", timeRange.From, timeRange.To, step).Rows(), merged[0].Rows())
+	require.Equal(t, seriesFrame("stopped", timeRange.From, now.Add(-2*time.Minute), step).Rows(), merged[1].Rows())
+	require.Equal(t, data.Labels{"instance": "new"}, merged[2].Fields[1].Labels)
+
+	t.Run("returns false if the new points have a different step", func(t *testing.T) {
+		_, ok := entry.merge(data.Frames{seriesFrame("a", tailFrom, timeRange.To, 20*time.Second)}, timeRange, tailFrom)
+		require.False(t, ok)
+	})
+}
+
+func TestIncrementalCache(t *testing.T) {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	// Each entry has 10 points of 32 bytes.
+	newEntry := func(instance string) *incrementalEntry {
+		return &incrementalEntry{frames: data.Frames{seriesFrame(instance, now.Add(-9*time.Second), now, time.Second)}}
+	}
+	require.Equal(t, int64(320), newEntry("a").size())
+
+	t.Run("evicts the least recently used entries when the max size is exceeded", func(t *testing.T) {
+		cache := newIncrementalCache(time.Hour, 1000)
+		cache.set("a", newEntry("a"))
+		cache.set("b", newEntry("b"))
+		cache.set("c", newEntry("c"))
+		_, ok := cache.get("a")
+		require.True(t, ok)
+
+		cache.set("d", newEntry("d"))
+		_, ok = cache.get("b")
+		require.False(t, ok)
+		for _, key := range []string{"a", "c", "d"} {
+			_, ok := cache.get(key)
+			require.True(t, ok, key)
+		}
+		require.Equal(t, int64(960), cache.size)
+	})
+
+	t.Run("does not keep entries larger than the max size", func(t *testing.T) {
+		cache := newIncrementalCache(time.Hour, 300)
+		cache.set("a", newEntry("a"))
+		_, ok := cache.get("a")
+		require.False(t, ok)
+		require.Zero(t, cache.size)
+	})
+
+	t.Run("entries expire after the max age", func(t *testing.T) {
+		cache := newIncrementalCache(time.Millisecond, 0)
+		cache.set("a", newEntry("a"))
+		time.Sleep(2 * time.Millisecond)
+		cache.set("b", newEntry("b"))
+		require.Len(t, cache.items, 1)
+		_, ok := cache.get("a")
+		require.False(t, ok)
+	})
+}
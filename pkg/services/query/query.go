@@ -47,6 +47,9 @@ func ProvideService(
 		pluginClient:           pluginClient,
 		log:                    log.New("query_data"),
 	}
+	if cfg.IncrementalQuerying.Enabled {
+		g.incremental = newIncrementalQuerier(cfg.IncrementalQuerying)
+	}
 	g.log.Info("Query Service initialization")
 	return g
 }
@@ -58,6 +61,7 @@ type Service struct {
 	pluginRequestValidator validations.PluginRequestValidator
 	dataSourceService      datasources.DataSourceService
 	pluginClient           plugins.Client
+	incremental            *incrementalQuerier
 	log                    log.Logger
 }
 
@@ -233,6 +237,9 @@ func (s *Service) handleQuerySingleDatasource(ctx context.Context, user *user.Si
 		req.Queries = append(req.Queries, q.query)
 	}
 
+	if s.incremental != nil && s.incremental.supports(ds.Type) {
+		return s.incremental.queryData(ctx, s.pluginClient, user, ds, req)
+	}
 	return s.pluginClient.QueryData(ctx, req)
 }
 
@@ -20,6 +20,26 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	// should come in the form of a new migration appended to the end of AddTablesMigrations
 	// instead of modifying an existing one. This ensure that tables are modified in a consistent and correct order.
 	historicalTableMigrations(mg)
+
+	addAlertStateHistoryMigrations(mg)
+
+	addRecordingRuleColumnsMigration(mg)
+
+	addRuleDependenciesColumnsMigration(mg)
+
+	addKeepFiringForColumnMigration(mg)
+
+	addNotificationHistoryMigrations(mg)
+
+	addRecurringSilenceMigrations(mg)
+
+	addNotifierStateMigrations(mg)
+
+	addAlertRuleTemplateMigrations(mg)
+
+	addParentOrgColumnMigration(mg)
+
+	addKeepFiringSinceColumnMigration(mg)
 }
 
 // historicalTableMigrations contains those migrations that existed prior to creating the improved messaging around migration immutability.
@@ -553,3 +573,210 @@ func (c extractAlertmanagerConfigurationHistory) Exec(sess *xorm.Session, migrat
 	}
 	return nil
 }
+
+func addAlertStateHistoryMigrations(mg *migrator.Migrator) {
+	stateHistory := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "data", Type: migrator.DB_Text, Nullable: false},
+			{Name: "at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid", "at"}, Type: migrator.IndexType},
+			{Cols: []string{"at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(stateHistory))
+	mg.AddMigration("add index in alert_state_history on org_id, rule_uid and at columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[0]))
+	mg.AddMigration("add index in alert_state_history on at column", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[1]))
+}
+
+func addRecordingRuleColumnsMigration(mg *migrator.Migrator) {
+	mg.AddMigration("add record column to alert_rule table", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{
+			Name:     "record",
+			Type:     migrator.DB_Text,
+			Nullable: true,
+		},
+	))
+
+	mg.AddMigration("add record column to alert_rule_version table", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{
+			Name:     "record",
+			Type:     migrator.DB_Text,
+			Nullable: true,
+		},
+	))
+}
+
+func addRuleDependenciesColumnsMigration(mg *migrator.Migrator) {
+	mg.AddMigration("add depends_on column to alert_rule table", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{
+			Name:     "depends_on",
+			Type:     migrator.DB_Text,
+			Nullable: true,
+		},
+	))
+
+	mg.AddMigration("add depends_on column to alert_rule_version table", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{
+			Name:     "depends_on",
+			Type:     migrator.DB_Text,
+			Nullable: true,
+		},
+	))
+}
+
+func addKeepFiringForColumnMigration(mg *migrator.Migrator) {
+	mg.AddMigration("add keep_firing_for column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{Name: "keep_firing_for", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+	))
+
+	mg.AddMigration("add keep_firing_for column to alert_rule_version", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{Name: "keep_firing_for", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+	))
+}
+
+func addNotificationHistoryMigrations(mg *migrator.Migrator) {
+	notificationHistory := migrator.Table{
+		Name: "alert_notification_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "integration_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "fingerprints", Type: migrator.DB_Text, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: false},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "at"}, Type: migrator.IndexType},
+			{Cols: []string{"at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_history table", migrator.NewAddTableMigration(notificationHistory))
+	mg.AddMigration("add index in alert_notification_history on org_id and at columns", migrator.NewAddIndexMigration(notificationHistory, notificationHistory.Indices[0]))
+	mg.AddMigration("add index in alert_notification_history on at column", migrator.NewAddIndexMigration(notificationHistory, notificationHistory.Indices[1]))
+}
+
+func addRecurringSilenceMigrations(mg *migrator.Migrator) {
+	recurringSilence := migrator.Table{
+		Name: "alert_recurring_silence",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "comment", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "matchers", Type: migrator.DB_Text, Nullable: false},
+			{Name: "cron", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "time_intervals", Type: migrator.DB_Text, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_recurring_silence table", migrator.NewAddTableMigration(recurringSilence))
+	mg.AddMigration("add unique index in alert_recurring_silence on org_id and uid columns", migrator.NewAddIndexMigration(recurringSilence, recurringSilence.Indices[0]))
+}
+
+func addNotifierStateMigrations(mg *migrator.Migrator) {
+	notifierState := migrator.Table{
+		Name: "alert_notifier_state",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "kind", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "data", Type: migrator.DB_LongBlob, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "kind"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notifier_state table", migrator.NewAddTableMigration(notifierState))
+	mg.AddMigration("add index in alert_notifier_state on org_id and kind columns", migrator.NewAddIndexMigration(notifierState, notifierState.Indices[0]))
+}
+
+func addAlertRuleTemplateMigrations(mg *migrator.Migrator) {
+	template := migrator.Table{
+		Name: "alert_rule_template",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "description", Type: migrator.DB_Text, Nullable: false},
+			{Name: "parameters", Type: migrator.DB_Text, Nullable: false},
+			{Name: "rule", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_template table", migrator.NewAddTableMigration(template))
+	mg.AddMigration("add unique index in alert_rule_template on org_id and uid columns", migrator.NewAddIndexMigration(template, template.Indices[0]))
+
+	instance := migrator.Table{
+		Name: "alert_rule_template_instance",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "template_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "template_version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "parameter_values", Type: migrator.DB_Text, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "template_uid"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_template_instance table", migrator.NewAddTableMigration(instance))
+	mg.AddMigration("add unique index in alert_rule_template_instance on org_id and rule_uid columns", migrator.NewAddIndexMigration(instance, instance.Indices[0]))
+	mg.AddMigration("add index in alert_rule_template_instance on org_id and template_uid columns", migrator.NewAddIndexMigration(instance, instance.Indices[1]))
+}
+
+func addParentOrgColumnMigration(mg *migrator.Migrator) {
+	mg.AddMigration("add column parent_org_id in ngalert_configuration", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "ngalert_configuration"},
+		&migrator.Column{Name: "parent_org_id", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+	))
+}
+
+func addKeepFiringSinceColumnMigration(mg *migrator.Migrator) {
+	mg.AddMigration("add keep_firing_since column to alert_instance", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_instance"},
+		&migrator.Column{Name: "keep_firing_since", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+	))
+}
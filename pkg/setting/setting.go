@@ -362,6 +362,12 @@ type Cfg struct {
 	// DistributedCache
 	RemoteCacheOptions *RemoteCacheOptions
 
+	// Query caching
+	QueryCaching QueryCachingSettings
+
+	// Incremental querying
+	IncrementalQuerying IncrementalQueryingSettings
+
 	EditorsCanAdmin bool
 
 	ApiKeyMaxSecondsToLive int64
@@ -991,6 +997,14 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 		return err
 	}
 
+	if err := cfg.readQueryCachingSettings(iniFile); err != nil {
+		return err
+	}
+
+	if err := cfg.readIncrementalQueryingSettings(iniFile); err != nil {
+		return err
+	}
+
 	if err := readSecuritySettings(iniFile, cfg); err != nil {
 		return err
 	}
@@ -0,0 +1,30 @@
+package setting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+)
+
+// QueryCachingSettings configures the caching of data source query results.
+type QueryCachingSettings struct {
+	Enabled bool
+	// TTL is how long query results are cached, unless the data source overrides it.
+	TTL time.Duration
+	// MaxValueSize is the size in bytes of the largest query result that is cached.
+	MaxValueSize int64
+}
+
+func (cfg *Cfg) readQueryCachingSettings(iniFile *ini.File) error {
+	section := iniFile.Section("query_caching")
+	cfg.QueryCaching.Enabled = section.Key("enabled").MustBool(false)
+
+	var err error
+	cfg.QueryCaching.TTL, err = gtime.ParseDuration(valueAsString(section, "ttl", "5m"))
+	if err != nil {
+		return err
+	}
+	cfg.QueryCaching.MaxValueSize = section.Key("max_value_mb").MustInt64(1) * 1024 * 1024
+	return nil
+}
@@ -0,0 +1,45 @@
+package setting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// IncrementalQueryingSettings configures the incremental querying of time series when dashboards are refreshed.
+type IncrementalQueryingSettings struct {
+	Enabled bool
+	// DatasourceTypes are the types of the data sources whose queries are made incrementally.
+	DatasourceTypes map[string]bool
+	// Overlap is how much of the previously returned data is queried again, because recent points can still change.
+	Overlap time.Duration
+	// MaxAge is how long the results of a panel are kept after its last refresh.
+	MaxAge time.Duration
+	// MaxSize is the maximum size in bytes of the results that are kept, there is no limit if it is 0.
+	MaxSize int64
+}
+
+func (cfg *Cfg) readIncrementalQueryingSettings(iniFile *ini.File) error {
+	section := iniFile.Section("query_incremental")
+	cfg.IncrementalQuerying.Enabled = section.Key("enabled").MustBool(false)
+
+	cfg.IncrementalQuerying.DatasourceTypes = make(map[string]bool)
+	for _, dsType := range util.SplitString(valueAsString(section, "datasource_types", "prometheus loki")) {
+		cfg.IncrementalQuerying.DatasourceTypes[dsType] = true
+	}
+
+	var err error
+	cfg.IncrementalQuerying.Overlap, err = gtime.ParseDuration(valueAsString(section, "overlap", "10m"))
+	if err != nil {
+		return err
+	}
+	cfg.IncrementalQuerying.MaxAge, err = gtime.ParseDuration(valueAsString(section, "max_age", "10m"))
+	if err != nil {
+		return err
+	}
+	cfg.IncrementalQuerying.MaxSize = section.Key("max_size_mb").MustInt64(100) * 1024 * 1024
+	return nil
+}
@@ -53,13 +53,17 @@ const (
 	screenshotsMaxCaptureTimeout            = 30 * time.Second
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	screenshotsDefaultStorage               = ScreenshotStorageDisk
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
 	SchedulerBaseInterval = 10 * time.Second
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
-	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
-	stateHistoryDefaultEnabled    = true
+	DefaultRuleEvaluationInterval       = SchedulerBaseInterval * 6 // == 60 seconds
+	stateHistoryDefaultEnabled          = true
+	stateHistoryDefaultSQLRetention     = 30 * 24 * time.Hour
+	recordingRulesDefaultTimeout        = 10 * time.Second
+	notificationHistoryDefaultRetention = 7 * 24 * time.Hour
 )
 
 type UnifiedAlertingSettings struct {
@@ -71,6 +75,7 @@ type UnifiedAlertingSettings struct {
 	HAPeerTimeout                  time.Duration
 	HAGossipInterval               time.Duration
 	HAPushPullInterval             time.Duration
+	HAShardRuleEvaluation          bool
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
 	EvaluationTimeout              time.Duration
@@ -86,13 +91,24 @@ type UnifiedAlertingSettings struct {
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	RecordingRules                RecordingRuleSettings
+	NotificationHistory           NotificationHistorySettings
 }
 
+const (
+	// ScreenshotStorageDisk keeps screenshots on the disk of the Grafana server that took them.
+	ScreenshotStorageDisk = "disk"
+	// ScreenshotStorageDatabase stores screenshots in the Grafana database, from where any Grafana server
+	// serves them with a signed URL.
+	ScreenshotStorageDatabase = "database"
+)
+
 type UnifiedAlertingScreenshotSettings struct {
 	Capture                    bool
 	CaptureTimeout             time.Duration
 	MaxConcurrentScreenshots   int64
 	UploadExternalImageStorage bool
+	Storage                    string
 }
 
 type UnifiedAlertingReservedLabelSettings struct {
@@ -111,6 +127,25 @@ type UnifiedAlertingStateHistorySettings struct {
 	LokiBasicAuthPassword string
 	LokiBasicAuthUsername string
 	ExternalLabels        map[string]string
+	// SQLRetention is how long state transitions are kept by the "sql" backend before they are removed by the cleanup service.
+	SQLRetention time.Duration
+}
+
+// RecordingRuleSettings configures where the results of recording rules are written.
+type RecordingRuleSettings struct {
+	Enabled bool
+	// URL is the Prometheus remote write endpoint. If it is empty, the results are discarded.
+	URL               string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	Timeout           time.Duration
+}
+
+// NotificationHistorySettings configures the log of notification attempts of the Grafana Alertmanager.
+type NotificationHistorySettings struct {
+	Enabled bool
+	// Retention is how long notification attempts are kept before they are removed by the cleanup service.
+	Retention time.Duration
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -230,6 +265,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 			uaCfg.HAPeers = append(uaCfg.HAPeers, peer)
 		}
 	}
+	uaCfg.HAShardRuleEvaluation = ua.Key("ha_shard_rule_evaluation").MustBool(false)
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -308,6 +344,12 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfgScreenshots.MaxConcurrentScreenshots = screenshots.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent)
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
+	uaCfgScreenshots.Storage = screenshots.Key("storage").MustString(screenshotsDefaultStorage)
+	switch uaCfgScreenshots.Storage {
+	case ScreenshotStorageDisk, ScreenshotStorageDatabase:
+	default:
+		return fmt.Errorf("invalid value %q for setting 'storage', expected %q or %q", uaCfgScreenshots.Storage, ScreenshotStorageDisk, ScreenshotStorageDatabase)
+	}
 	uaCfg.Screenshots = uaCfgScreenshots
 
 	reservedLabels := iniFile.Section("unified_alerting.reserved_labels")
@@ -321,6 +363,10 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	stateHistory := iniFile.Section("unified_alerting.state_history")
 	stateHistoryLabels := iniFile.Section("unified_alerting.state_history.external_labels")
+	stateHistorySQLRetention, err := gtime.ParseDuration(valueAsString(stateHistory, "sql_retention", stateHistoryDefaultSQLRetention.String()))
+	if err != nil {
+		return err
+	}
 	uaCfgStateHistory := UnifiedAlertingStateHistorySettings{
 		Enabled:               stateHistory.Key("enabled").MustBool(stateHistoryDefaultEnabled),
 		Backend:               stateHistory.Key("backend").MustString("annotations"),
@@ -331,9 +377,33 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		LokiBasicAuthUsername: stateHistory.Key("loki_basic_auth_username").MustString(""),
 		LokiBasicAuthPassword: stateHistory.Key("loki_basic_auth_password").MustString(""),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		SQLRetention:          stateHistorySQLRetention,
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	recordingRules := iniFile.Section("unified_alerting.recording_rules")
+	recordingRulesTimeout, err := gtime.ParseDuration(valueAsString(recordingRules, "timeout", recordingRulesDefaultTimeout.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.RecordingRules = RecordingRuleSettings{
+		Enabled:           recordingRules.Key("enabled").MustBool(false),
+		URL:               recordingRules.Key("url").MustString(""),
+		BasicAuthUsername: recordingRules.Key("basic_auth_username").MustString(""),
+		BasicAuthPassword: recordingRules.Key("basic_auth_password").MustString(""),
+		Timeout:           recordingRulesTimeout,
+	}
+
+	notificationHistory := iniFile.Section("unified_alerting.notification_history")
+	notificationHistoryRetention, err := gtime.ParseDuration(valueAsString(notificationHistory, "retention", notificationHistoryDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.NotificationHistory = NotificationHistorySettings{
+		Enabled:   notificationHistory.Key("enabled").MustBool(true),
+		Retention: notificationHistoryRetention,
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
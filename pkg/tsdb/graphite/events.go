@@ -0,0 +1,163 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// isAnnotationQuery returns true for the queries of annotations.
+func isAnnotationQuery(query backend.DataQuery) bool {
+	var model eventsQueryModel
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return false
+	}
+	return model.FromAnnotations
+}
+
+// isEventsQuery returns true for annotation queries without a target, which return the Graphite events with the tags of the query.
+func isEventsQuery(query backend.DataQuery) bool {
+	var model eventsQueryModel
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return false
+	}
+	return model.FromAnnotations && model.Target == "" && model.TargetFull == ""
+}
+
+// handleEventsQuery returns the events in the time range of the query as an annotation frame.
+func (s *Service) handleEventsQuery(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) backend.DataResponse {
+	var model eventsQueryModel
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	from, until := epochMStoGraphiteTime(query.TimeRange)
+	params := url.Values{
+		"from":  []string{from},
+		"until": []string{until},
+	}
+	if len(model.Tags) > 0 {
+		params.Set("tags", strings.Join(model.Tags, " "))
+	}
+
+	body, status, err := s.doResourceRequest(ctx, logger, dsInfo, "events/get_data", params)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	if status/100 != 2 {
+		return backend.DataResponse{Error: fmt.Errorf("request failed, status: %d", status)}
+	}
+
+	var events []EventDTO
+	if err := json.Unmarshal(body, &events); err != nil {
+		logger.Info("Failed to unmarshal graphite events", "error", err, "body", string(body))
+		return backend.DataResponse{Error: err}
+	}
+
+	frame, err := eventsToFrame(query.RefID, events)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func eventsToFrame(refID string, events []EventDTO) (*data.Frame, error) {
+	times := make([]time.Time, 0, len(events))
+	titles := make([]string, 0, len(events))
+	texts := make([]string, 0, len(events))
+	tags := make([]json.RawMessage, 0, len(events))
+	for _, event := range events {
+		eventTags, err := parseEventTags(event.Tags)
+		if err != nil {
+			return nil, err
+		}
+		encodedTags, err := json.Marshal(eventTags)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, time.UnixMilli(int64(event.When*1000)).UTC())
+		titles = append(titles, event.What)
+		texts = append(texts, event.Data)
+		tags = append(tags, encodedTags)
+	}
+
+	return newAnnotationFrame(refID, times, titles, texts, tags), nil
+}
+
+// seriesToAnnotationFrame returns the annotations of an annotation query with a target, which are the points of
+// the time series that have a value other than zero. The title of an annotation is the name of its time series.
+func seriesToAnnotationFrame(refID string, frames data.Frames) *data.Frame {
+	times := []time.Time{}
+	titles := []string{}
+	texts := []string{}
+	tags := []json.RawMessage{}
+	for _, frame := range frames {
+		if len(frame.Fields) != 2 {
+			continue
+		}
+		title := frame.Name
+		if config := frame.Fields[1].Config; config != nil && config.DisplayNameFromDS != "" {
+			title = config.DisplayNameFromDS
+		}
+		for i := 0; i < frame.Rows(); i++ {
+			t, ok := frame.Fields[0].At(i).(time.Time)
+			if !ok {
+				continue
+			}
+			if value, ok := frame.Fields[1].At(i).(*float64); !ok || value == nil || *value == 0 {
+				continue
+			}
+			times = append(times, t)
+			titles = append(titles, title)
+			texts = append(texts, "")
+			tags = append(tags, json.RawMessage(`[]`))
+		}
+	}
+	return newAnnotationFrame(refID, times, titles, texts, tags)
+}
+
+func newAnnotationFrame(refID string, times []time.Time, titles, texts []string, tags []json.RawMessage) *data.Frame {
+	frame := data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("title", nil, titles),
+		data.NewField("text", nil, texts),
+		data.NewField("tags", nil, tags),
+	)
+	frame.RefID = refID
+	return frame
+}
+
+// parseEventTags returns the tags of an event, which are either a list or a string of tags separated by commas
+// or, if there is no comma, spaces.
+func parseEventTags(raw json.RawMessage) ([]string, error) {
+	tags := []string{}
+	if len(raw) == 0 || string(raw) == "null" {
+		return tags, nil
+	}
+	if err := json.Unmarshal(raw, &tags); err == nil {
+		return tags, nil
+	}
+
+	var tagString string
+	if err := json.Unmarshal(raw, &tagString); err != nil {
+		return nil, fmt.Errorf("invalid event tags: %s", string(raw))
+	}
+	separator := ","
+	if !strings.Contains(tagString, ",") {
+		separator = " "
+	}
+	for _, tag := range strings.Split(tagString, separator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,95 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestEventsQuery(t *testing.T) {
+	from := time.Unix(1600000000, 0)
+	s := setupGraphite(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/graphite/events/get_data", r.URL.Path)
+		require.Equal(t, "deploy prod", r.URL.Query().Get("tags"))
+		require.Equal(t, "1600000000", r.URL.Query().Get("from"))
+		require.Equal(t, "1600003600", r.URL.Query().Get("until"))
+		_, _ = w.Write([]byte(`[
+			{"when": 1600000100, "what": "Deployed v1", "data": "by ci", "tags": ["deploy", "prod"]},
+			{"when": 1600000200.5, "what": "Deployed v2", "data": "", "tags": "deploy prod"}
+		]`))
+	})
+
+	resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{
+			RefID:     "Anno",
+			JSON:      []byte(`{"fromAnnotations": true, "tags": ["deploy", "prod"]}`),
+			TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, resp.Responses["Anno"].Error)
+	require.Len(t, resp.Responses["Anno"].Frames, 1)
+
+	frame := resp.Responses["Anno"].Frames[0]
+	expected := data.NewFrame("annotations",
+		data.NewField("time", nil, []time.Time{time.Unix(1600000100, 0).UTC(), time.UnixMilli(1600000200500).UTC()}),
+		data.NewField("title", nil, []string{"Deployed v1", "Deployed v2"}),
+		data.NewField("text", nil, []string{"by ci", ""}),
+		data.NewField("tags", nil, []json.RawMessage{json.RawMessage(`["deploy","prod"]`), json.RawMessage(`["deploy","prod"]`)}),
+	)
+	expected.RefID = "Anno"
+	require.Equal(t, expected, frame)
+}
+
+func TestAnnotationQueryWithTarget(t *testing.T) {
+	from := time.Unix(1600000000, 0)
+	s := setupGraphite(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/graphite/render", r.URL.Path)
+		_, _ = w.Write([]byte(`[{"target": "deploys Anno", "datapoints": [[1, 1600000100], [0, 1600000200], [null, 1600000300], [2, 1600000400]]}]`))
+	})
+	s.tracer = tracing.InitializeTracerForTest()
+
+	resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{
+			RefID:     "Anno",
+			JSON:      []byte(`{"fromAnnotations": true, "target": "deploys"}`),
+			TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, resp.Responses["Anno"].Error)
+	require.Len(t, resp.Responses["Anno"].Frames, 1)
+
+	// the points without a value or with a value of zero are not annotations
+	frame := resp.Responses["Anno"].Frames[0]
+	expected := data.NewFrame("annotations",
+		data.NewField("time", nil, []time.Time{time.Unix(1600000100, 0).UTC(), time.Unix(1600000400, 0).UTC()}),
+		data.NewField("title", nil, []string{"deploys", "deploys"}),
+		data.NewField("text", nil, []string{"", ""}),
+		data.NewField("tags", nil, []json.RawMessage{json.RawMessage(`[]`), json.RawMessage(`[]`)}),
+	)
+	expected.RefID = "Anno"
+	require.Equal(t, expected, frame)
+}
+
+func TestParseEventTags(t *testing.T) {
+	for raw, expected := range map[string][]string{
+		`null`:         {},
+		`["a", "b c"]`: {"a", "b c"},
+		`"a b"`:        {"a", "b"},
+		`"a b,c"`:      {"a b", "c"},
+		`""`:           {},
+	} {
+		tags, err := parseEventTags(json.RawMessage(raw))
+		require.NoError(t, err)
+		require.Equal(t, expected, tags, raw)
+	}
+}
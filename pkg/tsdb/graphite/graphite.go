@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -31,9 +32,15 @@ import (
 
 var logger = log.New("tsdb.graphite")
 
+var (
+	_ backend.QueryDataHandler    = (*Service)(nil)
+	_ backend.CallResourceHandler = (*Service)(nil)
+)
+
 type Service struct {
-	im     instancemgmt.InstanceManager
-	tracer tracing.Tracer
+	im              instancemgmt.InstanceManager
+	tracer          tracing.Tracer
+	resourceHandler backend.CallResourceHandler
 }
 
 const (
@@ -42,10 +49,12 @@ const (
 )
 
 func ProvideService(httpClientProvider httpclient.Provider, tracer tracing.Tracer) *Service {
-	return &Service{
+	s := &Service{
 		im:     datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 		tracer: tracer,
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 type datasourceInfo struct {
@@ -85,6 +94,12 @@ func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*datasourceInfo, e
 	return &instance, nil
 }
 
+// CallResource handles the metric browsing, tag autocompletion and function listing requests of the query editor,
+// and the metric find requests of template variables.
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	if len(req.Queries) == 0 {
 		return nil, fmt.Errorf("query contains no queries")
@@ -98,8 +113,25 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return nil, err
 	}
 
+	var result = backend.QueryDataResponse{
+		Responses: make(backend.Responses),
+	}
+
+	// annotation queries of events are sent to the events API, all other queries are rendered together
+	queries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		if isEventsQuery(query) {
+			result.Responses[query.RefID] = s.handleEventsQuery(ctx, logger, dsInfo, query)
+		} else {
+			queries = append(queries, query)
+		}
+	}
+	if len(queries) == 0 {
+		return &result, nil
+	}
+
 	// take the first query in the request list, since all query should share the same timerange
-	q := req.Queries[0]
+	q := queries[0]
 
 	/*
 		graphite doc about from and until, with sdk we are getting absolute instead of relative time
@@ -115,16 +147,15 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	}
 
 	// Convert datasource query to graphite target request
-	targetList, emptyQueries, origRefIds, err := s.processQueries(logger, req.Queries)
+	targetList, emptyQueries, origRefIds, err := s.processQueries(logger, queries)
 	if err != nil {
 		return nil, err
 	}
 
-	var result = backend.QueryDataResponse{}
 	if len(emptyQueries) != 0 {
 		logger.Warn("Found query models without targets", "models without targets", strings.Join(emptyQueries, "\n"))
 		// If no queries had a valid target, return an error; otherwise, attempt with the targets we have
-		if len(emptyQueries) == len(req.Queries) {
+		if len(emptyQueries) == len(queries) {
 			return &result, errors.New("no query target found for the alert rule")
 		}
 	}
@@ -168,10 +199,6 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return &result, err
 	}
 
-	result = backend.QueryDataResponse{
-		Responses: make(backend.Responses),
-	}
-
 	for _, f := range frames {
 		if resp, ok := result.Responses[f.Name]; ok {
 			resp.Frames = append(resp.Frames, f)
@@ -183,6 +210,15 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		}
 	}
 
+	// annotation queries with a target return the points of their time series as annotations
+	for _, query := range queries {
+		if isAnnotationQuery(query) {
+			result.Responses[query.RefID] = backend.DataResponse{
+				Frames: data.Frames{seriesToAnnotationFrame(query.RefID, result.Responses[query.RefID].Frames)},
+			}
+		}
+	}
+
 	return &result, nil
 }
 
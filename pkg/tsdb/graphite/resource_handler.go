@@ -0,0 +1,257 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// metricFindLimit is the maximum number of tags and tag values returned for template variables.
+const metricFindLimit = "10000"
+
+var (
+	tagValuesQueryRegex = regexp.MustCompile(`^tag_values\((.*)\)$`)
+	tagsQueryRegex      = regexp.MustCompile(`^tags\((.*)\)$`)
+	expandQueryRegex    = regexp.MustCompile(`^expand\((.*)\)$`)
+)
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/find", s.handleProxy("metrics/find", "query", "from", "until"))
+	mux.HandleFunc("/metrics/expand", s.handleProxy("metrics/expand", "query", "from", "until"))
+	mux.HandleFunc("/tags/autoComplete/tags", s.handleProxy("tags/autoComplete/tags", "expr", "tagPrefix", "limit", "from", "until"))
+	mux.HandleFunc("/tags/autoComplete/values", s.handleProxy("tags/autoComplete/values", "expr", "tag", "valuePrefix", "limit", "from", "until"))
+	mux.HandleFunc("/tags", s.handleProxy("tags", "from", "until"))
+	mux.HandleFunc("/tags/", s.handleTag)
+	mux.HandleFunc("/functions", s.handleProxy("functions"))
+	mux.HandleFunc("/version", s.handleProxy("version"))
+	mux.HandleFunc("/metric-find", s.handleMetricFind)
+	return mux
+}
+
+// handleProxy returns a handler that sends the request to an endpoint of the Graphite API with the given parameters
+// of the request, and writes the response of Graphite.
+func (s *Service) handleProxy(endpoint string, params ...string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		logger := logger.FromContext(ctx)
+		dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(ctx))
+		if err != nil {
+			writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		if err := req.ParseForm(); err != nil {
+			writeResponse(rw, http.StatusBadRequest, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+
+		values := url.Values{}
+		for _, param := range params {
+			if value, ok := req.Form[param]; ok {
+				values[param] = value
+			}
+		}
+
+		body, status, err := s.doResourceRequest(ctx, logger, dsInfo, endpoint, values)
+		if err != nil {
+			writeResponse(rw, http.StatusBadGateway, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		writeResponse(rw, status, string(body))
+	}
+}
+
+// handleTag sends a request for the values of a tag, /tags/<tag>, to Graphite.
+func (s *Service) handleTag(rw http.ResponseWriter, req *http.Request) {
+	tag := strings.TrimPrefix(req.URL.Path, "/tags/")
+	if tag == "" || strings.Contains(tag, "/") {
+		writeResponse(rw, http.StatusNotFound, "not found")
+		return
+	}
+	s.handleProxy("tags/"+tag, "from", "until")(rw, req)
+}
+
+type metricFindValue struct {
+	Text       string `json:"text"`
+	Expandable bool   `json:"expandable"`
+}
+
+// handleMetricFind returns the values of a template variable query, which is either a metric path,
+// expand(<metric path>), tags(<expression>[,<expression>]*) or tag_values(<tag>[,<expression>]*).
+func (s *Service) handleMetricFind(rw http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	logger := logger.FromContext(ctx)
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(ctx))
+	if err != nil {
+		writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		writeResponse(rw, http.StatusBadRequest, fmt.Sprintf("unexpected error %v", err))
+		return
+	}
+
+	values, err := s.metricFind(ctx, logger, dsInfo, req.Form.Get("query"), req.Form.Get("from"), req.Form.Get("until"))
+	if err != nil {
+		writeResponse(rw, http.StatusBadRequest, fmt.Sprintf("unexpected error %v", err))
+		return
+	}
+	body, err := json.Marshal(values)
+	if err != nil {
+		writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponse(rw, http.StatusOK, string(body))
+}
+
+func (s *Service) metricFind(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query, from, until string) ([]metricFindValue, error) {
+	params := url.Values{}
+	if from != "" {
+		params.Set("from", from)
+	}
+	if until != "" {
+		params.Set("until", until)
+	}
+
+	if expressions := functionArguments(tagValuesQueryRegex, query); len(expressions) > 0 {
+		params.Set("tag", expressions[0])
+		params["expr"] = expressions[1:]
+		params.Set("limit", metricFindLimit)
+		var tagValues []string
+		if err := s.getResource(ctx, logger, dsInfo, "tags/autoComplete/values", params, &tagValues); err != nil {
+			return nil, err
+		}
+		return toMetricFindValues(tagValues), nil
+	}
+
+	if expressions := functionArguments(tagsQueryRegex, query); len(expressions) > 0 {
+		params["expr"] = expressions
+		params.Set("limit", metricFindLimit)
+		var tags []string
+		if err := s.getResource(ctx, logger, dsInfo, "tags/autoComplete/tags", params, &tags); err != nil {
+			return nil, err
+		}
+		return toMetricFindValues(tags), nil
+	}
+
+	if match := expandQueryRegex.FindStringSubmatch(query); match != nil {
+		params.Set("query", match[1])
+		var expanded struct {
+			Results []string `json:"results"`
+		}
+		if err := s.getResource(ctx, logger, dsInfo, "metrics/expand", params, &expanded); err != nil {
+			return nil, err
+		}
+		return toMetricFindValues(expanded.Results), nil
+	}
+
+	params.Set("query", query)
+	var metrics []struct {
+		Text string `json:"text"`
+		// Graphite returns 0 or 1, graphite-api returns a boolean.
+		Expandable interface{} `json:"expandable"`
+	}
+	if err := s.getResource(ctx, logger, dsInfo, "metrics/find", params, &metrics); err != nil {
+		return nil, err
+	}
+	values := make([]metricFindValue, 0, len(metrics))
+	for _, metric := range metrics {
+		expandable := false
+		switch v := metric.Expandable.(type) {
+		case bool:
+			expandable = v
+		case float64:
+			expandable = v != 0
+		}
+		values = append(values, metricFindValue{Text: metric.Text, Expandable: expandable})
+	}
+	return values, nil
+}
+
+// functionArguments returns the non-empty arguments of the query if it is a call of the function matched by the regex.
+func functionArguments(regex *regexp.Regexp, query string) []string {
+	match := regex.FindStringSubmatch(query)
+	if match == nil {
+		return nil
+	}
+	arguments := []string{}
+	for _, argument := range strings.Split(match[1], ",") {
+		if argument = strings.TrimSpace(argument); argument != "" {
+			arguments = append(arguments, argument)
+		}
+	}
+	return arguments
+}
+
+func toMetricFindValues(texts []string) []metricFindValue {
+	values := make([]metricFindValue, 0, len(texts))
+	for _, text := range texts {
+		values = append(values, metricFindValue{Text: text})
+	}
+	return values
+}
+
+// getResource sends a GET request to an endpoint of the Graphite API and decodes the JSON response into v.
+func (s *Service) getResource(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, endpoint string, params url.Values, v interface{}) error {
+	body, status, err := s.doResourceRequest(ctx, logger, dsInfo, endpoint, params)
+	if err != nil {
+		return err
+	}
+	if status/100 != 2 {
+		logger.Info("Request failed", "status", status, "body", string(body))
+		return fmt.Errorf("request failed, status: %d", status)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// doResourceRequest sends a GET request to an endpoint of the Graphite API with the HTTP client of the data source,
+// and returns the body and status code of the response.
+func (s *Service) doResourceRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, endpoint string, params url.Values) ([]byte, int, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, 0, err
+	}
+	u.Path = path.Join(u.Path, endpoint)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		logger.Info("Failed to create request", "error", err)
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, res.StatusCode, nil
+}
+
+func writeResponse(rw http.ResponseWriter, code int, msg string) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write([]byte(msg)); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
@@ -0,0 +1,162 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGraphiteInstanceManager struct {
+	dsInfo datasourceInfo
+}
+
+func (f fakeGraphiteInstanceManager) Get(pluginContext backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.dsInfo, nil
+}
+
+func (f fakeGraphiteInstanceManager) Do(pluginContext backend.PluginContext, fn instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+type fakeSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(response *backend.CallResourceResponse) error {
+	s.response = response
+	return nil
+}
+
+// setupGraphite returns a service that sends its requests to a Graphite server with the given handler.
+func setupGraphite(t *testing.T, handler http.HandlerFunc) *Service {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	s := &Service{im: fakeGraphiteInstanceManager{dsInfo: datasourceInfo{HTTPClient: server.Client(), URL: server.URL + "/graphite"}}}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
+}
+
+func callResource(t *testing.T, s *Service, resourceURL string) *backend.CallResourceResponse {
+	t.Helper()
+	u, err := url.Parse(resourceURL)
+	require.NoError(t, err)
+	sender := &fakeSender{}
+	err = s.CallResource(context.Background(), &backend.CallResourceRequest{
+		Method: http.MethodGet,
+		Path:   u.Path,
+		URL:    resourceURL,
+	}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.response)
+	return sender.response
+}
+
+func TestCallResource(t *testing.T) {
+	t.Run("sends requests of the query editor to Graphite", func(t *testing.T) {
+		s := setupGraphite(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/graphite/tags/autoComplete/tags", r.URL.Path)
+			require.Equal(t, []string{"name=a", "dc=eu"}, r.URL.Query()["expr"])
+			require.Equal(t, "d", r.URL.Query().Get("tagPrefix"))
+			require.Empty(t, r.URL.Query().Get("other"))
+			_, _ = w.Write([]byte(`["dc", "host"]`))
+		})
+
+		resp := callResource(t, s, "tags/autoComplete/tags?expr=name%3Da&expr=dc%3Deu&tagPrefix=d&other=x")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["dc", "host"]`, string(resp.Body))
+	})
+
+	t.Run("sends requests for the values of a tag to Graphite", func(t *testing.T) {
+		s := setupGraphite(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/graphite/tags/dc", r.URL.Path)
+			require.Equal(t, "1600000000", r.URL.Query().Get("from"))
+			_, _ = w.Write([]byte(`{"tag": "dc", "values": [{"value": "eu", "count": 1}]}`))
+		})
+
+		resp := callResource(t, s, "tags/dc?from=1600000000")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"tag": "dc", "values": [{"value": "eu", "count": 1}]}`, string(resp.Body))
+
+		resp = callResource(t, s, "tags/dc/values")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+
+	t.Run("returns the status of Graphite", func(t *testing.T) {
+		s := setupGraphite(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		resp := callResource(t, s, "functions")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+	})
+}
+
+func TestMetricFind(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		path     string
+		params   map[string][]string
+		response string
+		expected string
+	}{
+		{
+			name:     "finds metrics",
+			query:    "prod.servers.*",
+			path:     "/graphite/metrics/find",
+			params:   map[string][]string{"query": {"prod.servers.*"}},
+			response: `[{"text": "001", "expandable": 1}, {"text": "cpu", "expandable": false}]`,
+			expected: `[{"text": "001", "expandable": true}, {"text": "cpu", "expandable": false}]`,
+		},
+		{
+			name:     "expands metrics",
+			query:    "expand(prod.servers.*)",
+			path:     "/graphite/metrics/expand",
+			params:   map[string][]string{"query": {"prod.servers.*"}},
+			response: `{"results": ["prod.servers.001"]}`,
+			expected: `[{"text": "prod.servers.001", "expandable": false}]`,
+		},
+		{
+			name:     "finds tags",
+			query:    "tags(name=cpu, dc=eu)",
+			path:     "/graphite/tags/autoComplete/tags",
+			params:   map[string][]string{"expr": {"name=cpu", "dc=eu"}, "limit": {"10000"}},
+			response: `["dc", "host"]`,
+			expected: `[{"text": "dc", "expandable": false}, {"text": "host", "expandable": false}]`,
+		},
+		{
+			name:     "finds tag values",
+			query:    "tag_values(host, name=cpu)",
+			path:     "/graphite/tags/autoComplete/values",
+			params:   map[string][]string{"tag": {"host"}, "expr": {"name=cpu"}, "limit": {"10000"}},
+			response: `["a", "b"]`,
+			expected: `[{"text": "a", "expandable": false}, {"text": "b", "expandable": false}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupGraphite(t, func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tc.path, r.URL.Path)
+				for name, values := range tc.params {
+					require.Equal(t, values, r.URL.Query()[name])
+				}
+				require.Equal(t, "1600000000", r.URL.Query().Get("from"))
+				_, _ = w.Write([]byte(tc.response))
+			})
+
+			resp := callResource(t, s, "metric-find?from=1600000000&query="+url.QueryEscape(tc.query))
+			require.Equal(t, http.StatusOK, resp.Status)
+			require.JSONEq(t, tc.expected, string(resp.Body))
+		})
+	}
+}
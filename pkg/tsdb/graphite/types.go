@@ -1,6 +1,10 @@
 package graphite
 
-import "github.com/grafana/grafana/pkg/tsdb/legacydata"
+import (
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
 
 type TargetResponseDTO struct {
 	Target     string                          `json:"target"`
@@ -8,3 +12,20 @@ type TargetResponseDTO struct {
 	// Graphite <=1.1.7 may return some tags as numbers requiring extra conversion. See https://github.com/grafana/grafana/issues/37614
 	Tags map[string]interface{} `json:"tags"`
 }
+
+// EventDTO is an event returned by the /events/get_data endpoint.
+type EventDTO struct {
+	When float64 `json:"when"`
+	What string  `json:"what"`
+	Data string  `json:"data"`
+	// Tags is a list of tags, or a string of tags separated by commas or spaces in older versions of Graphite.
+	Tags json.RawMessage `json:"tags"`
+}
+
+// eventsQueryModel is the model of an annotation query. Queries without a target return the events with the tags.
+type eventsQueryModel struct {
+	Target          string   `json:"target"`
+	TargetFull      string   `json:"targetFull"`
+	FromAnnotations bool     `json:"fromAnnotations"`
+	Tags            []string `json:"tags"`
+}
@@ -0,0 +1,92 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func isAnnotationQuery(query backend.DataQuery) bool {
+	var model annotationQueryModel
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return false
+	}
+	return model.FromAnnotations
+}
+
+// handleAnnotationQuery returns the annotations of the target metric, or the global annotations,
+// in the time range of the query as an annotation frame.
+func (s *Service) handleAnnotationQuery(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) backend.DataResponse {
+	var model annotationQueryModel
+	if err := json.Unmarshal(query.JSON, &model); err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	if model.Target == "" {
+		return backend.DataResponse{}
+	}
+
+	tsdbQuery := OpenTsdbQuery{
+		Start: query.TimeRange.From.UnixMilli(),
+		End:   query.TimeRange.To.UnixMilli(),
+		Queries: []map[string]interface{}{
+			{"aggregator": "sum", "metric": model.Target},
+		},
+		GlobalAnnotations: true,
+	}
+
+	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	responseData, err := s.readResponse(logger, res)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	var annotations []OpenTsdbAnnotation
+	if len(responseData) > 0 {
+		annotations = responseData[0].Annotations
+		if model.IsGlobal {
+			annotations = responseData[0].GlobalAnnotations
+		}
+	}
+
+	frame := annotationsToFrame(annotations)
+	frame.RefID = query.RefID
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func annotationsToFrame(annotations []OpenTsdbAnnotation) *data.Frame {
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]*time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+	for _, annotation := range annotations {
+		times = append(times, secondsToTime(annotation.StartTime))
+		var timeEnd *time.Time
+		if annotation.EndTime > 0 {
+			t := secondsToTime(annotation.EndTime)
+			timeEnd = &t
+		}
+		timeEnds = append(timeEnds, timeEnd)
+		texts = append(texts, annotation.Description)
+	}
+
+	return data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+	)
+}
+
+func secondsToTime(seconds float64) time.Time {
+	return time.Unix(int64(seconds), 0).UTC()
+}
@@ -0,0 +1,71 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationQuery(t *testing.T) {
+	from := time.Unix(1600000000, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/query", r.URL.Path)
+		var query OpenTsdbQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+		require.Equal(t, int64(1600000000000), query.Start)
+
+		switch query.Queries[0]["metric"] {
+		case "deploys":
+			require.True(t, query.GlobalAnnotations)
+			_, _ = w.Write([]byte(`[{
+				"metric": "deploys",
+				"dps": {},
+				"annotations": [{"description": "Deployed v1", "startTime": 1600000100}],
+				"globalAnnotations": [{"description": "Maintenance", "startTime": 1600000200, "endTime": 1600000500}]
+			}]`))
+		default:
+			require.False(t, query.GlobalAnnotations)
+			_, _ = w.Write([]byte(`[{"metric": "cpu", "dps": {"1600000000": 1}}]`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	service := &Service{im: fakeInstanceManager{dsInfo: &datasourceInfo{HTTPClient: server.Client(), URL: server.URL}}}
+	timeRange := backend.TimeRange{From: from, To: from.Add(time.Hour)}
+
+	resp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{
+			{RefID: "A", JSON: []byte(`{"metric": "cpu", "aggregator": "sum"}`), TimeRange: timeRange},
+			{RefID: "Anno", JSON: []byte(`{"fromAnnotations": true, "target": "deploys"}`), TimeRange: timeRange},
+			{RefID: "Global", JSON: []byte(`{"fromAnnotations": true, "target": "deploys", "isGlobal": true}`), TimeRange: timeRange},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Responses["A"].Frames, 1)
+	require.Equal(t, "cpu", resp.Responses["A"].Frames[0].Name)
+
+	expected := data.NewFrame("annotations",
+		data.NewField("time", nil, []time.Time{time.Unix(1600000100, 0).UTC()}),
+		data.NewField("timeEnd", nil, []*time.Time{nil}),
+		data.NewField("text", nil, []string{"Deployed v1"}),
+	)
+	expected.RefID = "Anno"
+	require.Equal(t, expected, resp.Responses["Anno"].Frames[0])
+
+	end := time.Unix(1600000500, 0).UTC()
+	expected = data.NewFrame("annotations",
+		data.NewField("time", nil, []time.Time{time.Unix(1600000200, 0).UTC()}),
+		data.NewField("timeEnd", nil, []*time.Time{&end}),
+		data.NewField("text", nil, []string{"Maintenance"}),
+	)
+	expected.RefID = "Global"
+	require.Equal(t, expected, resp.Responses["Global"].Frames[0])
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -25,14 +26,30 @@ import (
 
 var logger = log.New("tsdb.opentsdb")
 
+var (
+	_ backend.QueryDataHandler    = (*Service)(nil)
+	_ backend.CallResourceHandler = (*Service)(nil)
+)
+
+// downsampleFillPolicies are the fill policies of downsampling supported by OpenTSDB. The "none" policy
+// is the default and is not sent.
+var downsampleFillPolicies = map[string]bool{
+	"nan":  true,
+	"null": true,
+	"zero": true,
+}
+
 type Service struct {
-	im instancemgmt.InstanceManager
+	im              instancemgmt.InstanceManager
+	resourceHandler backend.CallResourceHandler
 }
 
 func ProvideService(httpClientProvider httpclient.Provider) *Service {
-	return &Service{
+	s := &Service{
 		im: datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 type datasourceInfo struct {
@@ -63,17 +80,42 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 	}
 }
 
+// CallResource handles the metric, tag key and tag value suggestions and lookups of the query editor
+// and of template variables.
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	var tsdbQuery OpenTsdbQuery
 
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	// annotation queries are sent one by one, all other queries are sent together
+	result := backend.NewQueryDataResponse()
+	queries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		if isAnnotationQuery(query) {
+			result.Responses[query.RefID] = s.handleAnnotationQuery(ctx, logger, dsInfo, query)
+		} else {
+			queries = append(queries, query)
+		}
+	}
+	if len(queries) == 0 {
+		return result, nil
+	}
+
+	q := queries[0]
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
-	for _, query := range req.Queries {
+	for _, query := range queries {
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -83,11 +125,6 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(req.PluginContext)
-	if err != nil {
-		return nil, err
-	}
-
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -98,11 +135,14 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return &backend.QueryDataResponse{}, err
 	}
 
-	result, err := s.parseResponse(logger, res)
+	metricsResult, err := s.parseResponse(logger, res)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
 
+	for refID, dataResponse := range metricsResult.Responses {
+		result.Responses[refID] = dataResponse
+	}
 	return result, nil
 }
 
@@ -132,27 +172,10 @@ func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *
 func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend.QueryDataResponse, error) {
 	resp := backend.NewQueryDataResponse()
 
-	body, err := io.ReadAll(res.Body)
+	responseData, err := s.readResponse(logger, res)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			logger.Warn("Failed to close response body", "err", err)
-		}
-	}()
-
-	if res.StatusCode/100 != 2 {
-		logger.Info("Request failed", "status", res.Status, "body", string(body))
-		return nil, fmt.Errorf("request failed, status: %s", res.Status)
-	}
-
-	var responseData []OpenTsdbResponse
-	err = json.Unmarshal(body, &responseData)
-	if err != nil {
-		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
-		return nil, err
-	}
 
 	frames := data.Frames{}
 	for _, val := range responseData {
@@ -180,6 +203,33 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend
 	return resp, nil
 }
 
+// readResponse reads and decodes the response of a request to the /api/query endpoint.
+func (s *Service) readResponse(logger log.Logger, res *http.Response) ([]OpenTsdbResponse, error) {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		return nil, fmt.Errorf("request failed, status: %s", res.Status)
+	}
+
+	var responseData []OpenTsdbResponse
+	err = json.Unmarshal(body, &responseData)
+	if err != nil {
+		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+
+	return responseData, nil
+}
+
 func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 	metric := make(map[string]interface{})
 
@@ -200,9 +250,13 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 			downsampleInterval = "1m" // default value for blank
 		}
 		downsample := downsampleInterval + "-" + model.Get("downsampleAggregator").MustString()
-		if model.Get("downsampleFillPolicy").MustString() != "none" {
-			metric["downsample"] = downsample + "-" + model.Get("downsampleFillPolicy").MustString()
+		fillPolicy := model.Get("downsampleFillPolicy").MustString()
+		if downsampleFillPolicies[fillPolicy] {
+			metric["downsample"] = downsample + "-" + fillPolicy
 		} else {
+			if fillPolicy != "" && fillPolicy != "none" {
+				logger.Warn("Ignoring unknown downsample fill policy", "fillPolicy", fillPolicy)
+			}
 			metric["downsample"] = downsample
 		}
 	}
@@ -230,21 +284,43 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 		metric["rateOptions"] = rateOptions
 	}
 
-	// Setting tags
-	tags, tagsCheck := model.CheckGet("tags")
-	if tagsCheck && len(tags.MustMap()) > 0 {
-		metric["tags"] = tags.MustMap()
+	// Setting filters, which replace the tags since OpenTSDB 2.2
+	if filters := buildFilters(model); len(filters) > 0 {
+		metric["filters"] = filters
+	} else {
+		tags, tagsCheck := model.CheckGet("tags")
+		if tagsCheck && len(tags.MustMap()) > 0 {
+			metric["tags"] = tags.MustMap()
+		}
 	}
 
-	// Setting filters
-	filters, filtersCheck := model.CheckGet("filters")
-	if filtersCheck && len(filters.MustArray()) > 0 {
-		metric["filters"] = filters.MustArray()
+	if model.Get("explicitTags").MustBool() {
+		metric["explicitTags"] = true
 	}
 
 	return metric
 }
 
+// buildFilters returns the valid filters of the query model. Filters without a type, tag key or filter expression are skipped.
+func buildFilters(model *simplejson.Json) []OpenTsdbFilter {
+	filters := make([]OpenTsdbFilter, 0)
+	for i := range model.Get("filters").MustArray() {
+		filterModel := model.Get("filters").GetIndex(i)
+		filter := OpenTsdbFilter{
+			Type:    filterModel.Get("type").MustString(),
+			Tagk:    filterModel.Get("tagk").MustString(),
+			Filter:  filterModel.Get("filter").MustString(),
+			GroupBy: filterModel.Get("groupBy").MustBool(),
+		}
+		if filter.Type == "" || filter.Tagk == "" || filter.Filter == "" {
+			logger.Warn("Skipping incomplete filter", "filter", filter)
+			continue
+		}
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
 func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*datasourceInfo, error) {
 	i, err := s.im.Get(pluginCtx)
 	if err != nil {
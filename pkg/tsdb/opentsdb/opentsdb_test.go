@@ -241,4 +241,48 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(45), metricRateOptions["counterMax"])
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
+
+	t.Run("Build metric with downsampling fill policies", func(t *testing.T) {
+		for fillPolicy, expected := range map[string]string{
+			"":      "1m-avg",
+			"none":  "1m-avg",
+			"nan":   "1m-avg-nan",
+			"zero":  "1m-avg-zero",
+			"other": "1m-avg",
+		} {
+			query := backend.DataQuery{
+				JSON: []byte(`{"metric": "cpu.average.percent", "aggregator": "avg", "downsampleAggregator": "avg", "downsampleFillPolicy": "` + fillPolicy + `"}`),
+			}
+
+			metric := service.buildMetric(query)
+			require.Equal(t, expected, metric["downsample"], fillPolicy)
+		}
+	})
+
+	t.Run("Build metric with filters", func(t *testing.T) {
+		query := backend.DataQuery{
+			JSON: []byte(`
+					{
+						"metric": "cpu.average.percent",
+						"aggregator": "avg",
+						"disableDownsampling": true,
+						"explicitTags": true,
+						"filters": [
+							{"type": "wildcard", "tagk": "host", "filter": "web-*", "groupBy": true},
+							{"type": "literal_or", "tagk": "env", "filter": ""}
+						],
+						"tags": {
+							"env": "prod"
+						}
+					}`,
+			),
+		}
+
+		metric := service.buildMetric(query)
+
+		require.Len(t, metric, 4)
+		require.Nil(t, metric["tags"])
+		require.True(t, metric["explicitTags"].(bool))
+		require.Equal(t, []OpenTsdbFilter{{Type: "wildcard", Tagk: "host", Filter: "web-*", GroupBy: true}}, metric["filters"])
+	})
 }
@@ -0,0 +1,79 @@
+package opentsdb
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/suggest", s.handleProxy("api/suggest", "type", "q", "max"))
+	mux.HandleFunc("/api/search/lookup", s.handleProxy("api/search/lookup", "m", "limit", "useMeta"))
+	return mux
+}
+
+// handleProxy returns a handler that sends the request to an endpoint of the OpenTSDB API with the given parameters
+// of the request, and writes the response of OpenTSDB.
+func (s *Service) handleProxy(endpoint string, params ...string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		logger := logger.FromContext(ctx)
+		dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(ctx))
+		if err != nil {
+			writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+
+		u, err := url.Parse(dsInfo.URL)
+		if err != nil {
+			writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		u.Path = path.Join(u.Path, endpoint)
+		query := url.Values{}
+		for _, param := range params {
+			if value, ok := req.URL.Query()[param]; ok {
+				query[param] = value
+			}
+		}
+		u.RawQuery = query.Encode()
+
+		tsdbReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			logger.Info("Failed to create request", "error", err)
+			writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("failed to create request: %v", err))
+			return
+		}
+
+		res, err := dsInfo.HTTPClient.Do(tsdbReq)
+		if err != nil {
+			writeResponse(rw, http.StatusBadGateway, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				logger.Warn("Failed to close response body", "err", err)
+			}
+		}()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			writeResponse(rw, http.StatusBadGateway, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		writeResponse(rw, res.StatusCode, string(body))
+	}
+}
+
+func writeResponse(rw http.ResponseWriter, code int, msg string) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write([]byte(msg)); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
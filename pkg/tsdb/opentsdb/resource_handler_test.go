@@ -0,0 +1,80 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInstanceManager struct {
+	dsInfo *datasourceInfo
+}
+
+func (f fakeInstanceManager) Get(pluginContext backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.dsInfo, nil
+}
+
+func (f fakeInstanceManager) Do(pluginContext backend.PluginContext, fn instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+type fakeSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(response *backend.CallResourceResponse) error {
+	s.response = response
+	return nil
+}
+
+func TestCallResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/opentsdb/api/suggest":
+			require.Equal(t, "tagk", r.URL.Query().Get("type"))
+			require.Equal(t, "ho", r.URL.Query().Get("q"))
+			require.Equal(t, "1000", r.URL.Query().Get("max"))
+			require.Empty(t, r.URL.Query().Get("other"))
+			_, _ = w.Write([]byte(`["host"]`))
+		case "/opentsdb/api/search/lookup":
+			require.Equal(t, "cpu{host=*}", r.URL.Query().Get("m"))
+			_, _ = w.Write([]byte(`{"results": [{"metric": "cpu", "tags": {"host": "web-1"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	service := &Service{im: fakeInstanceManager{dsInfo: &datasourceInfo{HTTPClient: server.Client(), URL: server.URL + "/opentsdb"}}}
+	service.resourceHandler = httpadapter.New(service.newResourceMux())
+
+	callResource := func(path, rawQuery string) *backend.CallResourceResponse {
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   path,
+			URL:    path + "?" + rawQuery,
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+
+	t.Run("suggests tag keys", func(t *testing.T) {
+		resp := callResource("api/suggest", "type=tagk&q=ho&max=1000&other=x")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["host"]`, string(resp.Body))
+	})
+
+	t.Run("looks up time series", func(t *testing.T) {
+		resp := callResource("api/search/lookup", "m=cpu%7Bhost%3D%2A%7D&limit=1000")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"results": [{"metric": "cpu", "tags": {"host": "web-1"}}]}`, string(resp.Body))
+	})
+}
@@ -4,10 +4,39 @@ type OpenTsdbQuery struct {
 	Start   int64                    `json:"start"`
 	End     int64                    `json:"end"`
 	Queries []map[string]interface{} `json:"queries"`
+	// GlobalAnnotations makes OpenTSDB return the annotations that are not associated with a time series.
+	GlobalAnnotations bool `json:"globalAnnotations,omitempty"`
 }
 
 type OpenTsdbResponse struct {
-	Metric     string             `json:"metric"`
-	Tags       map[string]string  `json:"tags"`
-	DataPoints map[string]float64 `json:"dps"`
+	Metric            string               `json:"metric"`
+	Tags              map[string]string    `json:"tags"`
+	DataPoints        map[string]float64   `json:"dps"`
+	Annotations       []OpenTsdbAnnotation `json:"annotations"`
+	GlobalAnnotations []OpenTsdbAnnotation `json:"globalAnnotations"`
+}
+
+// OpenTsdbAnnotation is an annotation of a time series, or a global annotation.
+type OpenTsdbAnnotation struct {
+	Description string `json:"description"`
+	Notes       string `json:"notes"`
+	// StartTime and EndTime are Unix timestamps in seconds. EndTime is 0 for annotations of a point in time.
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime"`
+}
+
+// OpenTsdbFilter is a filter of the tags of a time series, supported since OpenTSDB 2.2.
+type OpenTsdbFilter struct {
+	Type    string `json:"type"`
+	Tagk    string `json:"tagk"`
+	Filter  string `json:"filter"`
+	GroupBy bool   `json:"groupBy"`
+}
+
+// annotationQueryModel is the model of an annotation query. It returns the annotations of the time series of the
+// target metric, or the global annotations.
+type annotationQueryModel struct {
+	FromAnnotations bool   `json:"fromAnnotations"`
+	Target          string `json:"target"`
+	IsGlobal        bool   `json:"isGlobal"`
 }
@@ -0,0 +1,135 @@
+package sqleng
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+)
+
+// rowCursor reads the rows of a query result one at a time into a frame, and stops reading when the row limit or the
+// response size limit is reached, so that the whole result set of a query is never loaded into memory.
+type rowCursor struct {
+	rows       *sql.Rows
+	converters []sqlutil.Converter
+	// rowLimit is the maximum number of rows, there is no limit if it is less than 0.
+	rowLimit int64
+	// sizeLimit is the maximum size in bytes of the rows, there is no limit if it is 0.
+	sizeLimit int64
+
+	size int64
+	// truncated is true if not all the rows of the query result have been read because a limit was reached.
+	truncated bool
+}
+
+func newRowCursor(rows *sql.Rows, rowLimit, sizeLimit int64, converters ...sqlutil.Converter) *rowCursor {
+	return &rowCursor{
+		rows:       rows,
+		converters: converters,
+		rowLimit:   rowLimit,
+		sizeLimit:  sizeLimit,
+	}
+}
+
+// frame returns a frame with the rows of the cursor. If a limit was reached, a warning notice is attached to the frame.
+func (c *rowCursor) frame() (*data.Frame, error) {
+	types, err := c.rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	names, err := c.rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner, converters, err := sqlutil.MakeScanRow(types, names, c.converters...)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := sqlutil.NewFrame(names, converters...)
+
+	var i int64
+	for c.rows.Next() {
+		if i == c.rowLimit {
+			c.truncate(frame, fmt.Sprintf("Results have been limited to %v because the SQL row limit was reached", c.rowLimit))
+			break
+		}
+
+		r := scanner.NewScannableRow()
+		if err := c.rows.Scan(r...); err != nil {
+			return nil, err
+		}
+
+		if err := sqlutil.Append(frame, r, converters...); err != nil {
+			return nil, err
+		}
+
+		if c.sizeLimit > 0 {
+			c.size += rowSize(frame, int(i))
+			if c.size > c.sizeLimit {
+				frame.DeleteRow(int(i))
+				c.truncate(frame, fmt.Sprintf("Results have been limited to %v rows because the SQL response size limit of %v bytes was reached", i, c.sizeLimit))
+				break
+			}
+		}
+
+		i++
+	}
+
+	if err := c.rows.Err(); err != nil {
+		return frame, err
+	}
+
+	return frame, nil
+}
+
+func (c *rowCursor) truncate(frame *data.Frame, text string) {
+	c.truncated = true
+	frame.AppendNotices(data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     text,
+	})
+}
+
+// rowSize returns the approximate size in bytes of a row of the frame.
+func rowSize(frame *data.Frame, rowIdx int) int64 {
+	var size int64
+	for _, field := range frame.Fields {
+		size += valueSize(field.At(rowIdx))
+	}
+	return size
+}
+
+func valueSize(v interface{}) int64 {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case *string:
+		if v == nil {
+			return 0
+		}
+		return int64(len(*v))
+	case json.RawMessage:
+		return int64(len(v))
+	case *json.RawMessage:
+		if v == nil {
+			return 0
+		}
+		return int64(len(*v))
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return 0
+		}
+		rv = rv.Elem()
+	}
+	return int64(rv.Type().Size())
+}
@@ -0,0 +1,133 @@
+package sqleng
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// seriesQuery returns the numbers from 1 to n, with a text of 10 bytes.
+const seriesQuery = `WITH RECURSIVE series(value) AS (SELECT 1 UNION ALL SELECT value + 1 FROM series WHERE value < %d)
+	SELECT value, 'abcdefghij' AS text FROM series`
+
+func TestRowCursor(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	readFrame := func(t *testing.T, rowLimit, sizeLimit int64) (*data.Frame, *rowCursor) {
+		t.Helper()
+		rows, err := db.Query(fmt.Sprintf(seriesQuery, 100))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = rows.Close() })
+
+		cursor := newRowCursor(rows, rowLimit, sizeLimit)
+		frame, err := cursor.frame()
+		require.NoError(t, err)
+		return frame, cursor
+	}
+
+	t.Run("reads all rows without limits", func(t *testing.T) {
+		frame, cursor := readFrame(t, -1, 0)
+		require.Equal(t, 100, frame.Rows())
+		require.False(t, cursor.truncated)
+		require.Nil(t, frame.Meta)
+	})
+
+	t.Run("stops at the row limit", func(t *testing.T) {
+		frame, cursor := readFrame(t, 10, 0)
+		require.Equal(t, 10, frame.Rows())
+		require.True(t, cursor.truncated)
+		require.Len(t, frame.Meta.Notices, 1)
+		require.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+		require.Equal(t, "Results have been limited to 10 because the SQL row limit was reached", frame.Meta.Notices[0].Text)
+	})
+
+	t.Run("stops at the response size limit", func(t *testing.T) {
+		// The columns are strings in SQLite, so the first 9 rows have 11 bytes and the next ones have 12 bytes.
+		frame, cursor := readFrame(t, -1, 100)
+		require.Equal(t, 9, frame.Rows())
+		require.True(t, cursor.truncated)
+		require.Len(t, frame.Meta.Notices, 1)
+		require.Equal(t, "Results have been limited to 9 rows because the SQL response size limit of 100 bytes was reached", frame.Meta.Notices[0].Text)
+	})
+}
+
+type testMacroEngine struct{}
+
+func (m *testMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	return sql, nil
+}
+
+func TestQueryLimits(t *testing.T) {
+	newHandler := func(t *testing.T, rowLimit int64, jsonData JsonData) *DataSourceHandler {
+		t.Helper()
+		handler, err := NewQueryDataHandler(DataPluginConfiguration{
+			DriverName:       "sqlite3",
+			ConnectionString: ":memory:",
+			DSInfo:           DataSourceInfo{JsonData: jsonData},
+			RowLimit:         rowLimit,
+		}, &testQueryResultTransformer{}, &testMacroEngine{}, log.New("test"))
+		require.NoError(t, err)
+		t.Cleanup(handler.Dispose)
+		return handler
+	}
+
+	query := func(t *testing.T, handler *DataSourceHandler, rawSQL string) backend.DataResponse {
+		t.Helper()
+		queryJSON, err := json.Marshal(map[string]string{"rawSql": rawSQL, "format": "table"})
+		require.NoError(t, err)
+		resp, err := handler.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", JSON: queryJSON, TimeRange: backend.TimeRange{From: time.Now(), To: time.Now()}},
+			},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("the row limit of the data source cannot exceed the row limit of the server", func(t *testing.T) {
+		require.Equal(t, int64(10), newHandler(t, 100, JsonData{RowLimit: 10}).rowLimit)
+		require.Equal(t, int64(100), newHandler(t, 100, JsonData{RowLimit: 1000}).rowLimit)
+		require.Equal(t, int64(100), newHandler(t, 100, JsonData{}).rowLimit)
+	})
+
+	t.Run("truncates the results at the limits of the data source", func(t *testing.T) {
+		handler := newHandler(t, 1000000, JsonData{RowLimit: 20, ResponseSizeLimit: 1000})
+
+		res := query(t, handler, fmt.Sprintf(seriesQuery, 10))
+		require.NoError(t, res.Error)
+		require.Equal(t, 10, res.Frames[0].Rows())
+		require.Empty(t, res.Frames[0].Meta.Notices)
+
+		res = query(t, handler, fmt.Sprintf(seriesQuery, 100))
+		require.NoError(t, res.Error)
+		require.Equal(t, 20, res.Frames[0].Rows())
+		require.Len(t, res.Frames[0].Meta.Notices, 1)
+
+		// The statement is canceled, so the rows that are left are never read.
+		res = query(t, handler, fmt.Sprintf(seriesQuery, 1000000000))
+		require.NoError(t, res.Error)
+		require.Equal(t, 20, res.Frames[0].Rows())
+	})
+
+	t.Run("cancels queries that exceed the timeout of the data source", func(t *testing.T) {
+		handler := newHandler(t, 1000000, JsonData{QueryTimeout: 1})
+
+		start := time.Now()
+		res := query(t, handler, `WITH RECURSIVE series(value) AS (SELECT 1 UNION ALL SELECT value + 1 FROM series WHERE value < 1000000000)
+			SELECT count(*) FROM series`)
+		require.EqualError(t, res.Error, "convert frame from rows error: query timed out after 1s")
+		require.Less(t, time.Since(start), 10*time.Second)
+	})
+}
@@ -67,6 +67,12 @@ type JsonData struct {
 	Servername          string `json:"servername"`
 	TimeInterval        string `json:"timeInterval"`
 	Database            string `json:"database"`
+	// RowLimit is the maximum number of rows returned by a query. It cannot exceed the row limit of the Grafana server.
+	RowLimit int64 `json:"rowLimit"`
+	// ResponseSizeLimit is the maximum size in bytes of the rows returned by a query.
+	ResponseSizeLimit int64 `json:"responseSizeLimit"`
+	// QueryTimeout is the maximum duration in seconds of a query, after which the query is canceled in the database.
+	QueryTimeout int `json:"queryTimeout"`
 }
 
 type DataSourceInfo struct {
@@ -97,6 +103,8 @@ type DataSourceHandler struct {
 	log                    log.Logger
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
+	responseSizeLimit      int64
+	queryTimeout           time.Duration
 }
 type QueryJson struct {
 	RawSql       string  `json:"rawSql"`
@@ -107,6 +115,15 @@ type QueryJson struct {
 	Format       string  `json:"format"`
 }
 
+// timeoutError returns an error saying that the query timed out if the timeout of the query was exceeded,
+// otherwise err.
+func (e *DataSourceHandler) timeoutError(queryContext context.Context, err error) error {
+	if errors.Is(queryContext.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("query timed out after %s", e.queryTimeout)
+	}
+	return err
+}
+
 func (e *DataSourceHandler) TransformQueryError(logger log.Logger, err error) error {
 	// OpError is the error type usually returned by functions in the net
 	// package. It describes the operation, network type, and address of
@@ -135,6 +152,12 @@ func NewQueryDataHandler(config DataPluginConfiguration, queryResultTransformer
 		log:                    log,
 		dsInfo:                 config.DSInfo,
 		rowLimit:               config.RowLimit,
+		responseSizeLimit:      config.DSInfo.JsonData.ResponseSizeLimit,
+		queryTimeout:           time.Duration(config.DSInfo.JsonData.QueryTimeout) * time.Second,
+	}
+
+	if rowLimit := config.DSInfo.JsonData.RowLimit; rowLimit > 0 && (queryDataHandler.rowLimit < 0 || rowLimit < queryDataHandler.rowLimit) {
+		queryDataHandler.rowLimit = rowLimit
 	}
 
 	if len(config.TimeColumnNames) > 0 {
@@ -265,13 +288,22 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		return
 	}
 
+	// Canceling the context of the query cancels the statement in the database.
+	var cancel context.CancelFunc
+	if e.queryTimeout > 0 {
+		queryContext, cancel = context.WithTimeout(queryContext, e.queryTimeout)
+	} else {
+		queryContext, cancel = context.WithCancel(queryContext)
+	}
+	defer cancel()
+
 	session := e.engine.NewSession()
 	defer session.Close()
 	db := session.DB()
 
 	rows, err := db.QueryContext(queryContext, interpolatedQuery)
 	if err != nil {
-		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery)
+		errAppendDebug("db query error", e.timeoutError(queryContext, e.TransformQueryError(logger, err)), interpolatedQuery)
 		return
 	}
 	defer func() {
@@ -288,11 +320,16 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 
 	// Convert row.Rows to dataframe
 	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	cursor := newRowCursor(rows.Rows, e.rowLimit, e.responseSizeLimit, sqlutil.ToConverters(stringConverters...)...)
+	frame, err := cursor.frame()
 	if err != nil {
-		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
+		errAppendDebug("convert frame from rows error", e.timeoutError(queryContext, err), interpolatedQuery)
 		return
 	}
+	if cursor.truncated {
+		// Stop the statement instead of reading the rows that are left when closing them.
+		cancel()
+	}
 
 	if frame.Meta == nil {
 		frame.Meta = &data.FrameMeta{}
@@ -3,6 +3,7 @@ import React, { useCallback, useEffect, useRef } from 'react';
 import { DataSourceApi, QueryEditorProps, SelectableValue } from '@grafana/data';
 import { InlineField, Select } from '@grafana/ui';
 
+import { Anomaly } from './components/Anomaly';
 import { ClassicConditions } from './components/ClassicConditions';
 import { Math } from './components/Math';
 import { Reduce } from './components/Reduce';
@@ -27,6 +28,7 @@ function useExpressionsCache() {
       case ExpressionQueryType.reduce:
       case ExpressionQueryType.resample:
       case ExpressionQueryType.threshold:
+      case ExpressionQueryType.anomaly:
         return expressionCache.current[queryType];
       case ExpressionQueryType.classic:
         return undefined;
@@ -46,6 +48,7 @@ function useExpressionsCache() {
         expressionCache.current.reduce = value;
         expressionCache.current.resample = value;
         expressionCache.current.threshold = value;
+        expressionCache.current.anomaly = value;
         break;
     }
   }, []);
@@ -89,6 +92,9 @@ export function ExpressionQueryEditor(props: Props) {
 
       case ExpressionQueryType.threshold:
         return <Threshold onChange={onChange} query={query} labelWidth={labelWidth} refIds={refIds} />;
+
+      case ExpressionQueryType.anomaly:
+        return <Anomaly onChange={onChange} query={query} labelWidth={labelWidth} refIds={refIds} />;
     }
   };
 
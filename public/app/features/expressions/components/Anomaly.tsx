@@ -0,0 +1,68 @@
+import React, { ChangeEvent, FC } from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
+
+import { AnomalyAlgorithm, anomalyAlgorithms, AnomalyOutput, anomalyOutputs, ExpressionQuery } from '../types';
+
+interface Props {
+  refIds: Array<SelectableValue<string>>;
+  query: ExpressionQuery;
+  labelWidth?: number | 'auto';
+  onChange: (query: ExpressionQuery) => void;
+}
+
+export const Anomaly: FC<Props> = ({ labelWidth = 'auto', onChange, refIds, query }) => {
+  const algorithm = anomalyAlgorithms.find((o) => o.value === query.algorithm);
+  const output = anomalyOutputs.find((o) => o.value === query.output);
+
+  const onRefIdChange = (value: SelectableValue<string>) => {
+    onChange({ ...query, expression: value.value });
+  };
+
+  const onSelectAlgorithm = (value: SelectableValue<AnomalyAlgorithm>) => {
+    onChange({ ...query, algorithm: value.value });
+  };
+
+  const onSelectOutput = (value: SelectableValue<AnomalyOutput>) => {
+    onChange({ ...query, output: value.value });
+  };
+
+  const onSensitivityChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, sensitivity: parseFloat(event.target.value) });
+  };
+
+  const onSeasonChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, season: event.target.value });
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Input" labelWidth={labelWidth}>
+          <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
+        </InlineField>
+        <InlineField label="Algorithm">
+          <Select options={anomalyAlgorithms} value={algorithm} onChange={onSelectAlgorithm} width={20} />
+        </InlineField>
+        <InlineField label="Output">
+          <Select options={anomalyOutputs} value={output} onChange={onSelectOutput} width={20} />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField
+          label="Sensitivity"
+          labelWidth={labelWidth}
+          tooltip="Number of standard deviations a value can deviate from the baseline before it is an outlier"
+        >
+          <Input type="number" onChange={onSensitivityChange} value={query.sensitivity} width={15} />
+        </InlineField>
+        {query.algorithm === AnomalyAlgorithm.Seasonal && (
+          <InlineField label="Season" tooltip="Length of the repeating pattern, e.g. 1h, 1d, 1w">
+            <Input onChange={onSeasonChange} value={query.season} width={15} />
+          </InlineField>
+        )}
+      </InlineFieldRow>
+    </>
+  );
+};
@@ -8,6 +8,7 @@ export enum ExpressionQueryType {
   resample = 'resample',
   classic = 'classic_conditions',
   threshold = 'threshold',
+  anomaly = 'anomaly',
 }
 
 export const gelTypes: Array<SelectableValue<ExpressionQueryType>> = [
@@ -39,6 +40,12 @@ export const gelTypes: Array<SelectableValue<ExpressionQueryType>> = [
     description:
       'Takes one or more time series returned from a query or an expression and checks if any of the series match the threshold condition.',
   },
+  {
+    value: ExpressionQueryType.anomaly,
+    label: 'Anomaly',
+    description:
+      'Takes one or more time series returned from a query or an expression and detects points that deviate from a baseline computed from the series.',
+  },
 ];
 
 export const reducerTypes: Array<SelectableValue<string>> = [
@@ -48,6 +55,17 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: 'median', label: 'Median', description: 'Get the median value' },
+  { value: 'p90', label: '90th percentile', description: 'Get the 90th percentile' },
+  { value: 'p95', label: '95th percentile', description: 'Get the 95th percentile' },
+  { value: 'p99', label: '99th percentile', description: 'Get the 99th percentile' },
+  { value: 'stddev', label: 'Standard deviation', description: 'Get the standard deviation of all values' },
+  { value: ReducerID.variance, label: 'Variance', description: 'Get the variance of all values' },
+  { value: ReducerID.diff, label: 'Difference', description: 'Get the difference between the last and the first value' },
+  { value: ReducerID.range, label: 'Range', description: 'Get the difference between the maximum and the minimum value' },
+  { value: 'count_non_null', label: 'Count non-null', description: 'Get the number of values that are not null' },
+  { value: 'rate', label: 'Rate', description: 'Get the per-second rate of increase' },
 ];
 
 export enum ReducerMode {
@@ -88,6 +106,40 @@ export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
 ];
 
+export enum AnomalyAlgorithm {
+  ZScore = 'zscore',
+  MAD = 'mad',
+  Seasonal = 'seasonal',
+}
+
+export const anomalyAlgorithms: Array<SelectableValue<AnomalyAlgorithm>> = [
+  {
+    value: AnomalyAlgorithm.ZScore,
+    label: 'Z-score',
+    description: 'Baseline is the mean, deviation is the standard deviation of the series',
+  },
+  {
+    value: AnomalyAlgorithm.MAD,
+    label: 'MAD',
+    description: 'Baseline is the median, deviation is the median absolute deviation of the series',
+  },
+  {
+    value: AnomalyAlgorithm.Seasonal,
+    label: 'Seasonal',
+    description: 'Baseline is a Holt-Winters forecast that follows trends and a repeating season',
+  },
+];
+
+export enum AnomalyOutput {
+  Outlier = 'outlier',
+  Band = 'band',
+}
+
+export const anomalyOutputs: Array<SelectableValue<AnomalyOutput>> = [
+  { value: AnomalyOutput.Outlier, label: 'Outliers', description: '1 for anomalous points, 0 otherwise' },
+  { value: AnomalyOutput.Band, label: 'Band', description: 'Lower and upper bound of the expected values' },
+];
+
 export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
   { value: EvalFunction.IsAbove, label: 'Is above' },
   { value: EvalFunction.IsBelow, label: 'Is below' },
@@ -108,6 +160,10 @@ export interface ExpressionQuery extends DataQuery {
   upsampler?: string;
   conditions?: ClassicCondition[];
   settings?: ExpressionQuerySettings;
+  algorithm?: AnomalyAlgorithm;
+  output?: AnomalyOutput;
+  sensitivity?: number;
+  season?: string;
 }
 
 export interface ExpressionQuerySettings {
@@ -1,7 +1,7 @@
 import { ReducerID } from '@grafana/data';
 
 import { EvalFunction } from '../../alerting/state/alertDef';
-import { ClassicCondition, ExpressionQuery, ExpressionQueryType } from '../types';
+import { AnomalyAlgorithm, AnomalyOutput, ClassicCondition, ExpressionQuery, ExpressionQueryType } from '../types';
 
 export const getDefaults = (query: ExpressionQuery) => {
   switch (query.type) {
@@ -35,6 +35,22 @@ export const getDefaults = (query: ExpressionQuery) => {
 
       break;
 
+    case ExpressionQueryType.anomaly:
+      if (!query.algorithm) {
+        query.algorithm = AnomalyAlgorithm.ZScore;
+      }
+
+      if (!query.output) {
+        query.output = AnomalyOutput.Outlier;
+      }
+
+      if (!query.sensitivity) {
+        query.sensitivity = 3;
+      }
+
+      query.reducer = undefined;
+      break;
+
     default:
       query.reducer = undefined;
   }
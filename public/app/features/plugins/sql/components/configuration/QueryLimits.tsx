@@ -0,0 +1,68 @@
+import React from 'react';
+
+import { FieldSet, InlineField } from '@grafana/ui';
+import { NumberInput } from 'app/core/components/OptionsUI/NumberInput';
+
+import { SQLQueryLimits } from '../../types';
+
+interface Props<T> {
+  onPropertyChanged: (property: keyof T, value?: number) => void;
+  labelWidth: number;
+  jsonData: SQLQueryLimits;
+}
+
+export const QueryLimits = <T extends SQLQueryLimits>(props: Props<T>) => {
+  const { onPropertyChanged, labelWidth, jsonData } = props;
+
+  const onJSONDataNumberChanged = (property: keyof SQLQueryLimits) => {
+    return (number?: number) => {
+      if (onPropertyChanged) {
+        onPropertyChanged(property, number);
+      }
+    };
+  };
+
+  return (
+    <FieldSet label="Query limits">
+      <InlineField
+        tooltip={
+          <span>
+            The maximum number of rows returned by a query. Results with more rows are truncated and a warning is shown.
+            It cannot exceed the <code>row_limit</code> of the Grafana server. If set to 0, the limit of the Grafana
+            server is used.
+          </span>
+        }
+        labelWidth={labelWidth}
+        label="Max rows"
+      >
+        <NumberInput
+          placeholder="1000000"
+          value={jsonData.rowLimit}
+          onChange={onJSONDataNumberChanged('rowLimit')}
+        ></NumberInput>
+      </InlineField>
+      <InlineField
+        tooltip="The maximum size in bytes of the rows returned by a query. Results that are larger are truncated and a warning is shown. If set to 0, there is no limit on the size."
+        labelWidth={labelWidth}
+        label="Max size"
+      >
+        <NumberInput
+          placeholder="unlimited"
+          value={jsonData.responseSizeLimit}
+          onChange={onJSONDataNumberChanged('responseSizeLimit')}
+        ></NumberInput>
+      </InlineField>
+      <InlineField
+        tooltip="The maximum amount of time in seconds a query may run. Queries that take longer are canceled in the database. If set to 0, there is no timeout."
+        labelWidth={labelWidth}
+        label="Timeout"
+      >
+        <NumberInput
+          placeholder="unlimited"
+          value={jsonData.queryTimeout}
+          onChange={onJSONDataNumberChanged('queryTimeout')}
+        ></NumberInput>
+      </InlineField>
+    </FieldSet>
+  );
+};
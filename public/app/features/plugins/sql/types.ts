@@ -33,7 +33,13 @@ export interface SQLConnectionLimits {
   connMaxLifetime: number;
 }
 
-export interface SQLOptions extends SQLConnectionLimits, DataSourceJsonData {
+export interface SQLQueryLimits {
+  rowLimit?: number;
+  responseSizeLimit?: number;
+  queryTimeout?: number;
+}
+
+export interface SQLOptions extends SQLConnectionLimits, SQLQueryLimits, DataSourceJsonData {
   tlsAuth: boolean;
   tlsAuthWithCACert: boolean;
   timezone: string;
@@ -1,8 +1,8 @@
-import { isArray } from 'lodash';
-import { of } from 'rxjs';
+import { lastValueFrom, of } from 'rxjs';
 import { createFetchResponse } from 'test/helpers/createFetchResponse';
 
 import { AbstractLabelMatcher, AbstractLabelOperator, getFrameDisplayName, dateTime } from '@grafana/data';
+import { setBackendSrv } from '@grafana/runtime';
 import { backendSrv } from 'app/core/services/backend_srv'; // will use the version in __mocks__
 import { TemplateSrv } from 'app/features/templating/template_srv';
 
@@ -30,6 +30,7 @@ describe('graphiteDatasource', () => {
     jest.clearAllMocks();
 
     const instanceSettings = {
+      id: 1,
       url: '/api/datasources/proxy/1',
       name: 'graphiteProd',
       jsonData: {
@@ -39,6 +40,7 @@ describe('graphiteDatasource', () => {
     const templateSrv = new TemplateSrv();
     const ds = new GraphiteDatasource(instanceSettings, templateSrv);
     ctx = { templateSrv, ds };
+    setBackendSrv(backendSrv);
   });
 
   it('uses default Graphite version when no graphiteVersion is provided', () => {
@@ -183,103 +185,67 @@ describe('graphiteDatasource', () => {
     });
   });
 
-  describe('when fetching Graphite Events as annotations', () => {
-    let results: any;
-    let errorSpy: jest.SpyInstance;
-
-    beforeEach(() => {
-      errorSpy = jest.spyOn(console, 'error').mockImplementation();
-    });
-
-    afterEach(() => {
-      errorSpy.mockRestore();
-    });
+  describe('when querying annotations', () => {
+    let requestOptions: any;
 
-    const options = {
-      targets: [
-        {
-          fromAnnotations: true,
-          tags: ['tag1'],
-          queryType: 'tags',
-        },
-      ],
-
-      range: {
-        from: '2022-06-06T07:03:03.109Z',
-        to: '2022-06-07T07:03:03.109Z',
-        raw: {
-          from: '2022-06-06T07:03:03.109Z',
-          to: '2022-06-07T07:03:03.109Z',
-        },
-      },
+    const range = {
+      from: dateTime(1507222850000),
+      to: dateTime(1507226450000),
+      raw: { from: 'now-1h', to: 'now' },
     };
 
-    describe('and tags are returned as string', () => {
-      const response = [
+    beforeEach(() => {
+      fetchMock.mockImplementation((options: any) => {
+        requestOptions = options;
+        return of(createFetchResponse({ results: {} }));
+      });
+      ctx.templateSrv.init([
         {
-          when: 1507222850,
-          tags: 'tag1 tag2',
-          data: 'some text',
-          id: 2,
-          what: 'Event - deploy',
+          type: 'query',
+          name: 'server',
+          current: { value: ['backend_01', 'backend_02'] },
         },
-      ];
-
-      beforeEach(async () => {
-        fetchMock.mockImplementation((options: any) => {
-          return of(createFetchResponse(response));
-        });
-        await ctx.ds.annotationEvents(options.range, options.targets[0]).then((data: any) => {
-          results = data;
-        });
-      });
-
-      it('should parse the tags string into an array', () => {
-        expect(isArray(results[0].tags)).toEqual(true);
-        expect(results[0].tags.length).toEqual(2);
-        expect(results[0].tags[0]).toEqual('tag1');
-        expect(results[0].tags[1]).toEqual('tag2');
-      });
-    });
-
-    describe('and tags are returned as an array', () => {
-      const response = [
         {
-          when: 1507222850,
-          tags: ['tag1', 'tag2'],
-          data: 'some text',
-          id: 2,
-          what: 'Event - deploy',
+          type: 'query',
+          name: 'tag',
+          current: { value: 'deploy' },
         },
-      ];
-
-      beforeEach(async () => {
-        fetchMock.mockImplementation((options: any) => {
-          return of(createFetchResponse(response));
-        });
+      ]);
+    });
 
-        await ctx.ds.annotationEvents(options.range, options.targets[0]).then((data: any) => {
-          results = data;
-        });
-      });
+    it('should send events queries to the backend', async () => {
+      await lastValueFrom(
+        ctx.ds.query({
+          targets: [{ refId: 'Anno', fromAnnotations: true, queryType: 'tags', tags: ['$tag', 'prod', ''] }],
+          range,
+        } as any)
+      );
 
-      it('should parse the tags string into an array', () => {
-        expect(isArray(results[0].tags)).toEqual(true);
-        expect(results[0].tags.length).toEqual(2);
-        expect(results[0].tags[0]).toEqual('tag1');
-        expect(results[0].tags[1]).toEqual('tag2');
+      expect(requestOptions.url).toBe('/api/ds/query');
+      expect(requestOptions.data.queries).toHaveLength(1);
+      expect(requestOptions.data.queries[0]).toMatchObject({
+        refId: 'Anno',
+        fromAnnotations: true,
+        tags: ['deploy', 'prod'],
       });
+      expect(requestOptions.data.from).toBe('1507222850000');
+      expect(requestOptions.data.to).toBe('1507226450000');
     });
 
-    it('and tags response is invalid', async () => {
-      fetchMock.mockImplementation((options: any) => {
-        return of(createFetchResponse('zzzzzzz'));
-      });
-      await ctx.ds.annotationEvents(options.range, options.targets[0]).then((data: any) => {
-        results = data;
+    it('should send target queries to the backend', async () => {
+      await lastValueFrom(
+        ctx.ds.query({
+          targets: [{ refId: 'Anno', fromAnnotations: true, target: 'servers.$server.deploys' }],
+          range,
+        } as any)
+      );
+
+      expect(requestOptions.url).toBe('/api/ds/query');
+      expect(requestOptions.data.queries[0]).toMatchObject({
+        refId: 'Anno',
+        fromAnnotations: true,
+        target: 'servers.{backend_01,backend_02}.deploys',
       });
-      expect(results).toEqual([]);
-      expect(console.error).toHaveBeenCalledWith(expect.stringMatching(/Unable to get annotations/));
     });
   });
 
@@ -420,81 +386,28 @@ describe('graphiteDatasource', () => {
       });
     });
 
-    it('should generate tags query', () => {
-      ctx.ds.metricFindQuery('tags()').then((data: any) => {
-        results = data;
-      });
-
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
-      expect(requestOptions.params.expr).toEqual([]);
-      expect(results).not.toBe(null);
-    });
-
-    it('should generate tags query with a filter expression', () => {
+    it('should send tags queries to the backend', () => {
       ctx.ds.metricFindQuery('tags(server=backend_01)').then((data: any) => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
-      expect(requestOptions.params.expr).toEqual(['server=backend_01']);
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/metric-find');
+      expect(requestOptions.method).toBe('GET');
+      expect(requestOptions.params).toEqual({ query: 'tags(server=backend_01)' });
       expect(results).not.toBe(null);
     });
 
-    it('should generate tags query for an expression with whitespace after', () => {
-      ctx.ds.metricFindQuery('tags(server=backend_01 )').then((data: any) => {
-        results = data;
-      });
-
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/tags');
-      expect(requestOptions.params.expr).toEqual(['server=backend_01']);
-      expect(results).not.toBe(null);
-    });
-
-    it('should generate tag values query for one tag', () => {
-      ctx.ds.metricFindQuery('tag_values(server)').then((data: any) => {
-        results = data;
-      });
-
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
-      expect(requestOptions.params.tag).toBe('server');
-      expect(requestOptions.params.expr).toEqual([]);
-      expect(results).not.toBe(null);
-    });
-
-    it('should generate tag values query for a tag and expression', () => {
+    it('should send tag values queries to the backend', () => {
       ctx.ds.metricFindQuery('tag_values(server,server=~backend*)').then((data: any) => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
-      expect(requestOptions.params.tag).toBe('server');
-      expect(requestOptions.params.expr).toEqual(['server=~backend*']);
-      expect(results).not.toBe(null);
-    });
-
-    it('should generate tag values query for a tag with whitespace after', () => {
-      ctx.ds.metricFindQuery('tag_values(server )').then((data: any) => {
-        results = data;
-      });
-
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
-      expect(requestOptions.params.tag).toBe('server');
-      expect(requestOptions.params.expr).toEqual([]);
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/metric-find');
+      expect(requestOptions.params).toEqual({ query: 'tag_values(server,server=~backend*)' });
       expect(results).not.toBe(null);
     });
 
-    it('should generate tag values query for a tag and expression with whitespace after', () => {
-      ctx.ds.metricFindQuery('tag_values(server , server=~backend* )').then((data: any) => {
-        results = data;
-      });
-
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/tags/autoComplete/values');
-      expect(requestOptions.params.tag).toBe('server');
-      expect(requestOptions.params.expr).toEqual(['server=~backend*']);
-      expect(results).not.toBe(null);
-    });
-
-    it('/metrics/find should be POST', () => {
+    it('should interpolate the query', () => {
       ctx.templateSrv.init([
         {
           type: 'query',
@@ -505,11 +418,17 @@ describe('graphiteDatasource', () => {
       ctx.ds.metricFindQuery('[[foo]]').then((data: any) => {
         results = data;
       });
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
-      expect(requestOptions.method).toEqual('POST');
-      expect(requestOptions.headers).toHaveProperty('Content-Type', 'application/x-www-form-urlencoded');
-      expect(requestOptions.data).toMatch(`query=bar`);
-      expect(requestOptions).toHaveProperty('params');
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/metric-find');
+      expect(requestOptions.params).toEqual({ query: 'bar' });
+    });
+
+    it('should send the time range', () => {
+      ctx.ds.metricFindQuery('app.*', {
+        range: { from: dateTime(1507222850000), to: dateTime(1507226450000) },
+      });
+
+      // the start is rounded down and the end is rounded up, because the time range of Graphite is exclusive
+      expect(requestOptions.params).toEqual({ query: 'app.*', from: 1507222849, until: 1507226451 });
     });
 
     it('should interpolate $__searchFilter with searchFilter', () => {
@@ -517,9 +436,8 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
-      expect(requestOptions.params).toEqual({});
-      expect(requestOptions.data).toEqual('query=app.backend*');
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/metric-find');
+      expect(requestOptions.params).toEqual({ query: 'app.backend*' });
       expect(results).not.toBe(null);
     });
 
@@ -528,28 +446,33 @@ describe('graphiteDatasource', () => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
-      expect(requestOptions.params).toEqual({});
-      expect(requestOptions.data).toEqual('query=app.*');
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/metric-find');
+      expect(requestOptions.params).toEqual({ query: 'app.*' });
       expect(results).not.toBe(null);
     });
 
+    it('should interpolate $__searchFilter with an empty wildcard in tag queries', () => {
+      ctx.ds.metricFindQuery('tag_values(server, server=~$__searchFilter)', { searchFilter: 'backend' });
+
+      expect(requestOptions.params).toEqual({ query: 'tag_values(server, server=~backend)' });
+    });
+
     it('should request expanded metrics', () => {
       ctx.ds.metricFindQuery('expand(*.servers.*)').then((data: any) => {
         results = data;
       });
 
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/expand');
-      expect(requestOptions.params.query).toBe('*.servers.*');
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/metric-find');
+      expect(requestOptions.params.query).toBe('expand(*.servers.*)');
       expect(results).not.toBe(null);
     });
 
-    it('should fetch from /metrics/find endpoint when queryType is default or query is string', async () => {
+    it('should fetch from the metric-find resource when queryType is default or query is string', async () => {
       const stringQuery = 'query';
       ctx.ds.metricFindQuery(stringQuery).then((data: any) => {
         results = data;
       });
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/metric-find');
       expect(results).not.toBe(null);
 
       const objectQuery = {
@@ -559,7 +482,7 @@ describe('graphiteDatasource', () => {
         datasource: ctx.ds,
       };
       const data = await ctx.ds.metricFindQuery(objectQuery);
-      expect(requestOptions.url).toBe('/api/datasources/proxy/1/metrics/find');
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/metric-find');
       expect(data).toBeTruthy();
     });
 
@@ -656,6 +579,33 @@ describe('graphiteDatasource', () => {
     });
   });
 
+  describe('autocompleting tags', () => {
+    let requestOptions: any;
+
+    beforeEach(() => {
+      fetchMock.mockImplementation((options: any) => {
+        requestOptions = options;
+        return of(createFetchResponse(['backend_01', 'backend_02']));
+      });
+    });
+
+    it('should request the tags from the backend', async () => {
+      const tags = await ctx.ds.getTagsAutoComplete(['server=backend_01 '], 'se', { limit: 5000 });
+
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/tags/autoComplete/tags');
+      expect(requestOptions.params).toEqual({ expr: ['server=backend_01'], tagPrefix: 'se', limit: 5000 });
+      expect(tags).toEqual([{ text: 'backend_01' }, { text: 'backend_02' }]);
+    });
+
+    it('should request the tag values from the backend', async () => {
+      const values = await ctx.ds.getTagValuesAutoComplete([], 'server ', 'back', {});
+
+      expect(requestOptions.url).toBe('/api/datasources/1/resources/tags/autoComplete/values');
+      expect(requestOptions.params).toEqual({ expr: [], tag: 'server', valuePrefix: 'back' });
+      expect(values).toEqual([{ text: 'backend_01' }, { text: 'backend_02' }]);
+    });
+  });
+
   describe('exporting to abstract query', () => {
     async function assertQueryExport(target: string, labelMatchers: AbstractLabelMatcher[]): Promise<void> {
       let abstractQueries = await ctx.ds.exportToAbstractQueries([
@@ -1,5 +1,5 @@
 import { each, indexOf, isArray, isString, map as _map } from 'lodash';
-import { lastValueFrom, merge, Observable, of, OperatorFunction, pipe, throwError } from 'rxjs';
+import { lastValueFrom, Observable, of, throwError } from 'rxjs';
 import { catchError, map } from 'rxjs/operators';
 
 import {
@@ -9,7 +9,6 @@ import {
   DataFrame,
   DataQueryRequest,
   DataQueryResponse,
-  DataSourceApi,
   DataSourceWithQueryExportSupport,
   dateMath,
   dateTime,
@@ -20,7 +19,7 @@ import {
   TimeZone,
   toDataFrame,
 } from '@grafana/data';
-import { getBackendSrv } from '@grafana/runtime';
+import { DataSourceWithBackend, getBackendSrv } from '@grafana/runtime';
 import { isVersionGtOrEq, SemVersion } from 'app/core/utils/version';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 import { getRollupNotice, getRuntimeConsolidationNotice } from 'app/plugins/datasource/graphite/meta';
@@ -66,7 +65,7 @@ function convertGlobToRegEx(text: string): string {
 }
 
 export class GraphiteDatasource
-  extends DataSourceApi<GraphiteQuery, GraphiteOptions, GraphiteQueryImportConfiguration>
+  extends DataSourceWithBackend<GraphiteQuery, GraphiteOptions>
   implements DataSourceWithQueryExportSupport<GraphiteQuery>
 {
   basicAuth: string;
@@ -190,21 +189,9 @@ export class GraphiteDatasource
   }
 
   query(options: DataQueryRequest<GraphiteQuery>): Observable<DataQueryResponse> {
+    // annotations are queried by the backend, which returns the Graphite events or the points of the target
     if (options.targets.some((target: GraphiteQuery) => target.fromAnnotations)) {
-      const streams: Array<Observable<DataQueryResponse>> = [];
-
-      for (const target of options.targets) {
-        streams.push(
-          new Observable((subscriber) => {
-            this.annotationEvents(options.range, target)
-              .then((events) => subscriber.next({ data: [toDataFrame(events)] }))
-              .catch((ex) => subscriber.error(new Error(ex)))
-              .finally(() => subscriber.complete());
-          })
-        );
-      }
-
-      return merge(...streams);
+      return super.query(options);
     }
 
     // handle the queries here
@@ -329,18 +316,6 @@ export class GraphiteDatasource
     return stats;
   }
 
-  parseTags(tagString: string) {
-    let tags: string[] = [];
-    tags = tagString.split(',');
-    if (tags.length === 1) {
-      tags = tagString.split(' ');
-      if (tags[0] === '') {
-        tags = [];
-      }
-    }
-    return tags;
-  }
-
   interpolateVariablesInQueries(queries: GraphiteQuery[], scopedVars: ScopedVars): GraphiteQuery[] {
     let expandedQueries = queries;
     if (queries && queries.length > 0) {
@@ -356,96 +331,12 @@ export class GraphiteDatasource
     return expandedQueries;
   }
 
-  annotationEvents(range: any, target: any) {
-    if (target.target) {
-      // Graphite query as target as annotation
-      const targetAnnotation = this.templateSrv.replace(target.target, {}, 'glob');
-      const graphiteQuery = {
-        range: range,
-        targets: [{ target: targetAnnotation }],
-        format: 'json',
-        maxDataPoints: 100,
-      } as unknown as DataQueryRequest<GraphiteQuery>;
-
-      return lastValueFrom(
-        this.query(graphiteQuery).pipe(
-          map((result: any) => {
-            const list = [];
-
-            for (let i = 0; i < result.data.length; i++) {
-              const target = result.data[i];
-
-              for (let y = 0; y < target.length; y++) {
-                const time = target.fields[0].values.get(y);
-                const value = target.fields[1].values.get(y);
-
-                if (!value) {
-                  continue;
-                }
-
-                list.push({
-                  annotation: target,
-                  time,
-                  title: target.name,
-                });
-              }
-            }
-
-            return list;
-          })
-        )
-      );
-    } else {
-      // Graphite event/tag as annotation
-      const tags = this.templateSrv.replace(target.tags?.join(' '));
-      return this.events({ range: range, tags: tags }).then((results: any) => {
-        const list = [];
-        if (!isArray(results.data)) {
-          console.error(`Unable to get annotations from ${results.url}.`);
-          return [];
-        }
-        for (let i = 0; i < results.data.length; i++) {
-          const e = results.data[i];
-
-          let tags = e.tags;
-          if (isString(e.tags)) {
-            tags = this.parseTags(e.tags);
-          }
-
-          list.push({
-            annotation: target,
-            time: e.when * 1000,
-            title: e.what,
-            tags: tags,
-            text: e.data,
-          });
-        }
-
-        return list;
-      });
-    }
-  }
-
-  events(options: { range: TimeRange; tags: any; timezone?: any }) {
-    try {
-      let tags = '';
-      if (options.tags) {
-        tags = '&tags=' + options.tags;
-      }
-      return lastValueFrom(
-        this.doGraphiteRequest({
-          method: 'GET',
-          url:
-            '/events/get_data?from=' +
-            this.translateTime(options.range.raw.from, false, options.timezone) +
-            '&until=' +
-            this.translateTime(options.range.raw.to, true, options.timezone) +
-            tags,
-        })
-      );
-    } catch (err) {
-      return Promise.reject(err);
-    }
+  applyTemplateVariables(query: GraphiteQuery, scopedVars: ScopedVars): Record<string, any> {
+    return {
+      ...query,
+      target: this.templateSrv.replace(query.target ?? '', scopedVars, 'glob'),
+      tags: query.tags?.map((tag) => this.templateSrv.replace(tag, scopedVars)).filter((tag) => !!tag),
+    };
   }
 
   targetContainsTemplate(target: GraphiteQuery) {
@@ -490,7 +381,7 @@ export class GraphiteDatasource
       return this.requestMetricRender(queryObject, options, queryObject.queryType);
     }
 
-    let query = queryObject.target ?? '';
+    const query = queryObject.target ?? '';
 
     // First attempt to check for tag-related functions (using empty wildcard for interpolation)
     let interpolatedQuery = this.templateSrv.replace(
@@ -498,44 +389,23 @@ export class GraphiteDatasource
       getSearchFilterScopedVar({ query, wildcardChar: '', options: optionalOptions })
     );
 
-    // special handling for tag_values(<tag>[,<expression>]*), this is used for template variables
-    let allParams = interpolatedQuery.match(/^tag_values\((.*)\)$/);
-    let expressions = allParams ? allParams[1].split(',').filter((p) => !!p) : undefined;
-    if (expressions) {
-      options.limit = 10000;
-      return this.getTagValuesAutoComplete(expressions.slice(1), expressions[0], undefined, options);
-    }
-
-    // special handling for tags(<expression>[,<expression>]*), this is used for template variables
-    allParams = interpolatedQuery.match(/^tags\((.*)\)$/);
-    expressions = allParams ? allParams[1].split(',').filter((p) => !!p) : undefined;
-    if (expressions) {
-      options.limit = 10000;
-      return this.getTagsAutoComplete(expressions, undefined, options);
-    }
-
     // If no tag-related query was found, perform metric-based search (using * as the wildcard for interpolation)
-    let useExpand = query.match(/^expand\((.*)\)$/);
-    query = useExpand ? useExpand[1] : query;
-
-    interpolatedQuery = this.templateSrv.replace(
-      query,
-      getSearchFilterScopedVar({ query, wildcardChar: '*', options: optionalOptions })
-    );
+    if (!interpolatedQuery.match(/^(tags|tag_values)\((.*)\)$/)) {
+      interpolatedQuery = this.templateSrv.replace(
+        query,
+        getSearchFilterScopedVar({ query, wildcardChar: '*', options: optionalOptions })
+      );
+    }
 
-    let range;
+    // the backend handles tags(<expression>[,<expression>]*), tag_values(<tag>[,<expression>]*),
+    // expand(<metric path>) and metric paths
+    const params: Record<string, any> = { query: interpolatedQuery };
     if (options.range) {
-      range = {
-        from: this.translateTime(options.range.from, false, options.timezone),
-        until: this.translateTime(options.range.to, true, options.timezone),
-      };
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
 
-    if (useExpand) {
-      return this.requestMetricExpand(interpolatedQuery, options.requestId, range);
-    } else {
-      return this.requestMetricFind(interpolatedQuery, options.requestId, range);
-    }
+    return this.getResource('metric-find', params, { requestId: options.requestId });
   }
 
   /**
@@ -601,203 +471,88 @@ export class GraphiteDatasource
     return Promise.resolve(result);
   }
 
-  /**
-   * Search for metrics matching giving pattern using /metrics/find endpoint. It will
-   * return all possible values at the last level of the query, for example:
-   *
-   * metrics: prod.servers.001.cpu, prod.servers.002.cpu
-   * query: *.servers.*
-   * result: 001, 002
-   *
-   * For more complex searches use requestMetricExpand
-   */
-  private requestMetricFind(
-    query: string,
-    requestId: string,
-    range?: { from: any; until: any }
-  ): Promise<MetricFindValue[]> {
-    const httpOptions: any = {
-      method: 'POST',
-      url: '/metrics/find',
-      params: {},
-      data: `query=${query}`,
-      headers: {
-        'Content-Type': 'application/x-www-form-urlencoded',
-      },
-      // for cancellations
-      requestId: requestId,
-    };
-
-    if (range) {
-      httpOptions.params.from = range.from;
-      httpOptions.params.until = range.until;
-    }
-
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          return _map(results.data, (metric) => {
-            return {
-              text: metric.text,
-              expandable: metric.expandable ? true : false,
-            };
-          });
-        })
-      )
-    );
-  }
-
-  /**
-   * Search for metrics matching giving pattern using /metrics/expand endpoint.
-   * The result will contain all metrics (with full name) matching provided query.
-   * It's a more flexible version of /metrics/find endpoint (@see requestMetricFind)
-   */
-  private requestMetricExpand(
-    query: string,
-    requestId: string,
-    range?: { from: any; until: any }
-  ): Promise<MetricFindValue[]> {
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/metrics/expand',
-      params: { query },
-      headers: {
-        'Content-Type': 'application/x-www-form-urlencoded',
-      },
-      // for cancellations
-      requestId,
-    };
-
-    if (range) {
-      httpOptions.params.from = range.from;
-      httpOptions.params.until = range.until;
-    }
-
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          return _map(results.data.results, (metric) => {
-            return {
-              text: metric,
-              expandable: false,
-            };
-          });
-        })
-      )
-    );
-  }
-
   getTags(optionalOptions: any) {
     const options = optionalOptions || {};
-
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/tags',
-      // for cancellations
-      requestId: options.requestId,
-    };
+    const params: Record<string, any> = {};
 
     if (options.range) {
-      httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
-      httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
 
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          return _map(results.data, (tag) => {
-            return {
-              text: tag.tag,
-              id: tag.id,
-            };
-          });
-        })
-      )
-    );
+    return this.getResource('tags', params, { requestId: options.requestId }).then((results: any) => {
+      return _map(results, (tag) => {
+        return {
+          text: tag.tag,
+          id: tag.id,
+        };
+      });
+    });
   }
 
   getTagValues(options: any = {}) {
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/tags/' + this.templateSrv.replace(options.key),
-      // for cancellations
-      requestId: options.requestId,
-    };
+    const params: Record<string, any> = {};
 
     if (options.range) {
-      httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
-      httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
 
-    return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
-        map((results: any) => {
-          if (results.data && results.data.values) {
-            return _map(results.data.values, (value) => {
-              return {
-                text: value.value,
-                id: value.id,
-              };
-            });
-          } else {
-            return [];
-          }
-        })
-      )
-    );
+    return this.getResource('tags/' + encodeURIComponent(this.templateSrv.replace(options.key)), params, {
+      requestId: options.requestId,
+    }).then((results: any) => {
+      if (results && results.values) {
+        return _map(results.values, (value) => {
+          return {
+            text: value.value,
+            id: value.id,
+          };
+        });
+      } else {
+        return [];
+      }
+    });
   }
 
   getTagsAutoComplete(expressions: any[], tagPrefix: any, optionalOptions?: any) {
     const options = optionalOptions || {};
 
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/tags/autoComplete/tags',
-      params: {
-        expr: _map(expressions, (expression) => this.templateSrv.replace((expression || '').trim())),
-      },
-      // for cancellations
-      requestId: options.requestId,
+    const params: Record<string, any> = {
+      expr: _map(expressions, (expression) => this.templateSrv.replace((expression || '').trim())),
     };
 
     if (tagPrefix) {
-      httpOptions.params.tagPrefix = tagPrefix;
+      params.tagPrefix = tagPrefix;
     }
     if (options.limit) {
-      httpOptions.params.limit = options.limit;
+      params.limit = options.limit;
     }
     if (options.range) {
-      httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
-      httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    return this.getResource('tags/autoComplete/tags', params, { requestId: options.requestId }).then(mapToTags);
   }
 
   getTagValuesAutoComplete(expressions: any[], tag: any, valuePrefix: any, optionalOptions: any) {
     const options = optionalOptions || {};
 
-    const httpOptions: any = {
-      method: 'GET',
-      url: '/tags/autoComplete/values',
-      params: {
-        expr: _map(expressions, (expression) => this.templateSrv.replace((expression || '').trim())),
-        tag: this.templateSrv.replace((tag || '').trim()),
-      },
-      // for cancellations
-      requestId: options.requestId,
+    const params: Record<string, any> = {
+      expr: _map(expressions, (expression) => this.templateSrv.replace((expression || '').trim())),
+      tag: this.templateSrv.replace((tag || '').trim()),
     };
 
     if (valuePrefix) {
-      httpOptions.params.valuePrefix = valuePrefix;
+      params.valuePrefix = valuePrefix;
     }
     if (options.limit) {
-      httpOptions.params.limit = options.limit;
+      params.limit = options.limit;
     }
     if (options.range) {
-      httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
-      httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
+      params.from = this.translateTime(options.range.from, false, options.timezone);
+      params.until = this.translateTime(options.range.to, true, options.timezone);
     }
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    return this.getResource('tags/autoComplete/values', params, { requestId: options.requestId }).then(mapToTags);
   }
 
   getVersion(optionalOptions: any) {
@@ -1003,16 +758,12 @@ function supportsFunctionIndex(version: string): boolean {
   return isVersionGtOrEq(version, '1.1');
 }
 
-function mapToTags(): OperatorFunction<any, Array<{ text: string }>> {
-  return pipe(
-    map((results: any) => {
-      if (results.data) {
-        return _map(results.data, (value) => {
-          return { text: value };
-        });
-      } else {
-        return [];
-      }
-    })
-  );
+function mapToTags(results: any): Array<{ text: string }> {
+  if (results) {
+    return _map(results, (value) => {
+      return { text: value };
+    });
+  } else {
+    return [];
+  }
 }
@@ -9,6 +9,7 @@ import {
 } from '@grafana/data';
 import { Alert, FieldSet, InlineField, InlineFieldRow, InlineSwitch, Input, Link, SecretInput } from '@grafana/ui';
 import { ConnectionLimits } from 'app/features/plugins/sql/components/configuration/ConnectionLimits';
+import { QueryLimits } from 'app/features/plugins/sql/components/configuration/QueryLimits';
 import { TLSSecretsConfig } from 'app/features/plugins/sql/components/configuration/TLSSecretsConfig';
 import { useMigrateDatabaseField } from 'app/features/plugins/sql/components/configuration/useMigrateDatabaseField';
 
@@ -150,6 +151,14 @@ export const ConfigurationEditor = (props: DataSourcePluginOptionsEditorProps<My
         }}
       ></ConnectionLimits>
 
+      <QueryLimits
+        labelWidth={shortWidth}
+        jsonData={jsonData}
+        onPropertyChanged={(property, value) => {
+          updateDatasourcePluginJsonDataOption(props, property, value);
+        }}
+      ></QueryLimits>
+
       <FieldSet label="MySQL details">
         <InlineField
           tooltip={
@@ -13,26 +13,18 @@ import {
   map as _map,
   toPairs,
 } from 'lodash';
-import { lastValueFrom, merge, Observable, of } from 'rxjs';
+import { from, lastValueFrom, Observable, of } from 'rxjs';
 import { catchError, map } from 'rxjs/operators';
 
-import {
-  AnnotationEvent,
-  DataQueryRequest,
-  DataQueryResponse,
-  DataSourceApi,
-  dateMath,
-  ScopedVars,
-  toDataFrame,
-} from '@grafana/data';
-import { FetchResponse, getBackendSrv } from '@grafana/runtime';
+import { DataQueryRequest, DataQueryResponse, dateMath, ScopedVars } from '@grafana/data';
+import { DataSourceWithBackend, FetchResponse, getBackendSrv } from '@grafana/runtime';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 
 import { AnnotationEditor } from './components/AnnotationEditor';
 import { prepareAnnotation } from './migrations';
 import { OpenTsdbFilter, OpenTsdbOptions, OpenTsdbQuery } from './types';
 
-export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenTsdbOptions> {
+export default class OpenTsDatasource extends DataSourceWithBackend<OpenTsdbQuery, OpenTsdbOptions> {
   type: any;
   url: any;
   name: any;
@@ -69,29 +61,9 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
 
   // Called once per panel (graph)
   query(options: DataQueryRequest<OpenTsdbQuery>): Observable<DataQueryResponse> {
-    // migrate annotations
+    // annotations are queried by the backend
     if (options.targets.some((target: OpenTsdbQuery) => target.fromAnnotations)) {
-      const streams: Array<Observable<DataQueryResponse>> = [];
-
-      for (const annotation of options.targets) {
-        if (annotation.target) {
-          streams.push(
-            new Observable((subscriber) => {
-              this.annotationEvent(options, annotation)
-                .then((events) => subscriber.next({ data: [toDataFrame(events)] }))
-                .catch((ex) => {
-                  // grafana fetch throws the error so for annotation consistency among datasources
-                  // we return an empty array which displays as 'no events found'
-                  // in the annnotation editor
-                  return subscriber.next({ data: [toDataFrame([])] });
-                })
-                .finally(() => subscriber.complete());
-            })
-          );
-        }
-      }
-
-      return merge(...streams);
+      return super.query(options);
     }
 
     const start = this.convertToTSDBTime(options.range.raw.from, false, options.timezone);
@@ -156,42 +128,6 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     );
   }
 
-  annotationEvent(options: DataQueryRequest, annotation: OpenTsdbQuery): Promise<AnnotationEvent[]> {
-    const start = this.convertToTSDBTime(options.range.raw.from, false, options.timezone);
-    const end = this.convertToTSDBTime(options.range.raw.to, true, options.timezone);
-    const qs = [];
-    const eventList: any[] = [];
-
-    qs.push({ aggregator: 'sum', metric: annotation.target });
-
-    const queries = compact(qs);
-
-    return lastValueFrom(
-      this.performTimeSeriesQuery(queries, start, end).pipe(
-        map((results) => {
-          if (results.data[0]) {
-            let annotationObject = results.data[0].annotations;
-            if (annotation.isGlobal) {
-              annotationObject = results.data[0].globalAnnotations;
-            }
-            if (annotationObject) {
-              each(annotationObject, (ann) => {
-                const event = {
-                  text: ann.description,
-                  time: Math.floor(ann.startTime) * 1000,
-                  annotation: annotation,
-                };
-
-                eventList.push(event);
-              });
-            }
-          }
-          return eventList;
-        })
-      )
-    );
-  }
-
   targetContainsTemplate(target: any) {
     if (target.filters && target.filters.length > 0) {
       for (let i = 0; i < target.filters.length; i++) {
@@ -257,11 +193,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   _performSuggestQuery(query: string, type: string): Observable<any> {
-    return this._get('/api/suggest', { type, q: query, max: this.lookupLimit }).pipe(
-      map((result: any) => {
-        return result.data;
-      })
-    );
+    return from(this.getResource('api/suggest', { type, q: query, max: this.lookupLimit }));
   }
 
   _performMetricKeyValueLookup(metric: string, keys: any): Observable<any[]> {
@@ -281,9 +213,9 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
 
     const m = metric + '{' + keysQuery + '}';
 
-    return this._get('/api/search/lookup', { m: m, limit: this.lookupLimit }).pipe(
+    return from(this.getResource('api/search/lookup', { m: m, limit: this.lookupLimit })).pipe(
       map((result: any) => {
-        result = result.data.results;
+        result = result.results;
         const tagvs: any[] = [];
         each(result, (r) => {
           if (tagvs.indexOf(r.tags[key]) === -1) {
@@ -300,9 +232,9 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
       return of([]);
     }
 
-    return this._get('/api/search/lookup', { m: metric, limit: 1000 }).pipe(
+    return from(this.getResource('api/search/lookup', { m: metric, limit: 1000 })).pipe(
       map((result: any) => {
-        result = result.data.results;
+        result = result.results;
         const tagks: any[] = [];
         each(result, (r) => {
           each(r.tags, (tagv, tagk) => {
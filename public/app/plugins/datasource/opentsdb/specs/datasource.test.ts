@@ -1,6 +1,7 @@
-import { of } from 'rxjs';
+import { lastValueFrom, of } from 'rxjs';
 
 import { DataQueryRequest, dateTime } from '@grafana/data';
+import { setBackendSrv } from '@grafana/runtime';
 import { backendSrv } from 'app/core/services/backend_srv'; // will use the version in __mocks__
 
 import { createFetchResponse } from '../../../../../test/helpers/createFetchResponse';
@@ -25,10 +26,11 @@ const metricFindQueryData = [
 describe('opentsdb', () => {
   function getTestcontext({ data = metricFindQueryData }: { data?: any } = {}) {
     jest.clearAllMocks();
+    setBackendSrv(backendSrv);
     const fetchMock = jest.spyOn(backendSrv, 'fetch');
     fetchMock.mockImplementation(() => of(createFetchResponse(data)));
 
-    const instanceSettings = { url: '', jsonData: { tsdbVersion: 1 } };
+    const instanceSettings = { id: 1, url: '', jsonData: { tsdbVersion: 1 } };
     const replace = jest.fn((value) => value);
     const templateSrv: any = {
       replace,
@@ -46,7 +48,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('metrics(pew)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/1/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('metrics');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('pew');
       expect(results).not.toBe(null);
@@ -58,7 +60,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_names(cpu)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/1/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu');
       expect(results).not.toBe(null);
     });
@@ -69,7 +71,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/1/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*}');
       expect(results).not.toBe(null);
     });
@@ -80,7 +82,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=$env)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/1/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*,env=$env}');
       expect(results).not.toBe(null);
     });
@@ -91,7 +93,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=$env, region=$region)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/search/lookup');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/1/resources/api/search/lookup');
       expect(fetchMock.mock.calls[0][0].params?.m).toBe('cpu{hostname=*,env=$env,region=$region}');
       expect(results).not.toBe(null);
     });
@@ -102,7 +104,7 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('suggest_tagk(foo)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/1/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('tagk');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('foo');
       expect(results).not.toBe(null);
@@ -114,13 +116,33 @@ describe('opentsdb', () => {
       const results = await ds.metricFindQuery('suggest_tagv(bar)');
 
       expect(fetchMock).toHaveBeenCalledTimes(1);
-      expect(fetchMock.mock.calls[0][0].url).toBe('/api/suggest');
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/1/resources/api/suggest');
       expect(fetchMock.mock.calls[0][0].params?.type).toBe('tagv');
       expect(fetchMock.mock.calls[0][0].params?.q).toBe('bar');
       expect(results).not.toBe(null);
     });
   });
 
+  describe('When querying annotations', () => {
+    it('should query the annotations through the backend', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: { results: { A: { frames: [] } } } });
+      const options = {
+        range: {
+          from: dateTime(1432288354),
+          to: dateTime(1432288401),
+          raw: { from: 'now-6h', to: 'now' },
+        },
+        targets: [{ refId: 'A', target: 'deploys', fromAnnotations: true }],
+      } as unknown as DataQueryRequest<OpenTsdbQuery>;
+
+      await lastValueFrom(ds.query(options));
+
+      expect(fetchMock).toHaveBeenCalledTimes(1);
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/ds/query');
+      expect(fetchMock.mock.calls[0][0].data.queries[0]).toMatchObject({ target: 'deploys', fromAnnotations: true });
+    });
+  });
+
   describe('When interpolating variables', () => {
     it('should return an empty array if no queries are provided', () => {
       const { ds } = getTestcontext();